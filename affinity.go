@@ -0,0 +1,63 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// affinityTimeLayouts are layouts of textual timestamps stored by SQLite and produced by its date and time functions
+var affinityTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// julianDayUnixEpoch is the Julian day number of the Unix epoch
+const julianDayUnixEpoch = 2440587.5
+
+// WithTypeAffinity adapts the propagation to databases with dynamic typing such as SQLite, where the declared type
+// of the column is only its affinity, expressions have no declared type at all and each value comes back
+// with its own storage class. Numeric text is stored into fields of number types the same as with WithLenientNumbers
+// and fields of time.Time type accept textual timestamps in UTC unless the offset is specified,
+// integer Unix time in seconds and real Julian day numbers, all the forms SQLite date and time functions work with.
+func WithTypeAffinity() Option {
+	return func(o *options) {
+		o.compile.typeAffinity = true
+		o.compile.lenientNumbers = true
+	}
+}
+
+// convertAffinityTime stores time value of any storage class into the field of time.Time type
+func convertAffinityTime(src interface{}, dst reflect.Value) error {
+	switch value := src.(type) {
+	case nil:
+		return fmt.Errorf("converting NULL to %v is unsupported", dst.Type())
+	case time.Time:
+		dst.Set(reflect.ValueOf(value))
+		return nil
+	case int64:
+		dst.Set(reflect.ValueOf(time.Unix(value, 0).UTC()))
+		return nil
+	case float64:
+		sec, frac := math.Modf((value - julianDayUnixEpoch) * 86400)
+		dst.Set(reflect.ValueOf(time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC()))
+		return nil
+	}
+
+	text := strings.TrimSuffix(strings.TrimSpace(asString(src)), "Z")
+	for _, layout := range affinityTimeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return &ParseError{Value: asString(src), Type: dst.Type(), Err: errors.New("unknown time format")}
+}
@@ -0,0 +1,42 @@
+package rowconv
+
+import "reflect"
+
+// WithAllocationBlocks allocates the structs of the elements in blocks of size at once instead of one per row,
+// which reduces the pressure on the allocator and the garbage collector for the scans of millions of rows.
+// The block stays in memory as long as any of its elements is referenced, so it suits the propagations that keep
+// all the elements, e.g. into the slices of pointers, rather than the ones that retain only a few of them.
+// Structs released with Release are taken first if StructPooling is enabled. Non-positive size disables blocks.
+func WithAllocationBlocks(size int) Option {
+	return func(o *options) {
+		if size < 0 {
+			size = 0
+		}
+		o.compile.allocationBlock = size
+	}
+}
+
+// blockProvider returns struct provider that hands out the elements of forType from the blocks of size structs,
+// it must be created for each propagation as the current block isn't safe for concurrent use.
+// The structs are initialized by initializer the same way as the ones of the provider of forType.
+func blockProvider(forType reflect.Type, size int, initializer structInitializer) structProvider {
+	actualType, ptrDepth, _ := unwrapPtrStructType(forType)
+	blockType := reflect.SliceOf(actualType)
+	var block reflect.Value
+	next := size
+	return func() (reflect.Value, error) {
+		if next == size {
+			block = reflect.MakeSlice(blockType, size, size)
+			next = 0
+		}
+		holderValue := block.Index(next)
+		next++
+		if err := initializer(holderValue); err != nil {
+			return reflect.Value{}, err
+		}
+		for ptrNesting := ptrDepth; ptrNesting > 0; ptrNesting-- {
+			holderValue = holderValue.Addr()
+		}
+		return holderValue, nil
+	}
+}
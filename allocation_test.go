@@ -0,0 +1,42 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateWithAllocationBlocks(t *testing.T) {
+	type nested struct {
+		Col2 *string
+	}
+	type valStruct struct {
+		Id     int
+		Col1   string
+		Nested *nested
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'x'), (2, 'b', NULL), (3, 'c', 'z')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []*valStruct
+	if err := Propagate(&valStructs, rows, WithAllocationBlocks(2)); err != nil {
+		t.Fatal(err)
+	}
+	x, z := "x", "z"
+	exp := []*valStruct{{Id: 1, Col1: "a", Nested: &nested{Col2: &x}}, {Id: 2, Col1: "b", Nested: &nested{}}, {Id: 3, Col1: "c", Nested: &nested{Col2: &z}}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+
+	structSize := reflect.TypeOf(valStruct{}).Size()
+	if reflect.ValueOf(valStructs[1]).Pointer()-reflect.ValueOf(valStructs[0]).Pointer() != structSize {
+		t.Error("elements expected to be allocated in the same block")
+	}
+	valStructs[0].Id = 10
+	if valStructs[1].Id != 2 || valStructs[2].Id != 3 {
+		t.Error("elements expected to be independent")
+	}
+}
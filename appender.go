@@ -0,0 +1,34 @@
+package rowconv
+
+import (
+	"reflect"
+)
+
+// Appender is implemented by custom collections, e.g. ring buffers, ordered sets or typed lists,
+// that receive values mapped from rows directly, see NewAppenderSink and TypedAppender
+type Appender interface {
+	// Append accepts the value mapped from the next row
+	Append(v interface{}) error
+}
+
+// TypedAppender is Appender that reports the type of the values it accepts, so it can be passed to Propagate as dst
+type TypedAppender interface {
+	Appender
+	// ElementType returns the type of the values passed to Append, e.g. `reflect.TypeOf(User{})`
+	ElementType() reflect.Type
+}
+
+type appenderSink struct {
+	appender Appender
+}
+
+// NewAppenderSink creates sink that appends values to the appender
+func NewAppenderSink(appender Appender) Sink {
+	return &appenderSink{appender: appender}
+}
+
+func (as *appenderSink) Add(v reflect.Value) error {
+	return as.appender.Append(v.Interface())
+}
+
+func (as *appenderSink) Flush() error { return nil }
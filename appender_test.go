@@ -0,0 +1,54 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lastN keeps only the last n identifiers appended to it
+type lastN struct {
+	n   int
+	ids []int
+}
+
+func (l *lastN) Append(v interface{}) error {
+	l.ids = append(l.ids, v.(int))
+	if len(l.ids) > l.n {
+		l.ids = l.ids[1:]
+	}
+	return nil
+}
+
+func (l *lastN) ElementType() reflect.Type { return reflect.TypeOf(0) }
+
+func TestPropagateIntoAppender(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"SELECT id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	last := &lastN{n: 2}
+	if err := Propagate(last, rows); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(last.ids, []int{2, 3}) {
+		t.Errorf("unexpeted results of propagation: %v", last.ids)
+	}
+}
+
+func TestPropagateIntoAppenderSink(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"SELECT id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	last := &lastN{n: 1}
+	if err := PropagateSink(NewAppenderSink(last), reflect.TypeOf(0), rows); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(last.ids, []int{3}) {
+		t.Errorf("unexpeted results of propagation: %v", last.ids)
+	}
+}
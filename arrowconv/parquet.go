@@ -0,0 +1,69 @@
+package arrowconv
+
+import (
+	"database/sql"
+	"io"
+	"reflect"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/pavelmemory/rowconv"
+)
+
+// WriteParquet writes rows into w as a Parquet file of the schema, e.g. for database to data lake extracts.
+// The schema is derived from the columns with SchemaFromColumns if it is nil. Rows are converted into record batches
+// the same way as by Propagate, each batch of at most batchSize rows is written as a row group.
+// WriteParquetStruct should be used to write rows mapped by rowconv. The file is complete only if no error is returned.
+func WriteParquet(w io.Writer, rows *sql.Rows, schema *arrow.Schema, batchSize int) error {
+	return WriteParquetWithProperties(w, rows, schema, batchSize, parquet.NewWriterProperties())
+}
+
+// WriteParquetWithProperties is the same as WriteParquet, but the file is written with props, e.g. to set compression
+func WriteParquetWithProperties(w io.Writer, rows *sql.Rows, schema *arrow.Schema, batchSize int, props *parquet.WriterProperties) error {
+	if schema == nil {
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			return err
+		}
+		schema = SchemaFromColumns(columnTypes)
+	}
+
+	writer, err := pqarrow.NewFileWriter(schema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	if err := Propagate(rows, schema, batchSize, writer.Write); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// WriteParquetStruct writes rows into w as a Parquet file of the schema derived from t with SchemaFromStruct.
+// Rows are mapped into values of t by rowconv with opts and converted into record batches the same way
+// as by PropagateStruct, each batch of at most batchSize rows is written as a row group.
+// The file is complete only if no error is returned.
+func WriteParquetStruct(w io.Writer, rows *sql.Rows, t reflect.Type, batchSize int, opts ...rowconv.Option) error {
+	return WriteParquetStructWith(rowconv.Default(), w, rows, t, batchSize, parquet.NewWriterProperties(), opts...)
+}
+
+// WriteParquetStructWith is the same as WriteParquetStruct, but rows are mapped by the mapper m
+// and the file is written with props
+func WriteParquetStructWith(m *rowconv.Mapper, w io.Writer, rows *sql.Rows, t reflect.Type, batchSize int, props *parquet.WriterProperties, opts ...rowconv.Option) error {
+	schema, err := SchemaFromStructWith(m, t)
+	if err != nil {
+		return err
+	}
+
+	writer, err := pqarrow.NewFileWriter(schema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	if err := PropagateStructWith(m, memory.DefaultAllocator, rows, t, batchSize, writer.Write, opts...); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,126 @@
+//go:build sqlite
+// +build sqlite
+
+package arrowconv
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pavelmemory/rowconv"
+)
+
+func queryRows(t *testing.T) *sql.Rows {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE extract(id INTEGER, city VARCHAR(20), company VARCHAR(20))`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO extract VALUES (1, 'Berlin  ', 'ACME'), (2, 'Paris', NULL), (3, 'Rome', 'Initech')`); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.QueryContext(context.Background(), `SELECT id, city, company FROM extract ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func readParquet(t *testing.T, data []byte) arrow.Table {
+	t.Helper()
+	reader, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := fileReader.ReadTable(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(table.Release)
+	return table
+}
+
+// columnValues returns values of the column of the table as strings, "(null)" stands for null
+func columnValues(t *testing.T, table arrow.Table, name string) []string {
+	t.Helper()
+	indices := table.Schema().FieldIndices(name)
+	if len(indices) != 1 {
+		t.Fatalf("no column %s in %v", name, table.Schema())
+	}
+	var values []string
+	for _, chunk := range table.Column(indices[0]).Data().Chunks() {
+		for i := 0; i < chunk.Len(); i++ {
+			values = append(values, chunk.ValueStr(i))
+		}
+	}
+	return values
+}
+
+func TestWriteParquetStruct(t *testing.T) {
+	type company struct {
+		Title *string
+	}
+	type extract struct {
+		ID       int64  `db_column:"id,key"`
+		City     string `db_column:"city,trim"`
+		Employer *company
+	}
+
+	m := rowconv.NewMapper()
+	if err := rowconv.RegisterMappingOn[company](m, map[string]string{"Title": "company"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteParquetStructWith(m, &buf, queryRows(t), reflect.TypeOf(&extract{}), 2, parquet.NewWriterProperties()); err != nil {
+		t.Fatal(err)
+	}
+
+	table := readParquet(t, buf.Bytes())
+	if table.NumRows() != 3 {
+		t.Fatalf("unexpected amount of rows: %d", table.NumRows())
+	}
+	if values := columnValues(t, table, "id"); !reflect.DeepEqual(values, []string{"1", "2", "3"}) {
+		t.Errorf("unexpected ids: %v", values)
+	}
+	// trimmed by the tag option
+	if values := columnValues(t, table, "city"); !reflect.DeepEqual(values, []string{"Berlin", "Paris", "Rome"}) {
+		t.Errorf("unexpected cities: %v", values)
+	}
+	// the field of the nested struct is mapped by the registered mapping
+	if values := columnValues(t, table, "company"); !reflect.DeepEqual(values, []string{"ACME", array.NullValueStr, "Initech"}) {
+		t.Errorf("unexpected companies: %v", values)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, queryRows(t), nil, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	table := readParquet(t, buf.Bytes())
+	if values := columnValues(t, table, "city"); !reflect.DeepEqual(values, []string{"Berlin  ", "Paris", "Rome"}) {
+		t.Errorf("unexpected cities: %v", values)
+	}
+}
@@ -0,0 +1,305 @@
+// Package arrowconv converts 'database/sql/Rows' into Apache Arrow record batches and Parquet files.
+package arrowconv
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Propagate converts rows into record batches of the schema and passes each of them to fn.
+// Columns are matched to schema fields by name case-insensitively: columns without field are skipped and
+// fields without column are filled with nulls. If schema is nil it is derived from the columns with SchemaFromColumns.
+// Each batch holds at most batchSize rows. The record is released once fn returns, fn must Retain it to keep it longer.
+func Propagate(rows *sql.Rows, schema *arrow.Schema, batchSize int, fn func(arrow.Record) error) error {
+	return PropagateWithAllocator(memory.DefaultAllocator, rows, schema, batchSize, fn)
+}
+
+// PropagateWithAllocator is the same as Propagate, but memory of the record batches is taken from mem
+func PropagateWithAllocator(mem memory.Allocator, rows *sql.Rows, schema *arrow.Schema, batchSize int, fn func(arrow.Record) error) error {
+	if batchSize <= 0 {
+		return errors.New("batch size must be positive")
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		schema = SchemaFromColumns(columnTypes)
+	}
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	appenders, err := createAppenders(schema, columnTypes)
+	if err != nil {
+		return err
+	}
+	scanDestinations := make([]interface{}, len(appenders))
+	for i, appender := range appenders {
+		scanDestinations[i] = appender.dest
+	}
+	unmapped := unmappedFields(schema, columnTypes)
+
+	flush := func() error {
+		record := builder.NewRecord()
+		defer record.Release()
+		return fn(record)
+	}
+
+	size := 0
+	for rows.Next() {
+		if err := rows.Scan(scanDestinations...); err != nil {
+			return err
+		}
+
+		for _, appender := range appenders {
+			if appender.appendTo == nil {
+				continue
+			}
+			if err := appender.appendTo(builder.Field(appender.field)); err != nil {
+				return errors.New("column " + appender.column + ": " + err.Error())
+			}
+		}
+		for _, field := range unmapped {
+			builder.Field(field).AppendNull()
+		}
+
+		if size++; size == batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			size = 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if size > 0 {
+		return flush()
+	}
+	return nil
+}
+
+type columnAppender struct {
+	column   string
+	field    int
+	dest     interface{}
+	appendTo func(builder array.Builder) error
+}
+
+func createAppenders(schema *arrow.Schema, columnTypes []*sql.ColumnType) ([]columnAppender, error) {
+	appenders := make([]columnAppender, len(columnTypes))
+	for i, columnType := range columnTypes {
+		field := fieldIndex(schema, columnType.Name())
+		if field < 0 {
+			var skip interface{}
+			appenders[i] = columnAppender{dest: &skip}
+			continue
+		}
+
+		appender, err := createAppender(schema.Field(field).Type)
+		if err != nil {
+			return nil, errors.New("column " + columnType.Name() + ": " + err.Error())
+		}
+		appender.column = columnType.Name()
+		appender.field = field
+		appenders[i] = appender
+	}
+	return appenders, nil
+}
+
+func createAppender(dataType arrow.DataType) (columnAppender, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		var v sql.NullBool
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			builder.(*array.BooleanBuilder).Append(v.Bool)
+			return nil
+		}}, nil
+
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32:
+		var v sql.NullInt64
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			return appendInteger(builder, v.Int64)
+		}}, nil
+
+	case arrow.UINT64:
+		// values above math.MaxInt64 don't fit into sql.NullInt64, so they are scanned as text
+		var v sql.NullString
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			n, err := strconv.ParseUint(v.String, 10, 64)
+			if err != nil {
+				return errors.New("value " + v.String + " is out of range of " + dataType.String())
+			}
+			builder.(*array.Uint64Builder).Append(n)
+			return nil
+		}}, nil
+
+	case arrow.FLOAT32, arrow.FLOAT64:
+		var v sql.NullFloat64
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			switch b := builder.(type) {
+			case *array.Float32Builder:
+				b.Append(float32(v.Float64))
+			case *array.Float64Builder:
+				b.Append(v.Float64)
+			}
+			return nil
+		}}, nil
+
+	case arrow.STRING:
+		var v sql.NullString
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			builder.(*array.StringBuilder).Append(v.String)
+			return nil
+		}}, nil
+
+	case arrow.BINARY:
+		var v []byte
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if v == nil {
+				builder.AppendNull()
+				return nil
+			}
+			builder.(*array.BinaryBuilder).Append(v)
+			return nil
+		}}, nil
+
+	case arrow.TIMESTAMP:
+		unit := dataType.(*arrow.TimestampType).Unit
+		var v sql.NullTime
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			ts, err := timestampFromTime(v.Time, unit)
+			if err != nil {
+				return err
+			}
+			builder.(*array.TimestampBuilder).Append(ts)
+			return nil
+		}}, nil
+
+	case arrow.DATE32:
+		var v sql.NullTime
+		return columnAppender{dest: &v, appendTo: func(builder array.Builder) error {
+			if !v.Valid {
+				builder.AppendNull()
+				return nil
+			}
+			builder.(*array.Date32Builder).Append(arrow.Date32FromTime(v.Time))
+			return nil
+		}}, nil
+
+	default:
+		return columnAppender{}, errors.New("unsupported arrow type: " + dataType.String())
+	}
+}
+
+// timestampFromTime is arrow.TimestampFromTime that fails for the time which can't be represented in the unit
+// instead of overflowing, e.g. the time before 1677 or after 2262 in nanoseconds
+func timestampFromTime(t time.Time, unit arrow.TimeUnit) (arrow.Timestamp, error) {
+	perSecond := int64(time.Second / unit.Multiplier())
+	if seconds := t.Unix(); seconds > math.MaxInt64/perSecond-1 || seconds < math.MinInt64/perSecond+1 {
+		return 0, errors.New("time " + t.String() + " is out of range of timestamp[" + unit.String() + "]")
+	}
+	return arrow.TimestampFromTime(t, unit)
+}
+
+// appendInteger appends n to the builder of the integer type, n out of range of the type is an error
+func appendInteger(builder array.Builder, n int64) error {
+	var min, max int64
+	switch builder.(type) {
+	case *array.Int8Builder:
+		min, max = math.MinInt8, math.MaxInt8
+	case *array.Int16Builder:
+		min, max = math.MinInt16, math.MaxInt16
+	case *array.Int32Builder:
+		min, max = math.MinInt32, math.MaxInt32
+	case *array.Int64Builder:
+		min, max = math.MinInt64, math.MaxInt64
+	case *array.Uint8Builder:
+		max = math.MaxUint8
+	case *array.Uint16Builder:
+		max = math.MaxUint16
+	case *array.Uint32Builder:
+		max = math.MaxUint32
+	}
+	if n < min || n > max {
+		return errors.New("value " + strconv.FormatInt(n, 10) + " is out of range of " + builder.Type().String())
+	}
+
+	switch b := builder.(type) {
+	case *array.Int8Builder:
+		b.Append(int8(n))
+	case *array.Int16Builder:
+		b.Append(int16(n))
+	case *array.Int32Builder:
+		b.Append(int32(n))
+	case *array.Int64Builder:
+		b.Append(n)
+	case *array.Uint8Builder:
+		b.Append(uint8(n))
+	case *array.Uint16Builder:
+		b.Append(uint16(n))
+	case *array.Uint32Builder:
+		b.Append(uint32(n))
+	}
+	return nil
+}
+
+func fieldIndex(schema *arrow.Schema, columnName string) int {
+	for i, field := range schema.Fields() {
+		if strings.EqualFold(field.Name, columnName) {
+			return i
+		}
+	}
+	return -1
+}
+
+func unmappedFields(schema *arrow.Schema, columnTypes []*sql.ColumnType) []int {
+	var unmapped []int
+	for i, field := range schema.Fields() {
+		mapped := false
+		for _, columnType := range columnTypes {
+			if strings.EqualFold(field.Name, columnType.Name()) {
+				mapped = true
+				break
+			}
+		}
+		if !mapped {
+			unmapped = append(unmapped, i)
+		}
+	}
+	return unmapped
+}
@@ -0,0 +1,105 @@
+//go:build sqlite
+// +build sqlite
+
+package arrowconv
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func queryNumbers(t *testing.T, values string) *sql.Rows {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE numbers(n INTEGER, total TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO numbers VALUES ` + values); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(`SELECT n, total FROM numbers`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rows.Close() })
+	return rows
+}
+
+func TestPropagateUint64(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "total", Type: arrow.PrimitiveTypes.Uint64, Nullable: true}}, nil)
+	var totals []uint64
+	err := Propagate(queryNumbers(t, "(1, '18446744073709551615'), (2, NULL)"), schema, 10, func(record arrow.Record) error {
+		column := record.Column(0).(*array.Uint64)
+		for i := 0; i < column.Len(); i++ {
+			totals = append(totals, column.Value(i))
+		}
+		if !column.IsNull(1) {
+			t.Error("NULL expected to be appended as null")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totals) != 2 || totals[0] != 18446744073709551615 {
+		t.Errorf("unexpected totals: %v", totals)
+	}
+}
+
+func TestPropagateOutOfRange(t *testing.T) {
+	for _, tc := range []struct {
+		dataType arrow.DataType
+		column   string
+		values   string
+	}{
+		{dataType: arrow.PrimitiveTypes.Int8, column: "n", values: "(300, '1')"},
+		{dataType: arrow.PrimitiveTypes.Int32, column: "n", values: "(-2147483649, '1')"},
+		{dataType: arrow.PrimitiveTypes.Uint8, column: "n", values: "(-1, '1')"},
+		{dataType: arrow.PrimitiveTypes.Uint64, column: "total", values: "(1, '-1')"},
+	} {
+		t.Run(tc.dataType.String()+"/"+tc.column, func(t *testing.T) {
+			schema := arrow.NewSchema([]arrow.Field{{Name: tc.column, Type: tc.dataType, Nullable: true}}, nil)
+			err := Propagate(queryNumbers(t, tc.values), schema, 10, func(arrow.Record) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), "column "+tc.column) || !strings.Contains(err.Error(), "out of range") {
+				t.Errorf("out of range error of column %s expected, actual: %v", tc.column, err)
+			}
+		})
+	}
+}
+
+func TestPropagateTimestampOutOfRange(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE events(at DATETIME)`); err != nil {
+		t.Fatal(err)
+	}
+	// nanoseconds since epoch can't represent the year 1000
+	if _, err := db.Exec(`INSERT INTO events VALUES ('1000-01-01 00:00:00')`); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(`SELECT at FROM events`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "at", Type: &arrow.TimestampType{Unit: arrow.Nanosecond}, Nullable: true}}, nil)
+	if err := Propagate(rows, schema, 10, func(arrow.Record) error { return nil }); err == nil || !strings.Contains(err.Error(), "column at") {
+		t.Errorf("error of column at expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package arrowconv
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/pavelmemory/rowconv"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	rawBytesType = reflect.TypeOf(sql.RawBytes{})
+
+	nullTypes = map[reflect.Type]arrow.DataType{
+		reflect.TypeOf(sql.NullBool{}):    arrow.FixedWidthTypes.Boolean,
+		reflect.TypeOf(sql.NullInt16{}):   arrow.PrimitiveTypes.Int16,
+		reflect.TypeOf(sql.NullInt32{}):   arrow.PrimitiveTypes.Int32,
+		reflect.TypeOf(sql.NullInt64{}):   arrow.PrimitiveTypes.Int64,
+		reflect.TypeOf(sql.NullFloat64{}): arrow.PrimitiveTypes.Float64,
+		reflect.TypeOf(sql.NullString{}):  arrow.BinaryTypes.String,
+		reflect.TypeOf(sql.NullTime{}):    arrow.FixedWidthTypes.Timestamp_us,
+	}
+)
+
+// SchemaFromColumns derives schema of the record batches from the column types returned by database driver.
+// Columns of types that have no natural Arrow representation are stored as strings.
+func SchemaFromColumns(columnTypes []*sql.ColumnType) *arrow.Schema {
+	fields := make([]arrow.Field, len(columnTypes))
+	for i, columnType := range columnTypes {
+		nullable, ok := columnType.Nullable()
+		fields[i] = arrow.Field{
+			Name:     columnType.Name(),
+			Type:     columnDataType(columnType),
+			Nullable: nullable || !ok,
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+func columnDataType(columnType *sql.ColumnType) arrow.DataType {
+	scanType := columnType.ScanType()
+	if scanType == nil {
+		return arrow.BinaryTypes.String
+	}
+	if dataType, found := nullTypes[scanType]; found {
+		return dataType
+	}
+	if scanType == rawBytesType {
+		// drivers use raw bytes for textual and binary columns, only name of the type can tell them apart
+		switch typeName := strings.ToUpper(columnType.DatabaseTypeName()); {
+		case strings.Contains(typeName, "BLOB"), strings.Contains(typeName, "BINARY"), typeName == "BYTEA":
+			return arrow.BinaryTypes.Binary
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+	if dataType, err := goDataType(scanType); err == nil {
+		return dataType
+	}
+	return arrow.BinaryTypes.String
+}
+
+// SchemaFromStruct derives schema of the record batches from the struct fields.
+// Fields are named by the columns rowconv maps them to, see rowconv.Mappings: tag options, fields of nested structs
+// and mappings registered with rowconv.RegisterMapping are taken into account.
+// Fields of pointer type and fields of nested structs referenced by pointer are nullable.
+func SchemaFromStruct(t reflect.Type) (*arrow.Schema, error) {
+	return SchemaFromStructWith(rowconv.Default(), t)
+}
+
+// SchemaFromStructWith is the same as SchemaFromStruct, but the fields are mapped with the mapper m
+func SchemaFromStructWith(m *rowconv.Mapper, t reflect.Type) (*arrow.Schema, error) {
+	mappings, err := structMappings(m, t)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]arrow.Field, len(mappings))
+	for i, mapping := range mappings {
+		fields[i] = mapping.field
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+type fieldMapping struct {
+	field      arrow.Field
+	fieldIndex []int
+}
+
+// structMappings returns the fields of the schema of t in the order of their declaration with indexes of the struct fields
+func structMappings(m *rowconv.Mapper, t reflect.Type) ([]fieldMapping, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("struct type is expected, received: " + t.String())
+	}
+
+	mappings, err := m.Mappings(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []fieldMapping
+	for i, mapping := range mappings {
+		// nested struct is represented by its own mapped fields that follow it
+		if i+1 < len(mappings) && hasPrefix(mappings[i+1].FieldIndex, mapping.FieldIndex) {
+			continue
+		}
+
+		exported, nullable := inspectPath(t, mapping.FieldIndex)
+		if !exported {
+			continue
+		}
+
+		fieldType := mapping.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType, nullable = fieldType.Elem(), true
+		}
+		dataType, err := goDataType(fieldType)
+		if err != nil {
+			return nil, errors.New("field " + strings.Join(mapping.FieldPath, ".") + ": " + err.Error())
+		}
+		fields = append(fields, fieldMapping{
+			field:      arrow.Field{Name: mapping.Column, Type: dataType, Nullable: nullable},
+			fieldIndex: mapping.FieldIndex,
+		})
+	}
+	return fields, nil
+}
+
+func hasPrefix(index, prefix []int) bool {
+	if len(index) <= len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if index[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// inspectPath reports if all the fields on the way to the field of t are exported and if any of the nested structs
+// on the way is referenced by pointer
+func inspectPath(t reflect.Type, fieldIndex []int) (exported, throughPointer bool) {
+	for n, i := range fieldIndex {
+		if t.Kind() == reflect.Ptr {
+			t, throughPointer = t.Elem(), true
+		}
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			return false, throughPointer
+		}
+		if n < len(fieldIndex)-1 {
+			t = field.Type
+		}
+	}
+	return true, throughPointer
+}
+
+func goDataType(t reflect.Type) (arrow.DataType, error) {
+	if t == timeType {
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case reflect.Int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case reflect.Int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case reflect.Int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case reflect.Int, reflect.Int64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case reflect.Uint8:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case reflect.Uint16:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case reflect.Uint32:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case reflect.Uint, reflect.Uint64:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case reflect.Float32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case reflect.Float64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case reflect.String:
+		return arrow.BinaryTypes.String, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return arrow.BinaryTypes.Binary, nil
+		}
+	}
+	return nil, errors.New("unsupported type: " + t.String())
+}
@@ -0,0 +1,91 @@
+package arrowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/pavelmemory/rowconv"
+)
+
+func TestSchemaFromStruct(t *testing.T) {
+	type refStruct struct {
+		PK        int `db_column:"id"`
+		Col1      string
+		Col2      *string
+		Created   *time.Time `db_column:"creation_time"`
+		Payload   []byte
+		unexposed bool
+	}
+
+	schema, err := SchemaFromStruct(reflect.TypeOf(&refStruct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "col1", Type: arrow.BinaryTypes.String},
+		{Name: "col2", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "creation_time", Type: arrow.FixedWidthTypes.Timestamp_us, Nullable: true},
+		{Name: "payload", Type: arrow.BinaryTypes.Binary},
+	}, nil)
+	if !schema.Equal(exp) {
+		t.Errorf("unexpected schema: expected %v, actual %v", exp, schema)
+	}
+}
+
+func TestSchemaFromStructUnsupported(t *testing.T) {
+	if _, err := SchemaFromStruct(reflect.TypeOf(struct{ M map[string]int }{})); err == nil {
+		t.Error("error expected for unsupported field type")
+	}
+	if _, err := SchemaFromStruct(reflect.TypeOf(1)); err == nil {
+		t.Error("error expected for non-struct type")
+	}
+}
+
+func TestSchemaFromStructMapping(t *testing.T) {
+	type address struct {
+		City string `db_column:"city,trim"`
+		Zip  string
+	}
+	type company struct {
+		Title string
+	}
+	type refStruct struct {
+		ID       int64 `db_column:"id,key"`
+		Name     string
+		Home     address
+		Employer *company
+	}
+
+	m := rowconv.NewMapper()
+	if err := rowconv.RegisterMappingOn[refStruct](m, map[string]string{"Name": "full_name"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rowconv.RegisterMappingOn[company](m, map[string]string{"Title": "company_title"}); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := SchemaFromStructWith(m, reflect.TypeOf(refStruct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "full_name", Type: arrow.BinaryTypes.String},
+		{Name: "city", Type: arrow.BinaryTypes.String},
+		{Name: "zip", Type: arrow.BinaryTypes.String},
+		{Name: "company_title", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	if !schema.Equal(exp) {
+		t.Errorf("unexpected schema: expected %v, actual %v", exp, schema)
+	}
+
+	if _, err := SchemaFromStruct(reflect.TypeOf(struct {
+		Home address `db_column:"home,composite"`
+	}{})); err == nil {
+		t.Error("error expected for the struct mapped as a whole")
+	}
+}
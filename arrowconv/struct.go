@@ -0,0 +1,148 @@
+package arrowconv
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/pavelmemory/rowconv"
+)
+
+// PropagateStruct converts rows into record batches of the schema derived from t with SchemaFromStruct and passes
+// each of them to fn. Rows are mapped into values of t by rowconv with opts first, so tag options, registered mappings,
+// scanners and converters are applied the same way as by rowconv.Propagate.
+// Each batch holds at most batchSize rows. The record is released once fn returns, fn must Retain it to keep it longer.
+func PropagateStruct(rows *sql.Rows, t reflect.Type, batchSize int, fn func(arrow.Record) error, opts ...rowconv.Option) error {
+	return PropagateStructWith(rowconv.Default(), memory.DefaultAllocator, rows, t, batchSize, fn, opts...)
+}
+
+// PropagateStructWith is the same as PropagateStruct, but rows are mapped by the mapper m
+// and memory of the record batches is taken from mem
+func PropagateStructWith(m *rowconv.Mapper, mem memory.Allocator, rows *sql.Rows, t reflect.Type, batchSize int, fn func(arrow.Record) error, opts ...rowconv.Option) error {
+	if batchSize <= 0 {
+		return errors.New("batch size must be positive")
+	}
+
+	mappings, err := structMappings(m, t)
+	if err != nil {
+		return err
+	}
+	fields := make([]arrow.Field, len(mappings))
+	for i, mapping := range mappings {
+		fields[i] = mapping.field
+	}
+
+	builder := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+	defer builder.Release()
+
+	sink := &recordSink{builder: builder, mappings: mappings, batchSize: batchSize, fn: fn}
+	return m.PropagateSink(sink, t, rows, opts...)
+}
+
+// recordSink appends mapped values to the record builder field by field and passes full batches to fn
+type recordSink struct {
+	builder   *array.RecordBuilder
+	mappings  []fieldMapping
+	batchSize int
+	size      int
+	fn        func(arrow.Record) error
+}
+
+func (rs *recordSink) Add(v reflect.Value) error {
+	for i, mapping := range rs.mappings {
+		field, ok := fieldByIndex(v, mapping.fieldIndex)
+		if !ok {
+			rs.builder.Field(i).AppendNull()
+			continue
+		}
+		if err := appendValue(rs.builder.Field(i), field); err != nil {
+			return errors.New("field " + rs.mappings[i].field.Name + ": " + err.Error())
+		}
+	}
+
+	if rs.size++; rs.size == rs.batchSize {
+		return rs.flush()
+	}
+	return nil
+}
+
+func (rs *recordSink) Flush() error {
+	if rs.size > 0 {
+		return rs.flush()
+	}
+	return nil
+}
+
+func (rs *recordSink) flush() error {
+	rs.size = 0
+	record := rs.builder.NewRecord()
+	defer record.Release()
+	return rs.fn(record)
+}
+
+// fieldByIndex returns the field of v (or of the struct it refers to), false is returned if there is nil reference
+// on the way to the field, including the field itself
+func fieldByIndex(v reflect.Value, fieldIndex []int) (reflect.Value, bool) {
+	for _, i := range fieldIndex {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// appendValue appends v to the builder of the data type derived from the type of v with goDataType,
+// the time that can't be represented in the unit of the timestamp is an error
+func appendValue(builder array.Builder, v reflect.Value) error {
+	switch b := builder.(type) {
+	case *array.BooleanBuilder:
+		b.Append(v.Bool())
+	case *array.Int8Builder:
+		b.Append(int8(v.Int()))
+	case *array.Int16Builder:
+		b.Append(int16(v.Int()))
+	case *array.Int32Builder:
+		b.Append(int32(v.Int()))
+	case *array.Int64Builder:
+		b.Append(v.Int())
+	case *array.Uint8Builder:
+		b.Append(uint8(v.Uint()))
+	case *array.Uint16Builder:
+		b.Append(uint16(v.Uint()))
+	case *array.Uint32Builder:
+		b.Append(uint32(v.Uint()))
+	case *array.Uint64Builder:
+		b.Append(v.Uint())
+	case *array.Float32Builder:
+		b.Append(float32(v.Float()))
+	case *array.Float64Builder:
+		b.Append(v.Float())
+	case *array.StringBuilder:
+		b.Append(v.String())
+	case *array.BinaryBuilder:
+		b.Append(v.Bytes())
+	case *array.TimestampBuilder:
+		ts, err := timestampFromTime(v.Interface().(time.Time), b.Type().(*arrow.TimestampType).Unit)
+		if err != nil {
+			return err
+		}
+		b.Append(ts)
+	default:
+		builder.AppendNull()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// PropagateBatches converts rows into elements of batches of at most size elements and invokes fn for each batch.
+// fn must be of `func(batch []T) error` form, where T is any element type supported by Propagate.
+// The batch is reused between invocations, so fn must copy elements it wants to keep after return.
+// The first error returned by fn stops propagation and is returned as is.
+func PropagateBatches(rows *sql.Rows, size int, fn interface{}, opts ...Option) error {
+	return Default().PropagateBatches(rows, size, fn, opts...)
+}
+
+// PropagateBatches is the same as PropagateBatches of the package with the options of the mapper
+func (m *Mapper) PropagateBatches(rows *sql.Rows, size int, fn interface{}, opts ...Option) error {
+	if size <= 0 {
+		return errors.New("batch size must be positive")
+	}
+
+	fnValue := reflect.ValueOf(fn)
+	if !isBatchFunc(fnValue) {
+		return fmt.Errorf("function of `func(batch []T) error` form is expected, received: %T", fn)
+	}
+	fnType := fnValue.Type()
+
+	sink := &batchSink{
+		batch: reflect.MakeSlice(fnType.In(0), 0, size),
+		fn:    fnValue,
+	}
+	return m.PropagateSink(sink, fnType.In(0).Elem(), rows, opts...)
+}
+
+func isBatchFunc(fnValue reflect.Value) bool {
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		return false
+	}
+	fnType := fnValue.Type()
+	return fnType.NumIn() == 1 && fnType.In(0).Kind() == reflect.Slice &&
+		fnType.NumOut() == 1 && fnType.Out(0) == errorType
+}
+
+type batchSink struct {
+	batch reflect.Value
+	fn    reflect.Value
+}
+
+func (bs *batchSink) Add(v reflect.Value) error {
+	bs.batch = reflect.Append(bs.batch, v)
+	if bs.batch.Len() < bs.batch.Cap() {
+		return nil
+	}
+	return bs.Flush()
+}
+
+func (bs *batchSink) Flush() error {
+	if bs.batch.Len() == 0 {
+		return nil
+	}
+
+	out := bs.fn.Call([]reflect.Value{bs.batch})
+	// the same backing array is used for the next batch
+	bs.batch = bs.batch.Slice(0, 0)
+	if err, _ := out[0].Interface().(error); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPropagateBatches(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')",
+		"SELECT id, col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	var batches [][]valStruct
+	err := PropagateBatches(rows, 2, func(batch []valStruct) error {
+		batches = append(batches, append([]valStruct(nil), batch...))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := [][]valStruct{
+		{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}},
+		{{Id: 3, Col1: "c"}, {Id: 4, Col1: "d"}},
+		{{Id: 5, Col1: "e"}},
+	}
+	if !reflect.DeepEqual(batches, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, batches)
+	}
+}
+
+func TestPropagateBatchesCallbackError(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"SELECT id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	stop := errors.New("stop")
+	calls := 0
+	err := PropagateBatches(rows, 1, func(batch []int) error {
+		calls++
+		return stop
+	})
+	if err != stop {
+		t.Errorf("callback error expected, received: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("propagation must stop after the first error, calls made: %d", calls)
+	}
+}
+
+func TestPropagateBatchesInvalidCallback(t *testing.T) {
+	for _, fn := range []interface{}{nil, func(batch []int) {}, func(batch int) error { return nil }} {
+		if err := PropagateBatches(nil, 1, fn); err == nil {
+			t.Errorf("error expected for callback %T", fn)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rowconv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+)
+
+// binaryDecoder returns converter for the field with `hex` or `bytea` tag option, nil if there is none of them.
+// `hex` decodes hex-encoded text, optionally prefixed with 0x or \x: `db_column:"data,hex"`.
+// `bytea` decodes both output formats of PostgreSQL bytea: hex (\x0102) and escape (\001\002).
+func binaryDecoder(fieldOptions []string) converter {
+	var decode func(text []byte) ([]byte, error)
+	switch {
+	case hasOption(fieldOptions, "hex"):
+		decode = decodeHex
+	case hasOption(fieldOptions, "bytea"):
+		decode = decodeBytea
+	default:
+		return nil
+	}
+
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil {
+			return convertDefault(src, dst)
+		}
+		text := asBytes(src)
+		decoded, err := decode(text)
+		if err != nil {
+			return &ParseError{Value: string(text), Type: dst.Type(), Err: err}
+		}
+		return convertDefault(decoded, dst)
+	}
+}
+
+func decodeHex(text []byte) ([]byte, error) {
+	if len(text) >= 2 && (text[0] == '0' || text[0] == '\\') && (text[1] == 'x' || text[1] == 'X') {
+		text = text[2:]
+	}
+	decoded := make([]byte, hex.DecodedLen(len(text)))
+	n, err := hex.Decode(decoded, text)
+	return decoded[:n], err
+}
+
+func decodeBytea(text []byte) ([]byte, error) {
+	if bytes.HasPrefix(text, []byte(`\x`)) {
+		return decodeHex(text)
+	}
+
+	// escape format: backslash is doubled and non-printable bytes are octal escapes
+	decoded := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			decoded = append(decoded, text[i])
+			continue
+		}
+		switch {
+		case i+1 < len(text) && text[i+1] == '\\':
+			decoded = append(decoded, '\\')
+			i++
+		case i+3 < len(text) && isOctal(text[i+1]) && isOctal(text[i+2]) && isOctal(text[i+3]):
+			decoded = append(decoded, (text[i+1]-'0')<<6|(text[i+2]-'0')<<3|(text[i+3]-'0'))
+			i += 3
+		default:
+			return nil, errors.New("invalid escape sequence of bytea")
+		}
+	}
+	return decoded, nil
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
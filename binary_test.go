@@ -0,0 +1,52 @@
+package rowconv
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeBinary(t *testing.T) {
+	for _, tc := range []struct {
+		text   string
+		decode func([]byte) ([]byte, error)
+		exp    []byte
+	}{
+		{text: "0102ab", decode: decodeHex, exp: []byte{1, 2, 0xab}},
+		{text: "0x0102AB", decode: decodeHex, exp: []byte{1, 2, 0xab}},
+		{text: `\x0102ab`, decode: decodeBytea, exp: []byte{1, 2, 0xab}},
+		{text: `a\\b\001\377`, decode: decodeBytea, exp: []byte{'a', '\\', 'b', 1, 0xff}},
+	} {
+		act, err := tc.decode([]byte(tc.text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(act, tc.exp) {
+			t.Errorf("unexpected decoding of %s: expected %v, actual %v", tc.text, tc.exp, act)
+		}
+	}
+
+	for _, text := range []string{`\xzz`, `\9`} {
+		if _, err := decodeBytea([]byte(text)); err == nil {
+			t.Errorf("error expected for malformed bytea: %s", text)
+		}
+	}
+}
+
+func TestBinaryOptions(t *testing.T) {
+	convert := compileOptions{state: Default().state}.converter(reflect.TypeOf(net.HardwareAddr{}), []string{"hex"})
+	var mac net.HardwareAddr
+	if err := convert("08002b010203", reflect.ValueOf(&mac).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if mac.String() != "08:00:2b:01:02:03" {
+		t.Errorf("unexpected decoded value: %v", mac)
+	}
+
+	var data *[]byte
+	convert = compileOptions{state: Default().state}.converter(reflect.TypeOf(data), []string{"bytea"})
+	if err := convert(nil, reflect.ValueOf(&data).Elem()); err != nil || data != nil {
+		t.Errorf("nil expected for NULL, actual: %v, error: %v", data, err)
+	}
+}
@@ -0,0 +1,63 @@
+package rowconv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	blobSinkType   = reflect.TypeOf(BlobSink{})
+	blobOpenerType = reflect.TypeOf((*BlobOpener)(nil)).Elem()
+)
+
+// BlobSink is a type of the field for the binary column which value is streamed into the writer during propagation
+// instead of being kept in the struct, e.g. multi-megabyte BLOB written to the file.
+// The struct with such fields must implement BlobOpener.
+type BlobSink struct {
+	// Writer is the writer the value was written to, it is nil for NULL
+	Writer io.Writer
+	// Size is the amount of bytes written
+	Size int64
+}
+
+// BlobOpener is implemented by the structs with BlobSink fields (by the reference to the struct),
+// it opens the writer for the value of the column. Fields mapped to the preceding columns are already set,
+// so they can be used to name the destination, e.g. by id. Closing of the writer is up to the implementation.
+type BlobOpener interface {
+	OpenBlob(column string) (io.Writer, error)
+}
+
+func holderBlobSink(column string, holderIndexPath []int) holderSupplier {
+	return func(underlyingValue reflect.Value) interface{} {
+		return &blobScanner{
+			column: column,
+			field:  underlyingValue.FieldByIndex(holderIndexPath),
+			opener: underlyingValue.Addr().Interface().(BlobOpener),
+		}
+	}
+}
+
+type blobScanner struct {
+	column string
+	field  reflect.Value
+	opener BlobOpener
+}
+
+func (bs *blobScanner) Scan(src interface{}) error {
+	if src == nil {
+		bs.field.Set(reflect.Zero(blobSinkType))
+		return nil
+	}
+
+	w, err := bs.opener.OpenBlob(bs.column)
+	if err != nil {
+		return err
+	}
+	n, err := w.Write(asBytes(src))
+	bs.field.Set(reflect.ValueOf(BlobSink{Writer: w, Size: int64(n)}))
+	if err != nil {
+		return fmt.Errorf("value of column/alias: %v can't be written: %w", bs.column, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package rowconv
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type document struct {
+	Id      int
+	Col1    BlobSink
+	Col2    BlobSink
+	written map[string]*bytes.Buffer
+}
+
+func (d *document) OpenBlob(column string) (io.Writer, error) {
+	if d.written == nil {
+		d.written = map[string]*bytes.Buffer{}
+	}
+	buffer := &bytes.Buffer{}
+	d.written[column] = buffer
+	return buffer, nil
+}
+
+func TestPropagateBlobSink(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'content', NULL)",
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	var documents []document
+	if err := Propagate(&documents, rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(documents) != 1 {
+		t.Fatalf("unexpeted results of propagation: %+v", documents)
+	}
+	doc := documents[0]
+	if doc.Col1.Size != int64(len("content")) || doc.written["col1"].String() != "content" || doc.Col1.Writer != doc.written["col1"] {
+		t.Errorf("value expected to be written into the sink, actual: %+v", doc.Col1)
+	}
+	if doc.Col2.Writer != nil || doc.written["col2"] != nil {
+		t.Errorf("nothing expected to be written for NULL, actual: %+v", doc.Col2)
+	}
+}
+
+func TestPropagateBlobSinkWithoutOpener(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'content')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 BlobSink
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err == nil {
+		t.Error("error expected for struct that doesn't implement BlobOpener")
+	}
+}
@@ -0,0 +1,237 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MappingBuilder builds Mapping of the columns/aliases to the fields of struct T programmatically,
+// for cases where tags are too static, e.g. column names differ between schemas or tenants:
+//
+//	mapping, err := rowconv.For[User]().
+//		Column("usr_id").Field("ID").
+//		Column("usr_name").Field("Name").Converter(upper).
+//		Column("usr_city").Field("Address.City").
+//		Column("usr_dims").Fields("Width", "Height").Splitter(splitDims).
+//		Build()
+type MappingBuilder[T any] struct {
+	columns []mappedColumn
+	err     error
+}
+
+// mappedColumn is the column/alias mapped with MappingBuilder
+type mappedColumn struct {
+	column  string
+	fields  []string
+	convert func(src interface{}) (interface{}, error)
+	split   func(src interface{}) ([]interface{}, error)
+}
+
+// For starts building of Mapping for struct T
+func For[T any]() *MappingBuilder[T] {
+	return &MappingBuilder[T]{}
+}
+
+// Column starts mapping of the column/alias, it must be followed by Field or Fields
+func (mb *MappingBuilder[T]) Column(column string) *MappingBuilder[T] {
+	mb.columns = append(mb.columns, mappedColumn{column: column})
+	return mb
+}
+
+// Field sets the field the column/alias is mapped to, fields of nested structs are referred by the path: "Address.City"
+func (mb *MappingBuilder[T]) Field(fieldPath string) *MappingBuilder[T] {
+	if len(mb.columns) == 0 {
+		mb.fail(fmt.Errorf("field %s is set before the column", fieldPath))
+		return mb
+	}
+	mb.columns[len(mb.columns)-1].fields = []string{fieldPath}
+	return mb
+}
+
+// Fields sets the fields the value of the column/alias is split into by Splitter
+func (mb *MappingBuilder[T]) Fields(fieldPaths ...string) *MappingBuilder[T] {
+	if len(mb.columns) == 0 {
+		mb.fail(fmt.Errorf("fields %v are set before the column", fieldPaths))
+		return mb
+	}
+	mb.columns[len(mb.columns)-1].fields = append([]string(nil), fieldPaths...)
+	return mb
+}
+
+// Converter sets conversion of the value returned by database driver, its result is stored into the field
+// the same way database/sql stores values of the basic types. NULL values are not passed to convert.
+func (mb *MappingBuilder[T]) Converter(convert func(src interface{}) (interface{}, error)) *MappingBuilder[T] {
+	if len(mb.columns) == 0 {
+		mb.fail(errors.New("converter is set before the column"))
+		return mb
+	}
+	mb.columns[len(mb.columns)-1].convert = convert
+	return mb
+}
+
+// Splitter sets splitting of the value returned by database driver into the values of the fields set by Fields,
+// in the same order. It works the same way as the splitters registered with RegisterSplitter.
+func (mb *MappingBuilder[T]) Splitter(split func(src interface{}) ([]interface{}, error)) *MappingBuilder[T] {
+	if len(mb.columns) == 0 {
+		mb.fail(errors.New("splitter is set before the column"))
+		return mb
+	}
+	mb.columns[len(mb.columns)-1].split = split
+	return mb
+}
+
+func (mb *MappingBuilder[T]) fail(err error) {
+	if mb.err == nil {
+		mb.err = err
+	}
+}
+
+// Build compiles the mapping, it is applied to the propagation with WithMapping.
+// Mapping should be built once and reused, as compiled mappers are cached for each of them.
+func (mb *MappingBuilder[T]) Build() (*Mapping, error) {
+	if mb.err != nil {
+		return nil, mb.err
+	}
+
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mapping can be built only for struct types, received: %v", structType)
+	}
+
+	mapping := &Mapping{structType: structType, byColumn: map[string]fieldAccessor{}, splits: map[string]fieldSplit{}}
+	for _, mc := range mb.columns {
+		column := strings.ToLower(mc.column)
+		_, mapped := mapping.byColumn[column]
+		_, split := mapping.splits[column]
+		if mapped || split {
+			return nil, fmt.Errorf("column/alias %s is mapped more than once", mc.column)
+		}
+		if len(mc.fields) == 0 || mc.fields[0] == "" {
+			return nil, fmt.Errorf("no field is set for column/alias %s", mc.column)
+		}
+		if len(mc.fields) > 1 && mc.split == nil {
+			return nil, fmt.Errorf("column/alias %s is mapped to several fields without splitter", mc.column)
+		}
+
+		accessors := make([]fieldAccessor, len(mc.fields))
+		for i, field := range mc.fields {
+			accessor, err := fieldAccessorByPath(structType, field)
+			if err != nil {
+				return nil, err
+			}
+			accessor.columnAlias = column
+			if mc.convert != nil {
+				accessor.convert = convertWith(mc.convert)
+			}
+			accessors[i] = accessor
+		}
+		if mc.split != nil {
+			mapping.splits[column] = fieldSplit{split: mc.split, accessors: accessors}
+		} else {
+			mapping.byColumn[column] = accessors[0]
+		}
+	}
+	return mapping, nil
+}
+
+// Mapping is the mapping of the columns/aliases to the fields built with MappingBuilder.
+// Mapped columns take precedence over the tags and the names of the fields, other columns are mapped as usual.
+type Mapping struct {
+	structType reflect.Type
+	byColumn   map[string]fieldAccessor
+	splits     map[string]fieldSplit
+}
+
+// WithMapping applies mapping to the propagation into elements of its struct type (or references to it),
+// the mapping is ignored for elements of other types
+func WithMapping(mapping *Mapping) Option {
+	return func(o *options) {
+		o.compile.mapping = mapping
+	}
+}
+
+// columnAliasToAccessor returns accessors of structType by column/alias of the tags of tagKeys with the mapping applied
+func (m *Mapping) columnAliasToAccessor(st *state, tagKeys string) (map[string]fieldAccessor, error) {
+	columnAliasToAccessor, err := st.createFieldsAccessorsByKeys(m.structType, tagKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// fields of the mapped columns are not populated by other columns
+	var mappedAccessors []fieldAccessor
+	for _, mapped := range m.byColumn {
+		mappedAccessors = append(mappedAccessors, mapped)
+	}
+	for _, fs := range m.splits {
+		mappedAccessors = append(mappedAccessors, fs.accessors...)
+	}
+	for columnAlias, accessor := range columnAliasToAccessor {
+		for _, mapped := range mappedAccessors {
+			if reflect.DeepEqual(accessor.fieldIndex, mapped.fieldIndex) {
+				delete(columnAliasToAccessor, columnAlias)
+			}
+		}
+	}
+	for column, accessor := range m.byColumn {
+		columnAliasToAccessor[column] = st.withFieldOptions(m.structType, accessor)
+	}
+	return columnAliasToAccessor, nil
+}
+
+// columnToSplit returns the splits of the mapping with the options of their fields taken by st
+func (m *Mapping) columnToSplit(st *state) map[string]fieldSplit {
+	columnToSplit := make(map[string]fieldSplit, len(m.splits))
+	for column, fs := range m.splits {
+		accessors := make([]fieldAccessor, len(fs.accessors))
+		for i, accessor := range fs.accessors {
+			accessors[i] = st.withFieldOptions(m.structType, accessor)
+		}
+		columnToSplit[column] = fieldSplit{split: fs.split, accessors: accessors}
+	}
+	return columnToSplit
+}
+
+// withFieldOptions returns the accessor of the field of structType with the options of its tag and the mappings
+// registered with the mapper of st. Mapping isn't bound to a mapper, so the options are taken on propagation.
+func (st *state) withFieldOptions(structType reflect.Type, accessor fieldAccessor) fieldAccessor {
+	owner := structType
+	for i, index := range accessor.fieldIndex {
+		owner = derefType(owner)
+		field := owner.Field(index)
+		if i == len(accessor.fieldIndex)-1 {
+			_, accessor.options = st.fieldColumnTag(owner, field)
+		}
+		owner = field.Type
+	}
+	return accessor
+}
+
+// fieldAccessorByPath creates accessor of the field of structType by the dot-separated path of names of the fields,
+// the options of the field are set by withFieldOptions
+func fieldAccessorByPath(structType reflect.Type, fieldPath string) (fieldAccessor, error) {
+	var accessor fieldAccessor
+	inspectionType := structType
+	for _, name := range strings.Split(fieldPath, ".") {
+		inspectionType = derefType(inspectionType)
+		if inspectionType.Kind() != reflect.Struct {
+			return fieldAccessor{}, fmt.Errorf("%v has no field %s", structType, fieldPath)
+		}
+		field, found := inspectionType.FieldByName(name)
+		if !found || len(field.Index) != 1 || field.PkgPath != "" {
+			return fieldAccessor{}, fmt.Errorf("%v has no exported field %s", structType, fieldPath)
+		}
+
+		accessor.fieldIndex = append(accessor.fieldIndex, field.Index...)
+		accessor.fieldPath = append(accessor.fieldPath, field.Name)
+		accessor.fieldType = field.Type
+		inspectionType = field.Type
+	}
+	return accessor, nil
+}
+
+// convertWith creates converter that stores the result of convert into the field
+func convertWith(convert func(src interface{}) (interface{}, error)) converter {
+	return convertBefore(convert, convertReference(convertDefault))
+}
@@ -0,0 +1,93 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPropagateWithMapping(t *testing.T) {
+	type address struct {
+		City string
+	}
+	type valStruct struct {
+		Id      int
+		Name    string `db_column:"col2"`
+		Address *address
+	}
+	upper := func(src interface{}) (interface{}, error) {
+		return strings.ToUpper(asString(src)), nil
+	}
+	mapping, err := For[valStruct]().
+		Column("col1").Field("Name").Converter(upper).
+		Column("col2").Field("Address.City").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'john', 'Berlin')",
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows, WithMapping(mapping)); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Name: "JOHN", Address: &address{City: "Berlin"}}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithMappingConverterError(t *testing.T) {
+	type valStruct struct {
+		Id int
+	}
+	cause := errors.New("invalid id")
+	mapping, err := For[valStruct]().
+		Column("col1").Field("Id").Converter(func(src interface{}) (interface{}, error) { return nil, cause }).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT col1 FROM propagation",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	var parseErr *ParseError
+	if err := Propagate(&valStructs, rows, WithMapping(mapping)); !errors.As(err, &parseErr) || parseErr.Column != "col1" || !errors.Is(err, cause) {
+		t.Errorf("ParseError of the converter expected, actual: %v", err)
+	}
+}
+
+func TestMappingBuilderErrors(t *testing.T) {
+	type valStruct struct {
+		Id     int
+		hidden string
+	}
+
+	for name, builder := range map[string]*MappingBuilder[valStruct]{
+		"field before column":     For[valStruct]().Field("Id"),
+		"converter before column": For[valStruct]().Converter(func(src interface{}) (interface{}, error) { return src, nil }),
+		"no field":                For[valStruct]().Column("id"),
+		"unknown field":           For[valStruct]().Column("id").Field("Unknown"),
+		"unexported field":        For[valStruct]().Column("id").Field("hidden"),
+		"not a struct field":      For[valStruct]().Column("id").Field("Id.Value"),
+		"column mapped twice":     For[valStruct]().Column("id").Field("Id").Column("ID").Field("Id"),
+	} {
+		if _, err := builder.Build(); err == nil {
+			t.Errorf("%s: error expected", name)
+		}
+	}
+	if _, err := For[int]().Build(); err == nil {
+		t.Error("error expected for non-struct type")
+	}
+}
@@ -0,0 +1,210 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore keeps the results of the queries cached by QueryCache, it must be safe for concurrent use.
+// Values are deep copies owned by the store, they are never modified by QueryCache.
+type CacheStore interface {
+	// Get returns the value stored by the key, the second result is false if there is no value or it is expired
+	Get(key string) (interface{}, bool)
+	// Set stores the value by the key for ttl
+	Set(key string, value interface{}, ttl time.Duration)
+}
+
+// QueryCache is opt-in read-through cache of Select and Get: identical queries with identical args propagated into
+// the same type within the TTL return the deep copy of the previously mapped result without the query to the database,
+// e.g. for dashboards hammering the same lookups. Args are compared by the values passed to the driver, pointers by
+// the values they refer to. Options attached to ctx are not part of the key, so they must not differ for the same query.
+type QueryCache struct {
+	mapper *Mapper
+	store  CacheStore
+	ttl    time.Duration
+}
+
+// NewQueryCache creates the cache that keeps the results in store for ttl, nil store is replaced with NewMemoryCacheStore
+func NewQueryCache(store CacheStore, ttl time.Duration) *QueryCache {
+	return NewQueryCacheOn(Default(), store, ttl)
+}
+
+// NewQueryCacheOn is the same as NewQueryCache, the results are propagated with the mapper m.
+// The mapper isn't part of the key, so store must not be shared with the caches of other mappers.
+func NewQueryCacheOn(m *Mapper, store CacheStore, ttl time.Duration) *QueryCache {
+	if store == nil {
+		store = NewMemoryCacheStore()
+	}
+	return &QueryCache{mapper: m, store: store, ttl: ttl}
+}
+
+// Select is Select that returns the cached slice for the query with args, if there is one.
+// Only pointers to slices are supported as dst, the slice is extended with the elements of the result the same way.
+func (qc *QueryCache) Select(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() || dstValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("non-nil pointer to the slice is expected, received: %T", dst)
+	}
+
+	key, cacheable := cacheKey("select", dstValue.Type().Elem(), query, args)
+	if !cacheable {
+		return qc.mapper.Select(ctx, q, dst, query, args...)
+	}
+	if cached, found := qc.store.Get(key); found {
+		dstValue.Elem().Set(reflect.AppendSlice(dstValue.Elem(), deepCopy(reflect.ValueOf(cached))))
+		return nil
+	}
+
+	result := reflect.New(dstValue.Type().Elem())
+	if err := qc.mapper.Select(ctx, q, result.Interface(), query, args...); err != nil {
+		return err
+	}
+	qc.store.Set(key, deepCopy(result.Elem()).Interface(), qc.ttl)
+	dstValue.Elem().Set(reflect.AppendSlice(dstValue.Elem(), result.Elem()))
+	return nil
+}
+
+// Get is Get that returns the cached value for the query with args, if there is one.
+// sql.ErrNoRows is not cached, so the query is executed each time until it returns the row.
+func (qc *QueryCache) Get(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("non-nil pointer is expected, received: %T", dst)
+	}
+
+	key, cacheable := cacheKey("get", dstValue.Type().Elem(), query, args)
+	if !cacheable {
+		return qc.mapper.Get(ctx, q, dst, query, args...)
+	}
+	if cached, found := qc.store.Get(key); found {
+		dstValue.Elem().Set(deepCopy(reflect.ValueOf(cached)))
+		return nil
+	}
+
+	if err := qc.mapper.Get(ctx, q, dst, query, args...); err != nil {
+		return err
+	}
+	qc.store.Set(key, deepCopy(dstValue.Elem()).Interface(), qc.ttl)
+	return nil
+}
+
+// cacheKey identifies the result of the helper by the type it is propagated into, the query and the values of args.
+// Args are normalized the way database/sql passes them to the driver, so pointers and driver.Valuer are keyed
+// by the values they hold at the moment of the query. False is returned if an arg can't be normalized,
+// e.g. of the type supported only by the driver, then the query isn't cached.
+func cacheKey(helper string, dstType reflect.Type, query string, args []interface{}) (string, bool) {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s\x00%v\x00%q", helper, dstType, query)
+	for _, arg := range args {
+		var name string
+		if named, ok := arg.(sql.NamedArg); ok {
+			name, arg = named.Name, named.Value
+		}
+		value, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return "", false
+		}
+		fmt.Fprintf(&key, "\x00%s=%#v", name, value)
+	}
+	return key.String(), true
+}
+
+// deepCopy copies v along with the values it references, so the copy shares no mutable data with v.
+// Unexported fields of structs are copied as is.
+func deepCopy(v reflect.Value) reflect.Value {
+	copied := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			copied.Set(reflect.New(v.Type().Elem()))
+			copied.Elem().Set(deepCopy(v.Elem()))
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			copied.Set(deepCopy(v.Elem()))
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			copied.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				copied.Index(i).Set(deepCopy(v.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			copied.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			iter := v.MapRange()
+			for iter.Next() {
+				copied.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		copied.Set(v)
+	}
+	return copied
+}
+
+// MemoryCacheStore is CacheStore that keeps the values in memory, expired values are evicted lazily
+type MemoryCacheStore struct {
+	entries   map[string]cacheEntry
+	now       func() time.Time
+	lastSweep int
+	sync.Mutex
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewMemoryCacheStore creates empty MemoryCacheStore
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+func (mcs *MemoryCacheStore) Get(key string) (interface{}, bool) {
+	mcs.Lock()
+	defer mcs.Unlock()
+	entry, found := mcs.entries[key]
+	if !found {
+		return nil, false
+	}
+	if !mcs.now().Before(entry.expires) {
+		delete(mcs.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (mcs *MemoryCacheStore) Set(key string, value interface{}, ttl time.Duration) {
+	mcs.Lock()
+	defer mcs.Unlock()
+	now := mcs.now()
+	// expired values of the keys that are not requested anymore are swept each time the store doubles
+	if len(mcs.entries) >= 2*mcs.lastSweep {
+		for k, entry := range mcs.entries {
+			if !now.Before(entry.expires) {
+				delete(mcs.entries, k)
+			}
+		}
+		mcs.lastSweep = len(mcs.entries) + 1
+	}
+	mcs.entries[key] = cacheEntry{value: value, expires: now.Add(ttl)}
+}
@@ -0,0 +1,249 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')"); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 *string
+	}
+	now := time.Now()
+	store := NewMemoryCacheStore()
+	store.now = func() time.Time { return now }
+	cache := NewQueryCache(store, time.Minute)
+
+	query := "SELECT id, col1 FROM propagation WHERE id > ? ORDER BY id"
+	if driverName() == "postgres" {
+		query = "SELECT id, col1 FROM propagation WHERE id > $1 ORDER BY id"
+	}
+	var first []valStruct
+	if err := cache.Select(ctx, tx, &first, query, 0); err != nil {
+		t.Fatal(err)
+	}
+	*first[0].Col1 = "changed"
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (3, 'c')"); err != nil {
+		t.Fatal(err)
+	}
+	var cached []valStruct
+	if err := cache.Select(ctx, tx, &cached, query, 0); err != nil {
+		t.Fatal(err)
+	}
+	a, b := "a", "b"
+	if exp := []valStruct{{Id: 1, Col1: &a}, {Id: 2, Col1: &b}}; !reflect.DeepEqual(cached, exp) {
+		t.Errorf("unexpected cached results: expected %+v, actual %+v", exp, cached)
+	}
+
+	var other []valStruct
+	if err := cache.Select(ctx, tx, &other, query, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 1 || other[0].Id != 3 {
+		t.Errorf("query with other args is not expected to be cached: %+v", other)
+	}
+
+	now = now.Add(time.Minute)
+	var expired []valStruct
+	if err := cache.Select(ctx, tx, &expired, query, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 3 {
+		t.Errorf("expired results are not expected to be returned: %+v", expired)
+	}
+
+	var v valStruct
+	if err := cache.Get(ctx, tx, &v, query, 2); err != nil || v.Id != 3 {
+		t.Errorf("unexpected result of Get: %+v, error: %v", v, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM propagation"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Get(ctx, tx, &v, query, 2); err != nil || v.Id != 3 {
+		t.Errorf("unexpected cached result of Get: %+v, error: %v", v, err)
+	}
+	if err := cache.Get(ctx, tx, &v, query, 3); err != sql.ErrNoRows {
+		t.Errorf("sql.ErrNoRows expected, actual: %v", err)
+	}
+
+	var ids []int
+	if err := cache.Select(ctx, tx, ids, "SELECT id FROM propagation"); err == nil {
+		t.Error("error expected for non-pointer destination")
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type inner struct {
+		Tags []string
+	}
+	type valStruct struct {
+		Inner  *inner
+		Attrs  map[string][]int
+		Any    interface{}
+		hidden []int
+	}
+	orig := valStruct{
+		Inner:  &inner{Tags: []string{"a"}},
+		Attrs:  map[string][]int{"k": {1}},
+		Any:    []string{"x"},
+		hidden: []int{1},
+	}
+	copied := deepCopy(reflect.ValueOf(orig)).Interface().(valStruct)
+	if !reflect.DeepEqual(copied, orig) {
+		t.Fatalf("copy expected to be equal: %+v", copied)
+	}
+
+	copied.Inner.Tags[0] = "b"
+	copied.Attrs["k"][0] = 2
+	copied.Any.([]string)[0] = "y"
+	if orig.Inner.Tags[0] != "a" || orig.Attrs["k"][0] != 1 || orig.Any.([]string)[0] != "x" {
+		t.Errorf("copy is expected to share no data with the original: %+v", orig)
+	}
+}
+
+func TestQueryCachePointerArgs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')"); err != nil {
+		t.Fatal(err)
+	}
+
+	query := "SELECT id FROM propagation WHERE col1 = ?"
+	if driverName() == "postgres" {
+		query = "SELECT id FROM propagation WHERE col1 = $1"
+	}
+	cache := NewQueryCache(nil, time.Minute)
+
+	// the same pointer refers to the other value for the second query
+	col1 := "a"
+	var first, second []int
+	if err := cache.Select(ctx, tx, &first, query, &col1); err != nil {
+		t.Fatal(err)
+	}
+	col1 = "b"
+	if err := cache.Select(ctx, tx, &second, query, &col1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, []int{1}) || !reflect.DeepEqual(second, []int{2}) {
+		t.Errorf("unexpected results for the changed value of the pointer: %v, %v", first, second)
+	}
+
+	// the equal value behind the other pointer is served from the cache
+	if _, err := tx.ExecContext(ctx, "DELETE FROM propagation"); err != nil {
+		t.Fatal(err)
+	}
+	other := "a"
+	var cached []int
+	if err := cache.Select(ctx, tx, &cached, query, &other); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cached, []int{1}) {
+		t.Errorf("cached result expected for the equal value of the other pointer: %v", cached)
+	}
+}
+
+func TestQueryCacheOnMapper(t *testing.T) {
+	begin := func() (*sql.Tx, func()) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+		release := func() {
+			tx.Rollback()
+			cancel()
+		}
+		if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+			release()
+			t.Fatal(err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a')"); err != nil {
+			release()
+			t.Fatal(err)
+		}
+		return tx, release
+	}
+	type valStruct struct {
+		Id   int
+		Name string
+	}
+	mapper := NewMapper()
+	if err := RegisterMappingOn[valStruct](mapper, map[string]string{"Name": "col1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, release := begin()
+	defer release()
+	var selected []valStruct
+	if err := NewQueryCacheOn(mapper, nil, time.Minute).Select(context.Background(), tx, &selected, "SELECT id, col1 FROM propagation"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Name: "a"}}; !reflect.DeepEqual(selected, exp) {
+		t.Errorf("rows expected to be propagated with the mapping of the mapper: expected %+v, actual %+v", exp, selected)
+	}
+	var got valStruct
+	if err := NewQueryCacheOn(mapper, nil, time.Minute).Get(context.Background(), tx, &got, "SELECT id, col1 FROM propagation"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := (valStruct{Id: 1, Name: "a"}); got != exp {
+		t.Errorf("row expected to be propagated with the mapping of the mapper: expected %+v, actual %+v", exp, got)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	dstType := reflect.TypeOf([]int(nil))
+	key := func(args ...interface{}) string {
+		t.Helper()
+		k, ok := cacheKey("select", dstType, "query", args)
+		if !ok {
+			t.Fatalf("args expected to be cacheable: %v", args)
+		}
+		return k
+	}
+
+	a, b, i := "a", "a", 1
+	if key(&a) != key(&b) || key(&a) != key("a") {
+		t.Error("equal values expected to have equal keys regardless of pointers")
+	}
+	if key(&i) != key(int64(1)) || key(sql.NullString{String: "a", Valid: true}) != key("a") {
+		t.Error("args expected to be normalized the way database/sql passes them to the driver")
+	}
+	if key("1") == key(1) || key(sql.Named("a", 1)) == key(sql.Named("b", 1)) {
+		t.Error("different args expected to have different keys")
+	}
+	if _, ok := cacheKey("select", dstType, "query", []interface{}{struct{}{}}); ok {
+		t.Error("args unsupported by database/sql expected not to be cacheable")
+	}
+}
@@ -0,0 +1,98 @@
+package rowconv
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Converter is a converter of the chain tried for the values of the columns stored into the fields, see RegisterConverter
+type Converter struct {
+	// Name identifies the converter, registration of the converter with the same name replaces it
+	Name string
+	// DatabaseTypeName is the database type of the columns the converter applies to, as reported by
+	// sql.ColumnType.DatabaseTypeName, case-insensitive; empty name applies to the columns of all types
+	DatabaseTypeName string
+	// FieldType is the type of the fields (or of the references to them) the converter applies to,
+	// nil applies to the fields of all types
+	FieldType reflect.Type
+	// Priority orders the converters of the chain, the ones of higher priority are tried first.
+	// Converters of the same priority are tried in the order of registration.
+	Priority int
+	// Convert transforms the value returned by database driver, its result is stored into the field the same way
+	// as the value returned by database driver. NULL values are not passed to Convert.
+	Convert func(src interface{}) (interface{}, error)
+}
+
+// RegisterConverter adds the converter to the chain of the column and field pairs it applies to. The chain is resolved
+// once the mapper is compiled and the converters are tried in the order of priority until the value is both converted
+// and stored into the field, the error of the last one is returned if all of them fail. It lets several converters
+// handle the values of the same column in different formats, e.g. legacy and current encodings. The chain is applied
+// after the converter registered with RegisterDatabaseTypeConverter. Converters should be registered before
+// the first propagation, as compiled mappers are cached. Registration of the converter with nil Convert removes it.
+func RegisterConverter(c Converter) {
+	Default().RegisterConverter(c)
+}
+
+// RegisterConverter is the same as RegisterConverter of the package for the mapper
+func (m *Mapper) RegisterConverter(c Converter) {
+	c.DatabaseTypeName = strings.ToUpper(c.DatabaseTypeName)
+	m.state.converters.Lock()
+	defer m.state.converters.Unlock()
+
+	for i, registered := range m.state.converters.list {
+		if registered.Name != c.Name {
+			continue
+		}
+		if c.Convert == nil {
+			m.state.converters.list = append(m.state.converters.list[:i:i], m.state.converters.list[i+1:]...)
+		} else {
+			m.state.converters.list[i] = c
+		}
+		return
+	}
+	if c.Convert != nil {
+		m.state.converters.list = append(m.state.converters.list, c)
+	}
+}
+
+// converterChain returns the converters that apply to the column of the database type stored into the field of forType
+// ordered by priority, the chain is a copy, so later registrations don't affect mappers compiled with it
+func (st *state) converterChain(databaseTypeName string, forType reflect.Type) []Converter {
+	databaseTypeName = strings.ToUpper(databaseTypeName)
+	valueType := derefType(forType)
+
+	var chain []Converter
+	st.converters.RLock()
+	for _, c := range st.converters.list {
+		if (c.DatabaseTypeName == "" || c.DatabaseTypeName == databaseTypeName) &&
+			(c.FieldType == nil || c.FieldType == forType || c.FieldType == valueType) {
+			chain = append(chain, c)
+		}
+	}
+	st.converters.RUnlock()
+
+	sort.SliceStable(chain, func(i, j int) bool { return chain[i].Priority > chain[j].Priority })
+	return chain
+}
+
+// convertChain creates converter that stores the value with convert after the first converter of the chain
+// that succeeds, NULL values are not transformed
+func convertChain(chain []Converter, convert converter) converter {
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil {
+			return convert(nil, dst)
+		}
+
+		var err error
+		for _, c := range chain {
+			var value interface{}
+			if value, err = c.Convert(src); err == nil {
+				if err = convert(value, dst); err == nil {
+					return nil
+				}
+			}
+		}
+		return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+	}
+}
@@ -0,0 +1,70 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterConverter(t *testing.T) {
+	unix := func(src interface{}) (interface{}, error) {
+		seconds, err := strconv.ParseInt(asString(src), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	rfc3339 := func(src interface{}) (interface{}, error) {
+		return time.Parse(time.RFC3339, asString(src))
+	}
+	RegisterConverter(Converter{Name: "rfc3339", FieldType: timeType, Convert: rfc3339})
+	defer RegisterConverter(Converter{Name: "rfc3339"})
+	RegisterConverter(Converter{Name: "unix", FieldType: timeType, Priority: 1, Convert: unix})
+	defer RegisterConverter(Converter{Name: "unix"})
+
+	chain := Default().state.converterChain("VARCHAR", reflect.TypeOf(&time.Time{}))
+	if len(chain) != 2 || chain[0].Name != "unix" || chain[1].Name != "rfc3339" {
+		t.Errorf("converters expected to be ordered by priority, actual: %+v", chain)
+	}
+	if chain := Default().state.converterChain("VARCHAR", reflect.TypeOf("")); len(chain) != 0 {
+		t.Errorf("converters of other field types expected to be skipped, actual: %+v", chain)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 time.Time
+		Col2 *time.Time
+	}
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '2021-03-04T05:06:07Z', '1614834367'), (2, '0', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	exp := []valStruct{{Id: 1, Col1: at, Col2: &at}, {Id: 2, Col1: time.Unix(0, 0).UTC()}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestConvertChainError(t *testing.T) {
+	failed := errors.New("failed")
+	chain := []Converter{
+		{Name: "first", Convert: func(interface{}) (interface{}, error) { return nil, errors.New("first") }},
+		{Name: "last", Convert: func(interface{}) (interface{}, error) { return nil, failed }},
+	}
+	var s string
+	err := convertChain(chain, convertDefault)("value", reflect.ValueOf(&s).Elem())
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, failed) || !strings.Contains(err.Error(), "value") {
+		t.Errorf("parse error of the last converter expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// typeNameWrappers are parametrized database types of ClickHouse that don't change the values of the wrapped type
+var typeNameWrappers = []string{"NULLABLE(", "LOWCARDINALITY("}
+
+// databaseTypeName returns upper-cased database type name of the column without the wrappers of ClickHouse,
+// e.g. "LowCardinality(Nullable(String))" is reported as "STRING", so the column is handled as its underlying type
+func databaseTypeName(columnType columnType) string {
+	name := strings.ToUpper(columnType.DatabaseTypeName())
+	for unwrapped := true; unwrapped; {
+		unwrapped = false
+		for _, wrapper := range typeNameWrappers {
+			if strings.HasPrefix(name, wrapper) && strings.HasSuffix(name, ")") {
+				name = name[len(wrapper) : len(name)-1]
+				unwrapped = true
+			}
+		}
+	}
+	return name
+}
+
+// isArrayColumn returns true for Array(T) columns of ClickHouse, their values are returned by the driver as slices
+func isArrayColumn(columnType columnType) bool {
+	return strings.HasPrefix(databaseTypeName(columnType), "ARRAY(")
+}
+
+// isArrayTarget returns true for slices (or references to them) that values of Array(T) columns are stored into element-wise
+func isArrayTarget(forType reflect.Type) bool {
+	valueType := derefType(forType)
+	return valueType.Kind() == reflect.Slice && valueType.Elem().Kind() != reflect.Uint8 && !reflect.PtrTo(valueType).Implements(scannerType)
+}
+
+// convertArray stores the slice returned for Array(T) column into the slice field of another element type,
+// e.g. []int32 of Array(Int32) into []int64 field. Elements are converted the same way as the values of the columns,
+// so nested arrays and NULL elements of Array(Nullable(T)) stored into slices of references are supported.
+func convertArray(src interface{}, dst reflect.Value) error {
+	srcValue := reflect.ValueOf(src)
+	if srcValue.Kind() != reflect.Slice {
+		return fmt.Errorf("unsupported conversion of %T into %v", src, dst.Type())
+	}
+	if srcValue.Type().AssignableTo(dst.Type()) {
+		dst.Set(srcValue)
+		return nil
+	}
+
+	convertElem := convertReference(convertDefault)
+	if isArrayTarget(dst.Type().Elem()) {
+		convertElem = convertReference(convertArray)
+	}
+	elems := reflect.MakeSlice(dst.Type(), srcValue.Len(), srcValue.Len())
+	for i := 0; i < srcValue.Len(); i++ {
+		elem := srcValue.Index(i)
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				break
+			}
+			elem = elem.Elem()
+		}
+
+		var value interface{}
+		if elem.Kind() != reflect.Ptr && elem.Kind() != reflect.Interface {
+			value = elem.Interface()
+		}
+		if err := convertElem(value, elems.Index(i)); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	dst.Set(elems)
+	return nil
+}
@@ -0,0 +1,31 @@
+// +build clickhouse
+
+package rowconv
+
+import (
+	_ "github.com/ClickHouse/clickhouse-go"
+)
+
+const (
+	clickhouse = "clickhouse"
+)
+
+func driverName() string {
+	return clickhouse
+}
+
+func dataSourceURL() string {
+	return "tcp://127.0.0.1:" + port + "?database=default"
+}
+
+// ddlCreateTestTempTable creates the table in memory of the session, note that the driver sends
+// inserted rows only on commit of the transaction, so ClickHouse tests select literals instead
+func ddlCreateTestTempTable() string {
+	return `
+CREATE TEMPORARY TABLE IF NOT EXISTS propagation(
+	id Decimal(6,0),
+	col1 String,
+	col2 Nullable(String),
+	col3 Nullable(DateTime)
+) ENGINE = Memory`
+}
@@ -0,0 +1,76 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDatabaseTypeName(t *testing.T) {
+	for name, exp := range map[string]string{
+		"String":                           "STRING",
+		"Nullable(DateTime)":               "DATETIME",
+		"LowCardinality(String)":           "STRING",
+		"LowCardinality(Nullable(String))": "STRING",
+		"Array(Nullable(Int64))":           "ARRAY(NULLABLE(INT64))",
+		"Nullable":                         "NULLABLE",
+	} {
+		if act := databaseTypeName(Column{DatabaseTypeName: name}.known()); act != exp {
+			t.Errorf("unexpected database type name of %s: expected %s, actual %s", name, exp, act)
+		}
+	}
+}
+
+func TestConvertArray(t *testing.T) {
+	copts := compileOptions{state: Default().state}
+	ints := Column{Name: "ids", DatabaseTypeName: "Array(Int32)"}.known()
+
+	var act []int64
+	convert := copts.columnConverter(ints, reflect.TypeOf(act), nil)
+	if err := convert([]int32{1, 2, 3}, reflect.ValueOf(&act).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int64{1, 2, 3}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected array: expected %v, actual %v", exp, act)
+	}
+	if err := convert([]string{"1", "x"}, reflect.ValueOf(&act).Elem()); err == nil {
+		t.Error("error expected for element that is not a number")
+	}
+
+	var refs []*int
+	one := int64(1)
+	convert = copts.columnConverter(Column{Name: "ids", DatabaseTypeName: "Array(Nullable(Int64))"}.known(), reflect.TypeOf(refs), nil)
+	if err := convert([]*int64{&one, nil}, reflect.ValueOf(&refs).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 2 || refs[0] == nil || *refs[0] != 1 || refs[1] != nil {
+		t.Errorf("unexpected array of references: %v", refs)
+	}
+
+	var nested [][]string
+	convert = copts.columnConverter(Column{Name: "tags", DatabaseTypeName: "Array(Array(UInt8))"}.known(), reflect.TypeOf(nested), nil)
+	if err := convert([][]uint8{{1, 2}, {}}, reflect.ValueOf(&nested).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := [][]string{{"1", "2"}, {}}; !reflect.DeepEqual(nested, exp) {
+		t.Errorf("unexpected nested array: expected %v, actual %v", exp, nested)
+	}
+
+	if convert := copts.columnConverter(ints, reflect.TypeOf([]byte(nil)), nil); convert != nil {
+		t.Error("arrays are expected to be stored into bytes by database/sql")
+	}
+}
+
+func TestWrappedZonelessTimeColumn(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	copts := compileOptions{timeLocation: loc, state: Default().state}
+	convert := copts.columnConverter(Column{Name: "at", DatabaseTypeName: "Nullable(DateTime)"}.known(), reflect.TypeOf(&time.Time{}), nil)
+
+	var act *time.Time
+	if err := convert(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), reflect.ValueOf(&act).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2020, 1, 2, 3, 4, 5, 0, loc); act == nil || !act.Equal(exp) {
+		t.Errorf("unexpected time: expected %v, actual %v", exp, act)
+	}
+}
@@ -0,0 +1,114 @@
+// +build clickhouse
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClickHouseArrays(t *testing.T) {
+	rows, err := db.Query(`SELECT toInt32(1) AS id, [toInt32(1), 2, 3] AS ids, ['a', 'b'] AS tags,
+		[toNullable(toInt64(1)), NULL] AS refs, [[toUInt8(1)], [2, 3]] AS nested`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id     int
+		Ids    []int64
+		Tags   []string
+		Refs   []*int
+		Nested [][]int
+	}
+	var valStructs []valStruct
+	if err := PropagateAndClose(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	one := 1
+	exp := []valStruct{{Id: 1, Ids: []int64{1, 2, 3}, Tags: []string{"a", "b"}, Refs: []*int{&one, nil}, Nested: [][]int{{1}, {2, 3}}}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestClickHouseNullable(t *testing.T) {
+	rows, err := db.Query(`SELECT toNullable(toInt64(number)) AS id, if(number = 0, NULL, toNullable('b')) AS name,
+		toNullable(toDateTime('2020-01-02 03:04:05', 'UTC')) AS created
+	FROM system.numbers LIMIT 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id      int64
+		Name    *string
+		Created *time.Time
+	}
+	var valStructs []valStruct
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	if err := PropagateAndClose(&valStructs, rows, WithTimeLocation(loc)); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, loc)
+	if len(valStructs) != 2 || valStructs[0].Name != nil || valStructs[1].Name == nil || *valStructs[1].Name != "b" {
+		t.Fatalf("unexpeted results of propagation: %+v", valStructs)
+	}
+	for _, act := range valStructs {
+		if act.Created == nil || !act.Created.Equal(created) {
+			t.Errorf("unexpected time: expected %v, actual %v", created, act.Created)
+		}
+	}
+}
+
+func TestClickHouseLowCardinality(t *testing.T) {
+	rows, err := db.Query("SELECT toLowCardinality('a') AS tag, toLowCardinality(toNullable('b')) AS ref")
+	if err != nil {
+		t.Skip("driver doesn't support LowCardinality columns: " + err.Error())
+	}
+
+	type valStruct struct {
+		Tag string
+		Ref *string
+	}
+	var valStructs []valStruct
+	if err := PropagateAndClose(&valStructs, rows, WithStrictColumnTypeCheck(false)); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Tag: "a", Ref: StringRef("b")}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+// numberSum sums the numbers without keeping them
+type numberSum struct {
+	count int
+	sum   uint64
+}
+
+func (ns *numberSum) Append(v interface{}) error {
+	ns.count++
+	ns.sum += v.(uint64)
+	return nil
+}
+
+func (ns *numberSum) ElementType() reflect.Type { return reflect.TypeOf(uint64(0)) }
+
+func TestClickHouseHugeResultSet(t *testing.T) {
+	const amount = 1000000
+	rows, err := db.Query("SELECT number FROM system.numbers LIMIT 1000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ns numberSum
+	if err := PropagateAndClose(&ns, rows); err != nil {
+		t.Fatal(err)
+	}
+	if ns.count != amount || ns.sum != amount*(amount-1)/2 {
+		t.Errorf("unexpected propagation of %d rows: count %d, sum %d", amount, ns.count, ns.sum)
+	}
+}
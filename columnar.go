@@ -0,0 +1,95 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isColumnarType returns true if t is a struct which fields are filled column-wise
+func (st *state) isColumnarType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !st.isSmallestStructDecomposition(t)
+}
+
+// createColumnarScanDefinition creates mapper for the struct of slices: each row is split into its columns and
+// value of the column is appended to the slice field that the column is mapped to
+func createColumnarScanDefinition(holderType reflect.Type, columnTypes []columnType, copts compileOptions) (scanDefinition, error) {
+	st := copts.state
+	columnAliasToField := map[string]reflect.StructField{}
+	for i := 0; i < holderType.NumField(); i++ {
+		field := holderType.Field(i)
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		columnAliasToField[st.fieldColumnAlias(holderType, field)] = field
+	}
+	if len(columnAliasToField) == 0 {
+		return scanDefinition{}, fmt.Errorf("struct of slices is expected as columnar destination, %v has no slice fields", holderType)
+	}
+
+	// nil field index means the column is skipped
+	fieldIndexes := make([][]int, len(columnTypes))
+	holderTypes := make([]reflect.Type, len(columnTypes))
+	converters := make([]converter, len(columnTypes))
+	for i, columnType := range columnTypes {
+		field, found := columnAliasToField[strings.ToLower(columnType.Name())]
+		if !found {
+			if copts.columnAmountCheck {
+				return scanDefinition{}, errors.New("no mapping exists for column/alias: " + columnType.Name())
+			}
+			continue
+		}
+
+		valueType := field.Type.Elem()
+		if copts.columnTypeCheck && columnType.ScanType() != valueType {
+			return scanDefinition{}, fmt.Errorf("value for column/alias: %v can't be stored into the type: %v; required type: %v", columnType.Name(), valueType, columnType.ScanType())
+		}
+		fieldIndexes[i] = field.Index
+		holderTypes[i] = valueType
+		_, options := st.fieldColumnTag(holderType, field)
+		converters[i] = copts.columnConverter(columnType, valueType, options)
+	}
+
+	mapper := func(dst interface{}, rows *sql.Rows) error {
+		dstValue := reflect.ValueOf(dst).Elem()
+
+		// holders are reused between rows as database/sql doesn't retain scan destinations,
+		// they are reset before each row, so values of reference types, e.g. decoded JSON, aren't shared
+		holders := make([]reflect.Value, len(columnTypes))
+		scanDestinations := make([]interface{}, len(columnTypes))
+		for i, holderType := range holderTypes {
+			if fieldIndexes[i] == nil {
+				scanDestinations[i] = copts.holderSkip()(reflect.Value{})
+				continue
+			}
+			holders[i] = reflect.New(holderType)
+			scanDestinations[i] = holders[i].Interface()
+			if converters[i] != nil {
+				scanDestinations[i] = &fieldScanner{column: columnTypes[i].Name(), field: holders[i].Elem(), convert: converters[i]}
+			}
+		}
+
+		for rows.Next() {
+			for i, holder := range holders {
+				if fieldIndexes[i] != nil {
+					holder.Elem().Set(reflect.Zero(holderTypes[i]))
+				}
+			}
+			if err := rows.Scan(scanDestinations...); err != nil {
+				return err
+			}
+
+			for i, holder := range holders {
+				if fieldIndexes[i] == nil {
+					continue
+				}
+				column := dstValue.FieldByIndex(fieldIndexes[i])
+				column.Set(reflect.Append(column, holder.Elem()))
+			}
+		}
+		return rows.Err()
+	}
+	return scanDefinition{mapper: mapper}, nil
+}
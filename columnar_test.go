@@ -0,0 +1,89 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagateColumnar(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c'), (3, 'd', 'e')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var columns struct {
+		IDs   []int    `db_column:"id"`
+		Names []string `db_column:"col1"`
+		Col2  []*string
+		Other string
+	}
+	if err := Propagate(&columns, rows); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(columns.IDs, []int{1, 2, 3}) {
+		t.Errorf("unexpeted results of propagation: %v", columns.IDs)
+	}
+	if !reflect.DeepEqual(columns.Names, []string{"a", "b", "d"}) {
+		t.Errorf("unexpeted results of propagation: %v", columns.Names)
+	}
+	if !reflect.DeepEqual(columns.Col2, []*string{nil, StringRef("c"), StringRef("e")}) {
+		t.Errorf("unexpeted results of propagation: %v", columns.Col2)
+	}
+}
+
+func TestPropagateColumnarReferences(t *testing.T) {
+	if name := driverName(); name == "sqlserver" || name == "clickhouse" {
+		t.Skip("no JSON column type")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "CREATE TEMPORARY TABLE columnar_json(id INTEGER, payload JSON)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO columnar_json(id, payload) VALUES (1, '{"a": 1}'), (2, '{"b": 2}')`); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT payload FROM columnar_json ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var columns struct {
+		Payloads []map[string]interface{} `db_column:"payload"`
+	}
+	if err := Propagate(&columns, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []map[string]interface{}{{"a": float64(1)}, {"b": float64(2)}}
+	if !reflect.DeepEqual(columns.Payloads, exp) {
+		t.Errorf("unexpeted results of propagation: expected %v, actual %v", exp, columns.Payloads)
+	}
+}
+
+func TestPropagateColumnarWithoutSlices(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var columns struct {
+		Id   int
+		Col1 string
+	}
+	if err := Propagate(&columns, rows); err == nil {
+		t.Error("error expected for the struct without slice fields")
+	}
+}
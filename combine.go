@@ -0,0 +1,133 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterCombiner registers combine to populate fields of type T (or references to it) from several columns,
+// e.g. Point from `lat` and `lon` or Money from `currency` and `amount`. For each row combine receives values of the
+// columns in order of columns, as returned by database driver, e.g. []byte, int64, time.Time or nil for NULL.
+// Combined columns are not mapped to other fields and the fields of T are not mapped to columns.
+// The field is left untouched if none of the columns is selected and it is an error if only some of them are.
+// Combiners should be registered before the first propagation, as compiled mappers are cached.
+// Registration of nil combine removes it.
+func RegisterCombiner[T any](columns []string, combine func(args ...interface{}) (T, error)) {
+	RegisterCombinerOn(Default(), columns, combine)
+}
+
+// RegisterCombinerOn is the same as RegisterCombiner for the mapper m
+func RegisterCombinerOn[T any](m *Mapper, columns []string, combine func(args ...interface{}) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	m.state.combiners.Lock()
+	defer m.state.combiners.Unlock()
+	if combine == nil {
+		delete(m.state.combiners.byType, t)
+		return
+	}
+
+	lowerColumns := make([]string, len(columns))
+	for i, column := range columns {
+		lowerColumns[i] = strings.ToLower(column)
+	}
+	m.state.combiners.byType[t] = constructor{
+		columns: lowerColumns,
+		construct: func(args []interface{}) (reflect.Value, error) {
+			v, err := combine(args...)
+			return reflect.ValueOf(&v).Elem(), err
+		},
+	}
+}
+
+func (st *state) combinerOf(t reflect.Type) (constructor, bool) {
+	st.combiners.RLock()
+	c, found := st.combiners.byType[t]
+	st.combiners.RUnlock()
+	return c, found
+}
+
+// isCombinedType returns true if fields of type t are populated by the registered combiner
+func (st *state) isCombinedType(t reflect.Type) bool {
+	_, found := st.combinerOf(derefType(t))
+	return found
+}
+
+// fieldCombiner populates the field from the values of the combined columns of the row
+type fieldCombiner struct {
+	fieldIndex []int
+	fieldPath  []string
+	// positions are indexes of the combined columns in the row
+	positions []int
+	combiner  constructor
+}
+
+// createFieldCombiners creates combiners of the fields of dstType for the selected columns
+func (st *state) createFieldCombiners(dstType reflect.Type, columnTypes []columnType) ([]fieldCombiner, error) {
+	columnPositions := make(map[string]int, len(columnTypes))
+	for i := len(columnTypes) - 1; i >= 0; i-- {
+		columnPositions[strings.ToLower(columnTypes[i].Name())] = i
+	}
+
+	var fieldCombiners []fieldCombiner
+	combinedBy := map[int][]string{}
+	err := st.visitLeafFields(derefType(dstType), nil, nil, func(owner reflect.Type, field reflect.StructField, fieldIndex []int, fieldPath []string) error {
+		combiner, found := st.combinerOf(derefType(field.Type))
+		if !found {
+			return nil
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("unexported field %s can't be combined", strings.Join(fieldPath, "."))
+		}
+
+		fc := fieldCombiner{fieldIndex: fieldIndex, fieldPath: fieldPath, combiner: combiner}
+		var missing []string
+		for _, column := range combiner.columns {
+			position, selected := columnPositions[column]
+			if !selected {
+				missing = append(missing, column)
+				continue
+			}
+			if previous, combined := combinedBy[position]; combined {
+				return fmt.Errorf("column/alias %s is combined into more than one field: %s and %s",
+					column, strings.Join(previous, "."), strings.Join(fieldPath, "."))
+			}
+			combinedBy[position] = fieldPath
+			fc.positions = append(fc.positions, position)
+		}
+		switch {
+		case len(missing) == len(combiner.columns):
+			// the field is not selected
+		case len(missing) != 0:
+			return fmt.Errorf("columns/aliases %v combined into field %s are missing", missing, strings.Join(fieldPath, "."))
+		default:
+			fieldCombiners = append(fieldCombiners, fc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fieldCombiners, nil
+}
+
+// combine populates the field of structValue with the values of the combined columns scanned into dest
+func (fc fieldCombiner) combine(structValue reflect.Value, dest []interface{}) error {
+	args := make([]interface{}, len(fc.positions))
+	for i, position := range fc.positions {
+		args[i] = *dest[position].(*interface{})
+	}
+
+	v, err := fc.combiner.construct(args)
+	if err != nil {
+		return fmt.Errorf("can't combine columns/aliases %v into field %s: %w", fc.combiner.columns, strings.Join(fc.fieldPath, "."), err)
+	}
+	field := structValue.FieldByIndex(fc.fieldIndex)
+	for v.Type() != field.Type() {
+		ref := reflect.New(v.Type())
+		ref.Elem().Set(v)
+		v = ref
+	}
+	field.Set(v)
+	return nil
+}
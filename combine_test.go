@@ -0,0 +1,76 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type point struct {
+	Lat, Lon string
+}
+
+func combinePoint(args ...interface{}) (point, error) {
+	if args[0] == nil || args[1] == nil {
+		return point{}, errors.New("coordinates are required")
+	}
+	return point{Lat: asString(args[0]), Lon: asString(args[1])}, nil
+}
+
+func TestRegisterCombiner(t *testing.T) {
+	RegisterCombiner[point]([]string{"col1", "COL2"}, combinePoint)
+	defer RegisterCombiner[point](nil, nil)
+
+	type place struct {
+		Id       int
+		Col1     string
+		Location *point
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '52.5', '13.4')",
+		"SELECT col2, id, col1 FROM propagation",
+	)
+	defer release()
+
+	var places []place
+	if err := Propagate(&places, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []place{{Id: 1, Location: &point{Lat: "52.5", Lon: "13.4"}}}
+	if !reflect.DeepEqual(places, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, places)
+	}
+}
+
+func TestRegisterCombinerErrors(t *testing.T) {
+	RegisterCombiner[point]([]string{"col1", "col2"}, combinePoint)
+	defer RegisterCombiner[point](nil, nil)
+
+	type place struct {
+		Id       int
+		Location point
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, '52.5')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var places []place
+	if err := Propagate(&places, rows); err == nil {
+		t.Error("error expected for the missing combined column")
+	}
+
+	rows, release = queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, '52.5')",
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	if err := Propagate(&places, rows); err == nil || !strings.Contains(err.Error(), "coordinates are required") {
+		t.Errorf("error of the combiner expected, actual: %v", err)
+	}
+}
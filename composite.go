@@ -0,0 +1,98 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// convertComposite stores textual representation of PostgreSQL composite value, e.g. `(1,foo,)` of ROW(1, 'foo', NULL),
+// into the fields of the struct by position. Empty attributes are NULL, fields of nested structs
+// receive nested composite values. It is used for fields with `composite` tag option: `db_column:"address,composite"`.
+func (st *state) convertComposite(src interface{}, dst reflect.Value) error {
+	if src == nil {
+		return fmt.Errorf("converting NULL to %v is unsupported", dst.Type())
+	}
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("composite value can't be stored into the type: %v", dst.Type())
+	}
+
+	text := asString(src)
+	attributes, err := parseComposite(text)
+	if err != nil {
+		return &ParseError{Value: text, Type: dst.Type(), Err: err}
+	}
+	if len(attributes) > dst.NumField() {
+		return &ParseError{Value: text, Type: dst.Type(), Err: fmt.Errorf("%d attributes for %d fields", len(attributes), dst.NumField())}
+	}
+
+	for i, attribute := range attributes {
+		field := dst.Field(i)
+		convert := convertReference(convertDefault)
+		if fieldType := derefType(field.Type()); fieldType.Kind() == reflect.Struct && !st.isSmallestStructDecomposition(fieldType) {
+			convert = convertReference(st.convertComposite)
+		}
+
+		var attributeSrc interface{}
+		if attribute != nil {
+			attributeSrc = *attribute
+		}
+		if err := convert(attributeSrc, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// parseComposite splits composite value into attributes, nil is for NULL attributes.
+// Attributes may be double-quoted, quotes inside are escaped by doubling or with backslash.
+func parseComposite(text string) ([]*string, error) {
+	if len(text) < 2 || text[0] != '(' || text[len(text)-1] != ')' {
+		return nil, errors.New("composite value must be enclosed in parentheses")
+	}
+	body := text[1 : len(text)-1]
+
+	var attributes []*string
+	var attribute strings.Builder
+	quoted, present := false, false
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case quoted && c == '"' && i+1 < len(body) && body[i+1] == '"':
+			attribute.WriteByte('"')
+			i++
+		case c == '"':
+			quoted, present = !quoted, true
+		case c == '\\' && i+1 < len(body):
+			attribute.WriteByte(body[i+1])
+			present = true
+			i++
+		case c == ',' && !quoted:
+			attributes = append(attributes, compositeAttribute(&attribute, present))
+			present = false
+		default:
+			attribute.WriteByte(c)
+			present = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quoted attribute")
+	}
+	return append(attributes, compositeAttribute(&attribute, present)), nil
+}
+
+func compositeAttribute(attribute *strings.Builder, present bool) *string {
+	defer attribute.Reset()
+	if !present {
+		return nil
+	}
+	value := attribute.String()
+	return &value
+}
@@ -0,0 +1,28 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseComposite(t *testing.T) {
+	for text, exp := range map[string][]*string{
+		`(1,foo,)`:              {StringRef("1"), StringRef("foo"), nil},
+		`(,"a ""b"" c","x\\y")`: {nil, StringRef(`a "b" c`), StringRef(`x\y`)},
+		`("(1,2)","")`:          {StringRef("(1,2)"), StringRef("")},
+	} {
+		act, err := parseComposite(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(act, exp) {
+			t.Errorf("unexpected attributes of %s: expected %v, actual %v", text, exp, act)
+		}
+	}
+
+	for _, text := range []string{"1,foo", `("foo)`} {
+		if _, err := parseComposite(text); err == nil {
+			t.Errorf("error expected for malformed composite value: %s", text)
+		}
+	}
+}
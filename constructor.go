@@ -0,0 +1,111 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// constructor creates the value of the registered type from the values of its columns
+type constructor struct {
+	columns   []string
+	construct func(args []interface{}) (reflect.Value, error)
+}
+
+// RegisterConstructor registers construct to create values of T (or references to it) for immutable types
+// that have no settable fields. For each row construct receives values of the columns in order of columns,
+// as returned by database driver, e.g. []byte, int64, time.Time or nil for NULL. Other columns of the rows are
+// skipped unless StrictColumnAmountCheck is enabled. Constructors should be registered before the first propagation
+// into T, as compiled mappers are cached. Registration of nil construct removes it.
+func RegisterConstructor[T any](columns []string, construct func(args ...interface{}) (T, error)) {
+	RegisterConstructorOn(Default(), columns, construct)
+}
+
+// RegisterConstructorOn is the same as RegisterConstructor for the mapper m
+func RegisterConstructorOn[T any](m *Mapper, columns []string, construct func(args ...interface{}) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	m.state.constructors.Lock()
+	defer m.state.constructors.Unlock()
+	if construct == nil {
+		delete(m.state.constructors.byType, t)
+		return
+	}
+
+	lowerColumns := make([]string, len(columns))
+	for i, column := range columns {
+		lowerColumns[i] = strings.ToLower(column)
+	}
+	m.state.constructors.byType[t] = constructor{
+		columns: lowerColumns,
+		construct: func(args []interface{}) (reflect.Value, error) {
+			v, err := construct(args...)
+			return reflect.ValueOf(&v).Elem(), err
+		},
+	}
+}
+
+func (st *state) constructorOf(t reflect.Type) (constructor, bool) {
+	st.constructors.RLock()
+	c, found := st.constructors.byType[t]
+	st.constructors.RUnlock()
+	return c, found
+}
+
+// constructorScanner creates row scanner that passes values of the constructor columns to the constructor of forType,
+// forType is the type of the constructor or reference to it
+func constructorScanner(forType reflect.Type, c constructor, columnTypes []columnType, copts compileOptions) (func(unmappedColumnFunc) rowScanner, error) {
+	// positions of the constructor arguments in the row, -1 for skipped columns
+	positions := make([]int, len(columnTypes))
+	found := make([]bool, len(c.columns))
+	for i, columnType := range columnTypes {
+		positions[i] = -1
+		for j, column := range c.columns {
+			if column == strings.ToLower(columnType.Name()) && !found[j] {
+				positions[i], found[j] = j, true
+				break
+			}
+		}
+		if positions[i] == -1 && copts.columnAmountCheck {
+			return nil, errors.New("no mapping exists for column/alias: " + columnType.Name())
+		}
+	}
+	for j, column := range c.columns {
+		if !found[j] {
+			return nil, fmt.Errorf("column/alias %s of the constructor of %v is missing", column, derefType(forType))
+		}
+	}
+
+	return func(onUnmapped unmappedColumnFunc) rowScanner {
+		holderSkip := copts.holderSkip()
+		return func(rows *sql.Rows) (reflect.Value, error) {
+			args := make([]interface{}, len(c.columns))
+			dest := make([]interface{}, len(positions))
+			for i, position := range positions {
+				switch {
+				case position == -1 && onUnmapped != nil:
+					dest[i] = &unmappedColumnScanner{column: columnTypes[i].Name(), onUnmapped: onUnmapped}
+				case position == -1:
+					dest[i] = holderSkip(reflect.Value{})
+				default:
+					dest[i] = &args[position]
+				}
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return reflect.Value{}, err
+			}
+
+			v, err := c.construct(args)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			for v.Type() != forType {
+				ref := reflect.New(v.Type())
+				ref.Elem().Set(v)
+				v = ref
+			}
+			return v, nil
+		}
+	}, nil
+}
@@ -0,0 +1,69 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type money struct {
+	amount   int64
+	currency string
+}
+
+func newMoney(args ...interface{}) (money, error) {
+	if args[1] == nil {
+		return money{}, errors.New("currency is required")
+	}
+	var amount int64
+	if _, err := fmt.Sscan(asString(args[0]), &amount); err != nil {
+		return money{}, err
+	}
+	return money{amount: amount, currency: asString(args[1])}, nil
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	RegisterConstructor[money]([]string{"ID", "col1"}, newMoney)
+	defer RegisterConstructor[money](nil, nil)
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'EUR', 'a'), (2, 'USD', 'b')",
+		"SELECT col2, col1, id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var amounts []*money
+	if err := Propagate(&amounts, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*money{{amount: 1, currency: "EUR"}, {amount: 2, currency: "USD"}}
+	if !reflect.DeepEqual(amounts, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, amounts)
+	}
+}
+
+func TestRegisterConstructorErrors(t *testing.T) {
+	RegisterConstructor[money]([]string{"id", "col2"}, newMoney)
+	defer RegisterConstructor[money](nil, nil)
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'EUR')",
+		"SELECT id, col2 FROM propagation",
+	)
+	defer release()
+
+	var amounts []money
+	if err := Propagate(&amounts, rows); err == nil || err.Error() != "currency is required" {
+		t.Errorf("error of the constructor expected, actual: %v", err)
+	}
+
+	rows, release = queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'EUR')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+	if err := Propagate(&amounts, rows); err == nil {
+		t.Error("error expected for the missing column of the constructor")
+	}
+}
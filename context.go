@@ -0,0 +1,46 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+)
+
+type contextOptionsKey struct{}
+
+// WithContextOptions returns a copy of ctx that carries opts, so they can be enabled for specific requests,
+// e.g. by middleware, without changing global configuration. Options attached to the parent context are kept
+// and opts are applied after them. The options are used by PropagateContext and Select.
+func WithContextOptions(ctx context.Context, opts ...Option) context.Context {
+	inherited := contextOptions(ctx)
+	combined := make([]Option, 0, len(inherited)+len(opts))
+	combined = append(append(combined, inherited...), opts...)
+	return context.WithValue(ctx, contextOptionsKey{}, combined)
+}
+
+// WithContextStrictChecks returns a copy of ctx that overrides StrictColumnTypeCheck and StrictColumnAmountCheck,
+// e.g. to enable strict checks for a single request of the debugging endpoint in production
+func WithContextStrictChecks(ctx context.Context, strict bool) context.Context {
+	return WithContextOptions(ctx, WithStrictColumnTypeCheck(strict), WithStrictColumnAmountCheck(strict))
+}
+
+// WithContextLogger returns a copy of ctx that reports the events of propagation to l instead of the logger configured
+// with SetLogger, so logging can be enabled for a single request
+func WithContextLogger(ctx context.Context, l Logger) context.Context {
+	return WithContextOptions(ctx, WithLogger(l))
+}
+
+func contextOptions(ctx context.Context) []Option {
+	opts, _ := ctx.Value(contextOptionsKey{}).([]Option)
+	return opts
+}
+
+// PropagateContext is Propagate that applies options attached to ctx with WithContextOptions before opts.
+func PropagateContext(ctx context.Context, dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return Default().PropagateContext(ctx, dst, rows, opts...)
+}
+
+// PropagateContext is the same as PropagateContext of the package with the options of the mapper,
+// the options attached to ctx are applied after the ones of the mapper
+func (m *Mapper) PropagateContext(ctx context.Context, dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return propagate(dst, rows, m.newOptions(append(contextOptions(ctx), opts...)))
+}
@@ -0,0 +1,80 @@
+package rowconv
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestPropagateContext(t *testing.T) {
+	type valStruct struct {
+		Id int
+	}
+	propagate := func(ctx context.Context) error {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+			"SELECT id, col1 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		return PropagateContext(ctx, &valStructs, rows)
+	}
+
+	if err := propagate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := propagate(WithContextOptions(context.Background(), WithStrictColumnAmountCheck(true))); err == nil {
+		t.Error("error expected for column without mapping with strict check enabled by context")
+	}
+}
+
+func TestWithContextOptionsInheritance(t *testing.T) {
+	parent := WithContextOptions(context.Background(), WithStrictColumnTypeCheck(true))
+	child := WithContextOptions(parent, WithStrictColumnAmountCheck(true), WithStrictColumnTypeCheck(false))
+
+	if opts := newOptions(contextOptions(parent)); !opts.compile.columnTypeCheck || opts.compile.columnAmountCheck {
+		t.Errorf("unexpected options of parent context: %+v", opts.compile)
+	}
+	if opts := newOptions(contextOptions(child)); opts.compile.columnTypeCheck || !opts.compile.columnAmountCheck {
+		t.Errorf("unexpected options of child context: %+v", opts.compile)
+	}
+}
+
+func TestWithContextStrictChecks(t *testing.T) {
+	ctx := WithContextStrictChecks(context.Background(), true)
+	if opts := newOptions(contextOptions(ctx)); !opts.compile.columnTypeCheck || !opts.compile.columnAmountCheck {
+		t.Errorf("strict checks expected to be enabled by context: %+v", opts.compile)
+	}
+	ctx = WithContextStrictChecks(ctx, false)
+	if opts := newOptions(contextOptions(ctx)); opts.compile.columnTypeCheck || opts.compile.columnAmountCheck {
+		t.Errorf("strict checks expected to be disabled by child context: %+v", opts.compile)
+	}
+}
+
+func TestWithContextLogger(t *testing.T) {
+	var logged strings.Builder
+	ctx := WithContextLogger(context.Background(), log.New(&logged, "", 0))
+	if opts := newOptions(contextOptions(ctx)); opts.log() == nil {
+		t.Fatal("logger of the context expected")
+	}
+	if opts := newOptions(nil); opts.log() != nil {
+		t.Error("no logger expected without context")
+	}
+}
+
+func TestMapperPropagateContext(t *testing.T) {
+	mapper := NewMapper()
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var ids []int
+	ctx := WithContextStrictChecks(context.Background(), true)
+	if err := mapper.PropagateContext(ctx, &ids, rows); err == nil {
+		t.Error("error expected for column without mapping with strict check enabled by context")
+	}
+}
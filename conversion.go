@@ -0,0 +1,502 @@
+package rowconv
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// compileOptions are options that affect compiled scan definitions, so they are part of the cache key
+type compileOptions struct {
+	columnTypeCheck   bool
+	columnAmountCheck bool
+	lenientNumbers    bool
+	trimStrings       bool
+	emptyAsNull       bool
+	timeLocation      *time.Location
+	fractionPolicy    FractionPolicy
+	rawBytes          bool
+	typeAffinity      bool
+	mapping           *Mapping
+	interceptor       *interceptor
+	tagKeys           string
+	moneyFormat       MoneyFormat
+	timePrecision     time.Duration
+	noCache           bool
+	allocationBlock   int
+	missingField      *missingFieldPolicy
+	fieldErrors       fieldErrorsMode
+	// state is the state of the mapper the propagation is made with
+	state *state
+}
+
+// converter stores value returned by database driver into the field
+type converter func(src interface{}, dst reflect.Value) error
+
+// fieldScanner is a scan destination that stores the value into the field with the converter
+type fieldScanner struct {
+	column  string
+	field   reflect.Value
+	convert converter
+	// details are set if the errors are reported with FieldError
+	details *fieldDetails
+}
+
+func (fs *fieldScanner) Scan(src interface{}) (err error) {
+	defer recoverColumnPanic(fs.column, &err)
+
+	err = fs.convert(src, fs.field)
+	if parseErr, ok := err.(*ParseError); ok && parseErr.Column == "" {
+		parseErr.Column = fs.column
+	}
+	if err != nil && fs.details != nil {
+		return fs.details.fieldError(fs.column, src, err)
+	}
+	return err
+}
+
+// ParseError is returned when value of the column can't be parsed into the value of the field type
+type ParseError struct {
+	// Column is a name of the column/alias
+	Column string
+	// Value is the value returned by database driver
+	Value string
+	// Type is a type of the field
+	Type reflect.Type
+	// Err is the cause of the failure
+	Err error
+}
+
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("value %q of column/alias: %v can't be parsed into the type: %v: %v", pe.Value, pe.Column, pe.Type, pe.Err)
+}
+
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// converter returns converter for the field of forType with the options of its tag,
+// nil is returned if database/sql conversion should be used
+func (copts compileOptions) converter(forType reflect.Type, fieldOptions []string) converter {
+	if name, found := scannerOption(fieldOptions); found {
+		return copts.state.namedScannerConverter(name)
+	}
+	if hasOption(fieldOptions, "composite") {
+		return convertReference(copts.state.convertComposite)
+	}
+	if hasOption(fieldOptions, "xml") {
+		return convertReference(convertXML)
+	}
+
+	valueType := forType
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if hasOption(fieldOptions, "money") {
+		return convertReference(moneyConverter(valueType, copts.moneyFormat))
+	}
+	if reflect.PtrTo(valueType).Implements(scannerType) {
+		// the scanner is called by the converter so that its panic doesn't escape the scan
+		return convertReference(convertScanner)
+	}
+
+	var transforms []func(src interface{}) interface{}
+	var assign converter
+	decodeBinary := binaryDecoder(fieldOptions)
+	convertEnum := copts.state.enumConverter(valueType, fieldOptions)
+	layout, hasLayout := layoutOption(fieldOptions)
+	switch {
+	case convertEnum != nil:
+		assign = convertEnum
+	case decodeBinary != nil && valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8:
+		assign = decodeBinary
+	case valueType == durationType:
+		assign = convertDuration
+	case valueType == timeType && hasLayout:
+		assign = layoutTimeConverter(layout, copts.timeLocation)
+	case valueType == timeType && copts.typeAffinity:
+		assign = convertAffinityTime
+	case isNetworkType(valueType):
+		assign = convertDefault
+	case isNumberKind(valueType.Kind()):
+		if copts.lenientNumbers {
+			assign = convertLenientNumber
+		}
+		if isIntegerKind(valueType.Kind()) && copts.fractionPolicy != FractionError {
+			if assign == nil {
+				assign = convertDefault
+			}
+			assign = copts.fractionPolicy.converter(assign)
+		}
+	case valueType.Kind() == reflect.String && hasOption(fieldOptions, "uuid"):
+		assign = convertUUID
+	case valueType.Kind() == reflect.String:
+		if copts.trimStrings || hasOption(fieldOptions, "trim") {
+			transforms = append(transforms, trimRightSpaces)
+		}
+	}
+
+	emptyAsNull := copts.emptyAsNull || hasOption(fieldOptions, "emptynull")
+	onNull, hasNullPolicy := copts.state.nullPolicy(forType)
+	if transforms == nil && assign == nil && !emptyAsNull && !hasNullPolicy {
+		return nil
+	}
+	if assign == nil {
+		assign = convertDefault
+	}
+	assign = convertReference(assign)
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil && hasNullPolicy {
+			return onNull(dst)
+		}
+		for _, transform := range transforms {
+			src = transform(src)
+		}
+		if emptyAsNull && isEmptyText(src) {
+			// nil for references and zero value for values
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		return assign(src, dst)
+	}
+}
+
+// columnConverter returns converter for the field of forType the column is mapped to,
+// nil is returned if database/sql conversion should be used
+func (copts compileOptions) columnConverter(columnType columnType, forType reflect.Type, fieldOptions []string) converter {
+	convert := copts.converter(forType, fieldOptions)
+	if _, found := scannerOption(fieldOptions); found {
+		// the scanner of the field receives the value as returned by database driver
+		return copts.intercepted(columnType.Name(), convert)
+	}
+	if convert == nil && isArrayColumn(columnType) && isArrayTarget(forType) {
+		convert = convertReference(convertArray)
+	}
+	if isUniqueIdentifierColumn(columnType) && derefType(forType).Kind() == reflect.String {
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		convert = convertBefore(canonicalUniqueIdentifier, convert)
+	}
+	if isJSONColumn(columnType) && copts.state.isJSONTarget(forType) && !isWholeValueField(fieldOptions) {
+		convert = convertReference(convertJSON)
+	}
+	if isYearColumn(columnType) && derefType(forType) == timeType {
+		convert = convertReference(yearConverter(copts.timeLocation))
+	}
+	if convert == nil && isBitColumn(columnType) && derefType(forType).Kind() == reflect.Bool {
+		// database/sql can't store BIT(1) values into bool fields
+		convert = convertReference(convertDefault)
+	}
+	if copts.timeLocation != nil && derefType(forType) == timeType && isZonelessTimeColumn(columnType) {
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		loc := copts.timeLocation
+		convert = convertBefore(func(src interface{}) (interface{}, error) { return inLocation(src, loc), nil }, convert)
+	}
+
+	if transform, found := copts.state.databaseTypeConverterOf(databaseTypeName(columnType)); found {
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		convert = convertBefore(transform, convert)
+	}
+	if chain := copts.state.converterChain(databaseTypeName(columnType), forType); len(chain) > 0 {
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		convert = convertChain(chain, convert)
+	}
+	if copts.timePrecision > 0 && derefType(forType) == timeType {
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		convert = truncatedTime(copts.timePrecision, convert)
+	}
+	return copts.intercepted(columnType.Name(), convert)
+}
+
+// convertReference adapts convert to fields of reference types: NULL is stored as nil and
+// other values are converted into the newly allocated value
+func convertReference(convert converter) converter {
+	var convertRef converter
+	convertRef = func(src interface{}, dst reflect.Value) error {
+		if dst.Kind() != reflect.Ptr {
+			return convert(src, dst)
+		}
+		if src == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		value := reflect.New(dst.Type().Elem())
+		if err := convertRef(src, value.Elem()); err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+	}
+	return convertRef
+}
+
+// convertScanner stores src into dst implementing sql.Scanner with pointer receiver
+func convertScanner(src interface{}, dst reflect.Value) error {
+	return dst.Addr().Interface().(sql.Scanner).Scan(src)
+}
+
+// convertDefault stores src into dst the same way database/sql does for the basic types,
+// numbers are parsed strictly, e.g. "1e3" isn't accepted for the field of integer type
+func convertDefault(src interface{}, dst reflect.Value) error {
+	if src == nil {
+		return fmt.Errorf("converting NULL to %v is unsupported", dst.Type())
+	}
+	if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	if convert, found := networkConverters[dst.Type()]; found {
+		return convert(src, dst)
+	}
+
+	switch kind := dst.Kind(); {
+	case kind == reflect.String:
+		dst.SetString(asString(src))
+		return nil
+	case kind == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		switch value := src.(type) {
+		case []byte:
+			dst.SetBytes(append([]byte(nil), value...))
+		default:
+			dst.SetBytes([]byte(asString(src)))
+		}
+		return nil
+	case kind == reflect.Bool:
+		switch value := src.(type) {
+		case bool:
+			dst.SetBool(value)
+		case int64:
+			dst.SetBool(value != 0)
+		case []byte:
+			// BIT(1) of MySQL is returned as a single raw byte
+			if len(value) == 1 && value[0] <= 1 {
+				dst.SetBool(value[0] == 1)
+				return nil
+			}
+			b, err := strconv.ParseBool(string(value))
+			if err != nil {
+				return &ParseError{Value: string(value), Type: dst.Type(), Err: err}
+			}
+			dst.SetBool(b)
+		default:
+			b, err := strconv.ParseBool(asString(src))
+			if err != nil {
+				return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+			}
+			dst.SetBool(b)
+		}
+		return nil
+	case isNumberKind(kind):
+		return convertNumber(src, dst)
+	}
+
+	srcValue := reflect.ValueOf(src)
+	switch {
+	case srcValue.Type().AssignableTo(dst.Type()):
+		dst.Set(srcValue)
+	case srcValue.Type().ConvertibleTo(dst.Type()):
+		dst.Set(srcValue.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("unsupported conversion of %T into %v", src, dst.Type())
+	}
+	return nil
+}
+
+// asBytes returns textual representation of the value returned by database driver,
+// bytes are returned without copying, so they must not be retained
+func asBytes(src interface{}) []byte {
+	if value, ok := src.([]byte); ok {
+		return value
+	}
+	return []byte(asString(src))
+}
+
+// asString returns textual representation of the value returned by database driver
+func asString(src interface{}) string {
+	switch value := src.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func isEmptyText(src interface{}) bool {
+	switch value := src.(type) {
+	case []byte:
+		return len(value) == 0
+	case string:
+		return value == ""
+	default:
+		return false
+	}
+}
+
+func trimRightSpaces(src interface{}) interface{} {
+	switch value := src.(type) {
+	case []byte:
+		return bytes.TrimRightFunc(value, unicode.IsSpace)
+	case string:
+		return strings.TrimRightFunc(value, unicode.IsSpace)
+	default:
+		return src
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrOverflow is the cause of *ParseError when the number doesn't fit into the field, e.g. 300 into int8
+var ErrOverflow = errors.New("value overflows the type")
+
+// convertLenientNumber stores numbers and numeric strings into the field of number type.
+// Strings may be surrounded by whitespaces and integer fields accept whole numbers in floating point notation.
+func convertLenientNumber(src interface{}, dst reflect.Value) error {
+	var text string
+	switch value := src.(type) {
+	case nil:
+		return fmt.Errorf("converting NULL to %v is unsupported", dst.Type())
+	case int64:
+		return parseErrorOf(setNumber(dst, float64(value), value, true), src, dst)
+	case float64:
+		return parseErrorOf(setNumber(dst, value, int64(value), value == math.Trunc(value) && math.Abs(value) < 1<<63), src, dst)
+	case bool:
+		if value {
+			return setNumber(dst, 1, 1, true)
+		}
+		return setNumber(dst, 0, 0, true)
+	case []byte:
+		text = string(value)
+	case string:
+		text = value
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	text = strings.TrimSpace(text)
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return parseErrorOf(setNumber(dst, float64(i), i, true), text, dst)
+	}
+	if dst.Kind() >= reflect.Uint && dst.Kind() <= reflect.Uint64 {
+		if u, err := strconv.ParseUint(text, 10, 64); err == nil {
+			if dst.OverflowUint(u) {
+				return &ParseError{Value: text, Type: dst.Type(), Err: ErrOverflow}
+			}
+			dst.SetUint(u)
+			return nil
+		}
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return &ParseError{Value: text, Type: dst.Type(), Err: err}
+	}
+	return parseErrorOf(setNumber(dst, f, int64(f), f == math.Trunc(f) && math.Abs(f) < 1<<63), text, dst)
+}
+
+// convertNumber stores src into the field of number type the same way database/sql does: textual representation
+// of src is parsed with the size of the field, see WithLenientNumbers for the relaxed parsing
+func convertNumber(src interface{}, dst reflect.Value) error {
+	if value, ok := src.(int64); ok && isIntegerKind(dst.Kind()) {
+		return parseErrorOf(setNumber(dst, float64(value), value, true), src, dst)
+	}
+
+	text := asString(src)
+	switch kind := dst.Kind(); {
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, dst.Type().Bits())
+		if err != nil {
+			return numberParseError(text, dst, err)
+		}
+		dst.SetInt(i)
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, dst.Type().Bits())
+		if err != nil {
+			return numberParseError(text, dst, err)
+		}
+		dst.SetUint(u)
+	default:
+		f, err := strconv.ParseFloat(text, dst.Type().Bits())
+		if err != nil {
+			return numberParseError(text, dst, err)
+		}
+		dst.SetFloat(f)
+	}
+	return nil
+}
+
+// numberParseError wraps err of strconv parsing of the text into *ParseError, ErrOverflow is the cause for out of range values
+func numberParseError(text string, dst reflect.Value, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		err = ErrOverflow
+	}
+	return &ParseError{Value: text, Type: dst.Type(), Err: err}
+}
+
+// parseErrorOf wraps err of storing src into dst into *ParseError
+func parseErrorOf(err error, src interface{}, dst reflect.Value) error {
+	if err == nil {
+		return nil
+	}
+	return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+}
+
+// setNumber stores the number into dst; i holds integer value of f when whole is true
+func setNumber(dst reflect.Value, f float64, i int64, whole bool) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if dst.OverflowFloat(f) {
+			return ErrOverflow
+		}
+		dst.SetFloat(f)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !whole {
+			return fmt.Errorf("%v is not a whole number", f)
+		}
+		if dst.OverflowInt(i) {
+			return ErrOverflow
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !whole || i < 0 {
+			return fmt.Errorf("%v is not a non-negative whole number", f)
+		}
+		if dst.OverflowUint(uint64(i)) {
+			return ErrOverflow
+		}
+		dst.SetUint(uint64(i))
+		return nil
+	default:
+		return fmt.Errorf("unsupported number type: %v", dst.Type())
+	}
+}
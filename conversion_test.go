@@ -0,0 +1,241 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPropagateWithLenientNumbers(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, ' 42 ', '3.14'), (2, '7.0', NULL)",
+		"SELECT col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 int
+		Col2 *float64
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows, WithLenientNumbers()); err != nil {
+		t.Fatal(err)
+	}
+	pi := 3.14
+	exp := []valStruct{{Col1: 42, Col2: &pi}, {Col1: 7}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithLenientNumbersMalformed(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, '4.5'), (2, 'abc')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var col1s []int
+	err := Propagate(&col1s, rows, WithLenientNumbers())
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("parse error expected, received: %v", err)
+	}
+	if parseErr.Column != "col1" || parseErr.Value != "4.5" || parseErr.Type != reflect.TypeOf(0) {
+		t.Errorf("unexpected parse error: %+v", parseErr)
+	}
+}
+
+func TestPropagateWithTrimmedStrings(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a  ', ' b '), (2, 'c', NULL)",
+		"SELECT col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 string
+		Col2 *string
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows, WithTrimmedStrings()); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Col1: "a", Col2: StringRef(" b")}, {Col1: "c"}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithTrimTagOption(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a  ', 'b  ')",
+		"SELECT col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 string `db_column:",trim"`
+		Col2 string
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Col1: "a", Col2: "b  "}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithEmptyAsNull(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '', ''), (2, '5', 'b')",
+		"SELECT col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 int
+		Col2 *string
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows, WithEmptyAsNull()); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{}, {Col1: 5, Col2: StringRef("b")}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithEmptyNullTagOption(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '', '')",
+		"SELECT col1, col2 FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 *string
+		Col2 *string `db_column:",emptynull"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Col1: StringRef("")}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestConvertBit(t *testing.T) {
+	for src, exp := range map[string]bool{"\x00": false, "\x01": true, "1": true, "0": false, "true": true} {
+		var act bool
+		if err := convertDefault([]byte(src), reflect.ValueOf(&act).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Errorf("unexpected result of conversion of %q: expected %v, actual %v", src, exp, act)
+		}
+	}
+
+	var b bool
+	if err := convertDefault([]byte{2}, reflect.ValueOf(&b).Elem()); err == nil {
+		t.Error("error expected for value that is not a bit")
+	}
+}
+
+func TestConvertOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		src interface{}
+		dst interface{}
+	}{
+		{src: int64(300), dst: new(int8)},
+		{src: int64(-40000), dst: new(int16)},
+		{src: "5000000000", dst: new(int32)},
+		{src: []byte("256"), dst: new(uint8)},
+		{src: "1e40", dst: new(float32)},
+	} {
+		err := convertLenientNumber(tc.src, reflect.ValueOf(tc.dst).Elem())
+		var parseErr *ParseError
+		if !errors.Is(err, ErrOverflow) || !errors.As(err, &parseErr) {
+			t.Errorf("overflow error expected for %v into %T, actual: %v", tc.src, tc.dst, err)
+		}
+	}
+
+	var i int8
+	if err := convertLenientNumber(int64(127), reflect.ValueOf(&i).Elem()); err != nil || i != 127 {
+		t.Errorf("unexpected result of conversion: %v, error: %v", i, err)
+	}
+}
+
+func TestConvertDefaultNumber(t *testing.T) {
+	for _, src := range []interface{}{"1e3", []byte("3.0"), " 7", "7 ", float64(2.5), true} {
+		var i int64
+		if err := convertDefault(src, reflect.ValueOf(&i).Elem()); err == nil {
+			t.Errorf("error expected for %v into int64, actual: %v", src, i)
+		}
+	}
+
+	for _, tc := range []struct {
+		src interface{}
+		dst interface{}
+		exp interface{}
+	}{
+		{src: []byte("-42"), dst: new(int32), exp: int32(-42)},
+		{src: "42", dst: new(uint16), exp: uint16(42)},
+		{src: float64(3), dst: new(int), exp: 3},
+		{src: []byte("1e3"), dst: new(float64), exp: float64(1000)},
+		{src: int64(5), dst: new(float32), exp: float32(5)},
+	} {
+		dst := reflect.ValueOf(tc.dst).Elem()
+		if err := convertDefault(tc.src, dst); err != nil {
+			t.Errorf("unexpected error for %v into %T: %v", tc.src, tc.dst, err)
+			continue
+		}
+		if act := dst.Interface(); act != tc.exp {
+			t.Errorf("unexpected result of conversion of %v: expected %v, actual %v", tc.src, tc.exp, act)
+		}
+	}
+
+	var i8 int8
+	var parseErr *ParseError
+	if err := convertDefault("300", reflect.ValueOf(&i8).Elem()); !errors.Is(err, ErrOverflow) || !errors.As(err, &parseErr) {
+		t.Errorf("overflow error expected, actual: %v", err)
+	}
+}
+
+func TestPropagateIntegerIntoBool(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT 2 AS flag FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Flag bool
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err == nil {
+		t.Errorf("error expected for the integer that is not a bool, actual: %+v", valStructs)
+	}
+}
+
+func TestBitColumnConverter(t *testing.T) {
+	copts := compileOptions{state: Default().state}
+	boolType := reflect.TypeOf(false)
+	if convert := copts.columnConverter(Column{Name: "flag", DatabaseTypeName: "INTEGER"}.known(), boolType, nil); convert != nil {
+		t.Error("database/sql conversion expected for the bool field of the column that is not BIT")
+	}
+
+	convert := copts.columnConverter(Column{Name: "flag", DatabaseTypeName: "BIT"}.known(), boolType, nil)
+	if convert == nil {
+		t.Fatal("converter expected for the bool field of BIT column")
+	}
+	var flag bool
+	if err := convert([]byte{1}, reflect.ValueOf(&flag).Elem()); err != nil || !flag {
+		t.Errorf("unexpected result of conversion of BIT value: %v, error: %v", flag, err)
+	}
+}
@@ -0,0 +1,129 @@
+package rowconv
+
+import (
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CoverageRecorder records which fields of the element types are mapped to the columns of the propagated queries,
+// so test suites can find dead columns and stale struct fields that no query ever populates, see RecordCoverage
+type CoverageRecorder struct {
+	byType map[reflect.Type]*typeCoverage
+	sync.Mutex
+}
+
+// typeCoverage holds the paths of the fields of the element type that can be mapped and the ones that are mapped
+type typeCoverage struct {
+	fields    []string
+	populated map[string]struct{}
+}
+
+type coverageHolder struct {
+	*CoverageRecorder
+}
+
+// NewCoverageRecorder creates recorder without records
+func NewCoverageRecorder() *CoverageRecorder {
+	return &CoverageRecorder{byType: map[reflect.Type]*typeCoverage{}}
+}
+
+// RecordCoverage makes all propagations into structs record the fields populated from the columns into r,
+// nil stops the recording. It is meant for test runs, as each propagation pays for resolving the mapping again:
+//
+//	func TestMain(m *testing.M) {
+//		coverage := rowconv.NewCoverageRecorder()
+//		rowconv.RecordCoverage(coverage)
+//		code := m.Run()
+//		for t, fields := range coverage.Unpopulated() {
+//			log.Printf("fields of %v are never populated: %v", t, fields)
+//		}
+//		os.Exit(code)
+//	}
+func RecordCoverage(r *CoverageRecorder) {
+	Default().RecordCoverage(r)
+}
+
+// RecordCoverage is the same as RecordCoverage of the package for the mapper
+func (m *Mapper) RecordCoverage(r *CoverageRecorder) {
+	m.state.coverage.Store(coverageHolder{CoverageRecorder: r})
+}
+
+func (st *state) coverageRecorder() *CoverageRecorder {
+	return st.coverage.Load().(coverageHolder).CoverageRecorder
+}
+
+// Populated returns the paths of the fields of the struct t (or reference to it), e.g. "Address.City",
+// mapped so far in sorted order
+func (r *CoverageRecorder) Populated(t reflect.Type) []string {
+	r.Lock()
+	defer r.Unlock()
+	tc, found := r.byType[derefType(t)]
+	if !found {
+		return nil
+	}
+	populated := make([]string, 0, len(tc.populated))
+	for path := range tc.populated {
+		populated = append(populated, path)
+	}
+	sort.Strings(populated)
+	return populated
+}
+
+// Unpopulated returns the paths of the exported fields never mapped to any column for each recorded struct type,
+// the types which all fields are mapped are omitted
+func (r *CoverageRecorder) Unpopulated() map[reflect.Type][]string {
+	r.Lock()
+	defer r.Unlock()
+	unpopulated := map[reflect.Type][]string{}
+	for t, tc := range r.byType {
+		for _, path := range tc.fields {
+			if _, found := tc.populated[path]; !found {
+				unpopulated[t] = append(unpopulated[t], path)
+			}
+		}
+	}
+	return unpopulated
+}
+
+// record adds the fields of the struct element type mapped to the columns, other element types are not recorded
+func (r *CoverageRecorder) record(holderElementType reflect.Type, columnTypes []columnType, copts compileOptions) error {
+	structType, _, err := unwrapPtrStructType(holderElementType)
+	if err != nil {
+		return nil
+	}
+	p, err := copts.explain(holderElementType, columnTypes)
+	if err != nil || p.Strategy != "struct" {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	tc, found := r.byType[structType]
+	if !found {
+		fields, err := coverableFields(copts.state, structType)
+		if err != nil {
+			return err
+		}
+		tc = &typeCoverage{fields: fields, populated: map[string]struct{}{}}
+		r.byType[structType] = tc
+	}
+	for path := range p.populated() {
+		tc.populated[path] = struct{}{}
+	}
+	return nil
+}
+
+// coverableFields returns the paths of the exported fields of structType that can be mapped to the columns
+func coverableFields(st *state, structType reflect.Type) ([]string, error) {
+	var fields []string
+	err := st.visitLeafFields(structType, nil, nil, func(owner reflect.Type, field reflect.StructField, fieldIndex []int, fieldPath []string) error {
+		if token.IsExported(field.Name) && !isTableField(field) && !isRowNumberField(field) {
+			fields = append(fields, strings.Join(fieldPath, "."))
+		}
+		return nil
+	})
+	return fields, err
+}
@@ -0,0 +1,75 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordCoverage(t *testing.T) {
+	type details struct {
+		Col2 *string
+		Note string
+	}
+	type valStruct struct {
+		Id      int
+		Col1    string
+		Details details
+		Stale   bool
+		private int
+	}
+	coverage := NewCoverageRecorder()
+	RecordCoverage(coverage)
+	defer RecordCoverage(nil)
+
+	for _, query := range []string{
+		"SELECT id, col1 FROM propagation",
+		"SELECT id, col2 FROM propagation",
+	} {
+		rows, release := queryPropagation(t, "INSERT INTO propagation(id, col1) VALUES (1, 'a')", query)
+		var valStructs []*valStruct
+		err := Propagate(&valStructs, rows)
+		release()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	valStructType := reflect.TypeOf(valStruct{})
+	if populated, exp := coverage.Populated(valStructType), []string{"Col1", "Details.Col2", "Id"}; !reflect.DeepEqual(populated, exp) {
+		t.Errorf("unexpected populated fields: expected %v, actual %v", exp, populated)
+	}
+	exp := map[reflect.Type][]string{valStructType: {"Details.Note", "Stale"}}
+	if unpopulated := coverage.Unpopulated(); !reflect.DeepEqual(unpopulated, exp) {
+		t.Errorf("unexpected unpopulated fields: expected %v, actual %v", exp, unpopulated)
+	}
+}
+
+func TestRecordCoverageOfLazy(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+		Col2 *string
+	}
+	coverage := NewCoverageRecorder()
+	mapper := NewMapper()
+	mapper.RecordCoverage(coverage)
+
+	rows, release := queryPropagation(t, "INSERT INTO propagation(id, col1) VALUES (1, 'a')", "SELECT id, col1 FROM propagation")
+	defer release()
+	lazy, err := NewLazyOn[valStruct](mapper, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lazy.All(); err != nil {
+		t.Fatal(err)
+	}
+
+	valStructType := reflect.TypeOf(valStruct{})
+	if populated, exp := coverage.Populated(valStructType), []string{"Col1", "Id"}; !reflect.DeepEqual(populated, exp) {
+		t.Errorf("unexpected populated fields: expected %v, actual %v", exp, populated)
+	}
+	exp := map[reflect.Type][]string{valStructType: {"Col2"}}
+	if unpopulated := coverage.Unpopulated(); !reflect.DeepEqual(unpopulated, exp) {
+		t.Errorf("unexpected unpopulated fields: expected %v, actual %v", exp, unpopulated)
+	}
+}
@@ -0,0 +1,50 @@
+package rowconv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// RegisterDatabaseTypeConverter registers conversion of the values of the columns of the database type,
+// as reported by sql.ColumnType.DatabaseTypeName (e.g. "JSONB", "GEOMETRY" or "DECIMAL"), regardless of the field
+// they are mapped to. The result of convert is stored into the field the same way as the value returned by database driver.
+// NULL values are not passed to convert. Names are case-insensitive. Converters should be registered before
+// the first propagation from the columns of the type, as compiled mappers are cached. Registration of nil removes it.
+func RegisterDatabaseTypeConverter(databaseTypeName string, convert func(src interface{}) (interface{}, error)) {
+	Default().RegisterDatabaseTypeConverter(databaseTypeName, convert)
+}
+
+// RegisterDatabaseTypeConverter is the same as RegisterDatabaseTypeConverter of the package for the mapper
+func (m *Mapper) RegisterDatabaseTypeConverter(databaseTypeName string, convert func(src interface{}) (interface{}, error)) {
+	name := strings.ToUpper(databaseTypeName)
+	m.state.databaseTypeConverters.Lock()
+	if convert == nil {
+		delete(m.state.databaseTypeConverters.byName, name)
+	} else {
+		m.state.databaseTypeConverters.byName[name] = convert
+	}
+	m.state.databaseTypeConverters.Unlock()
+}
+
+func (st *state) databaseTypeConverterOf(databaseTypeName string) (func(src interface{}) (interface{}, error), bool) {
+	st.databaseTypeConverters.RLock()
+	convert, found := st.databaseTypeConverters.byName[strings.ToUpper(databaseTypeName)]
+	st.databaseTypeConverters.RUnlock()
+	return convert, found
+}
+
+// convertBefore creates converter that transforms value returned by database driver before it is stored with convert,
+// NULL values are not transformed
+func convertBefore(transform func(src interface{}) (interface{}, error), convert converter) converter {
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil {
+			return convert(nil, dst)
+		}
+
+		value, err := transform(src)
+		if err != nil {
+			return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+		}
+		return convert(value, dst)
+	}
+}
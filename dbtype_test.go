@@ -0,0 +1,70 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// varcharTypeNames returns the names of the database types of col1 and col2 of the propagation table as reported
+// by the driver, SQLite reports the declared types along with their lengths
+func varcharTypeNames() []string {
+	if driverName() == "sqlite3" {
+		return []string{"VARCHAR(20)", "VARCHAR(10)"}
+	}
+	return []string{"VARCHAR"}
+}
+
+func TestRegisterDatabaseTypeConverter(t *testing.T) {
+	for _, name := range varcharTypeNames() {
+		RegisterDatabaseTypeConverter(strings.ToLower(name), func(src interface{}) (interface{}, error) {
+			return strings.ToUpper(asString(src)), nil
+		})
+		defer RegisterDatabaseTypeConverter(name, nil)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 []byte
+		Col2 *string
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	b := "B"
+	exp := []valStruct{{Id: 1, Col1: []byte("A"), Col2: &b}, {Id: 2, Col1: []byte("C")}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestRegisterDatabaseTypeConverterError(t *testing.T) {
+	cause := errors.New("unsupported encoding")
+	for _, name := range varcharTypeNames() {
+		RegisterDatabaseTypeConverter(name, func(src interface{}) (interface{}, error) { return nil, cause })
+		defer RegisterDatabaseTypeConverter(name, nil)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT col1 FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 string
+	}
+	var valStructs []valStruct
+	var parseErr *ParseError
+	if err := Propagate(&valStructs, rows); !errors.As(err, &parseErr) || parseErr.Column != "col1" || !errors.Is(err, cause) {
+		t.Errorf("ParseError of the converter expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package rowconv
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// ScanDefinitionsLimit configures the maximum amount of scan definitions cached for the same type and options.
+// A definition is compiled for each distinct set of columns the type is propagated from, so services building
+// SELECT lists dynamically may accumulate many of them. Once the limit is exceeded the least recently used
+// definition is evicted and compiled again on the next use. 0, the default, means no limit.
+func ScanDefinitionsLimit(limit int) {
+	Default().ScanDefinitionsLimit(limit)
+}
+
+// ScanDefinitionsLimit is the same as ScanDefinitionsLimit of the package for the mapper
+func (m *Mapper) ScanDefinitionsLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	m.state.definitionsLimit.Store(limit)
+}
+
+func (st *state) scanDefinitionsLimit() int {
+	return st.definitionsLimit.Load().(int)
+}
+
+// columnsSignature identifies the set of columns the scan definition is compiled for.
+// It consists of what columns known ahead of time have, so the definitions of Warm match the columns of the rows.
+func columnsSignature(columnTypes []columnType) string {
+	var signature strings.Builder
+	for _, ct := range columnTypes {
+		signature.WriteString(ct.Name())
+		signature.WriteByte(0)
+		signature.WriteString(ct.DatabaseTypeName())
+		signature.WriteByte(0)
+		if scanType := ct.ScanType(); scanType != nil {
+			signature.WriteString(scanType.String())
+		}
+		signature.WriteByte(1)
+	}
+	return signature.String()
+}
+
+// definitionsLRU holds scan definitions compiled for the same key by the signature of the columns,
+// ordered from the most to the least recently used one
+type definitionsLRU struct {
+	bySignature map[string]*list.Element
+	order       *list.List
+	// Mutex guards the order, as uses are recorded under the read lock of the manager
+	sync.Mutex
+}
+
+// definitionEntry is the cached scan definition with the signature of its columns
+type definitionEntry struct {
+	signature string
+	scanDef   scanDefinition
+}
+
+func newDefinitionsLRU() *definitionsLRU {
+	return &definitionsLRU{bySignature: map[string]*list.Element{}, order: list.New()}
+}
+
+// get returns the entry of the signature without recording its use, see touch
+func (lru *definitionsLRU) get(signature string) (*definitionEntry, bool) {
+	if lru == nil {
+		return nil, false
+	}
+	element, found := lru.bySignature[signature]
+	if !found {
+		return nil, false
+	}
+	return element.Value.(*definitionEntry), true
+}
+
+// touch records the use of the entry of the signature, it may be called under the read lock of the manager
+func (lru *definitionsLRU) touch(signature string) {
+	lru.Lock()
+	if element, found := lru.bySignature[signature]; found {
+		lru.order.MoveToFront(element)
+	}
+	lru.Unlock()
+}
+
+// add puts the entry as the most recently used one, replacing the entry of the same signature, and evicts
+// the least recently used entries until there are no more than limit of them, 0 means no limit.
+// It must be called under the write lock of the manager.
+func (lru *definitionsLRU) add(entry *definitionEntry, limit int) {
+	if element, found := lru.bySignature[entry.signature]; found {
+		lru.order.Remove(element)
+	}
+	lru.bySignature[entry.signature] = lru.order.PushFront(entry)
+
+	for limit > 0 && lru.order.Len() > limit {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.bySignature, oldest.Value.(*definitionEntry).signature)
+	}
+}
+
+func (lru *definitionsLRU) len() int {
+	if lru == nil {
+		return 0
+	}
+	return len(lru.bySignature)
+}
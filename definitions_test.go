@@ -0,0 +1,68 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanDefinitionsLimit(t *testing.T) {
+	ScanDefinitionsLimit(2)
+	defer ScanDefinitionsLimit(0)
+
+	var compiled int
+	sdm := &scanDefinitionsManager{
+		byKey: map[definitionKey]*definitionsLRU{},
+		compile: func(reflect.Type, []columnType, compileOptions) (scanDefinition, error) {
+			compiled++
+			return scanDefinition{}, nil
+		},
+	}
+	columns := func(names ...string) []columnType {
+		columnTypes := make([]columnType, len(names))
+		for i, name := range names {
+			columnTypes[i] = Column{Name: name, DatabaseTypeName: "VARCHAR"}.known()
+		}
+		return columnTypes
+	}
+	elementType := reflect.TypeOf("")
+	copts := compileOptions{state: Default().state}
+	get := func(names ...string) {
+		t.Helper()
+		if _, err := sdm.getOrCreateSync(elementType, columns(names...), copts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get("id")
+	get("id", "col1")
+	get("id") // makes "id", "col1" the least recently used
+	get("id", "col2")
+	if compiled != 3 {
+		t.Errorf("3 compilations expected, actual: %d", compiled)
+	}
+	if n := sdm.byKey[definitionKey{elementType: elementType, options: copts}].len(); n != 2 {
+		t.Errorf("2 definitions expected to be cached, actual: %d", n)
+	}
+
+	get("id")
+	get("id", "col2")
+	if compiled != 3 {
+		t.Errorf("recently used definitions expected to be cached, compilations: %d", compiled)
+	}
+	get("id", "col1")
+	if compiled != 4 {
+		t.Errorf("evicted definition expected to be compiled again, compilations: %d", compiled)
+	}
+}
+
+func TestColumnsSignature(t *testing.T) {
+	a := []columnType{Column{Name: "ab"}.known(), Column{Name: "c"}.known()}
+	b := []columnType{Column{Name: "a"}.known(), Column{Name: "bc"}.known()}
+	if columnsSignature(a) == columnsSignature(b) {
+		t.Error("different columns expected to have different signatures")
+	}
+	c := []columnType{Column{Name: "ab", ScanType: reflect.TypeOf(int64(0))}.known(), Column{Name: "c"}.known()}
+	if columnsSignature(a) == columnsSignature(c) {
+		t.Error("columns of different scan types expected to have different signatures")
+	}
+}
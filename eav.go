@@ -0,0 +1,138 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PropagateEAV converts rows of entity-attribute-value shape, i.e. of (entity_id, attr_name, attr_value) columns,
+// into dst with a single element per entity. dst has the same requirements as for Propagate,
+// its elements must be structs or references to structs. The entity id is stored into the field tagged with `key` option
+// and each value into the field which column/alias matches the attribute name, e.g. `db_column:"email"`.
+// Elements are put into dst once all rows are consumed, in order of the first appearance of the entities.
+// Attributes without a field are skipped unless StrictColumnAmountCheck is enabled.
+func PropagateEAV(dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return Default().PropagateEAV(dst, rows, opts...)
+}
+
+// PropagateEAV is the same as PropagateEAV of the package with the options of the mapper
+func (m *Mapper) PropagateEAV(dst interface{}, rows *sql.Rows, opts ...Option) (err error) {
+	o := m.newOptions(opts)
+	defer o.compile.state.recoverPropagation(reflect.TypeOf(dst), rows, &err)
+
+	sink, elementType, err := o.compile.state.newSink(dst)
+	if err != nil {
+		return err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 3 {
+		return fmt.Errorf("columns of entity, attribute name and attribute value are expected, received: %v", columns)
+	}
+
+	entities, err := pivotEntities(elementType, columns[0], rows, o.compile)
+	if err != nil {
+		return err
+	}
+
+	if sink, err = o.wrapSink(sink, elementType); err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		if err := sink.Add(entity); err != nil {
+			return err
+		}
+	}
+	return sink.Flush()
+}
+
+// pivotEntities collects values of the attributes of each entity into a single element
+func pivotEntities(elementType reflect.Type, entityColumn string, rows *sql.Rows, copts compileOptions) ([]reflect.Value, error) {
+	structType, _, err := unwrapPtrStructType(elementType)
+	if err != nil {
+		return nil, err
+	}
+	columnAliasToAccessor, err := copts.state.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := copts.state.keyAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != 1 {
+		return nil, errors.New("single field with `key` option is expected for the entity id of " + elementType.String())
+	}
+	provider, err := copts.structProvider(elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	converters := map[string]converter{}
+	fieldConverter := func(accessor fieldAccessor) converter {
+		convert, found := converters[accessor.columnAlias]
+		if !found {
+			if convert = copts.converter(accessor.fieldType, accessor.options); convert == nil {
+				convert = convertReference(convertDefault)
+			}
+			converters[accessor.columnAlias] = convert
+		}
+		return convert
+	}
+
+	var entities []reflect.Value
+	entityIndexes := map[interface{}]int{}
+	for rows.Next() {
+		var entityID, value interface{}
+		var attribute string
+		if err := rows.Scan(&entityID, &attribute, &value); err != nil {
+			return nil, err
+		}
+
+		entityKey := entityID
+		if id, ok := entityID.([]byte); ok {
+			entityKey = string(id)
+		}
+		index, found := entityIndexes[entityKey]
+		if !found {
+			entity, err := provider()
+			if err != nil {
+				return nil, err
+			}
+			underlyingValue, _, err := unwrapPtrStructValue(entity)
+			if err != nil {
+				return nil, err
+			}
+			key := &fieldScanner{column: entityColumn, field: underlyingValue.FieldByIndex(keys[0].fieldIndex), convert: fieldConverter(keys[0])}
+			if err := key.Scan(entityID); err != nil {
+				return nil, err
+			}
+			index = len(entities)
+			entityIndexes[entityKey] = index
+			entities = append(entities, entity)
+		}
+
+		accessor, found := columnAliasToAccessor[strings.ToLower(attribute)]
+		if !found || accessor.nested {
+			if copts.columnAmountCheck {
+				return nil, errors.New("no mapping exists for attribute: " + attribute)
+			}
+			continue
+		}
+		underlyingValue, _, err := unwrapPtrStructValue(entities[index])
+		if err != nil {
+			return nil, err
+		}
+		field := &fieldScanner{column: attribute, field: underlyingValue.FieldByIndex(accessor.fieldIndex), convert: fieldConverter(accessor)}
+		if err := field.Scan(value); err != nil {
+			return nil, err
+		}
+	}
+	return entities, rows.Err()
+}
@@ -0,0 +1,46 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateEAV(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'name', 'u1'), (2, 'age', 'u1'), (3, 'name', 'u2'), (4, 'unknown', 'u2')",
+		"SELECT col2, col1, id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type user struct {
+		User string `db_column:"user,key"`
+		Name int
+		Age  *int
+	}
+	var users []user
+	if err := PropagateEAV(&users, rows); err != nil {
+		t.Fatal(err)
+	}
+	age := 2
+	exp := []user{{User: "u1", Name: 1, Age: &age}, {User: "u2", Name: 3}}
+	if !reflect.DeepEqual(users, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, users)
+	}
+}
+
+func TestPropagateEAVWithoutKey(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'name', 'u1')",
+		"SELECT col2, col1, id FROM propagation",
+	)
+	defer release()
+
+	type user struct {
+		User string
+		Name int
+	}
+	var users []user
+	if err := PropagateEAV(&users, rows); err == nil {
+		t.Error("error expected for struct without key field")
+	}
+}
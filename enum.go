@@ -0,0 +1,163 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterEnum registers members of MySQL ENUM or SET column, in order of the column definition,
+// for fields of type t of string or integer kind, e.g. `type Status string` with typed constants.
+// Values of ENUM columns are stored into fields of string types as is and into fields of integer types
+// as the position of the member. Values of SET columns are stored into fields with `set` tag option:
+// fields of slice types receive members and fields of integer types receive bitmask with a bit per member position.
+// Values that are not registered members are reported by *UnknownMemberError.
+// Members should be registered before the first propagation into the struct with such fields, as compiled mappers
+// are cached. Registration without members removes it.
+func RegisterEnum(t reflect.Type, members ...string) {
+	Default().RegisterEnum(t, members...)
+}
+
+// RegisterEnum is the same as RegisterEnum of the package for the mapper
+func (m *Mapper) RegisterEnum(t reflect.Type, members ...string) {
+	m.state.enumMembers.Lock()
+	if len(members) == 0 {
+		delete(m.state.enumMembers.byType, t)
+	} else {
+		m.state.enumMembers.byType[t] = append([]string(nil), members...)
+	}
+	m.state.enumMembers.Unlock()
+}
+
+func (st *state) enumOf(t reflect.Type) ([]string, bool) {
+	st.enumMembers.RLock()
+	members, found := st.enumMembers.byType[t]
+	st.enumMembers.RUnlock()
+	return members, found
+}
+
+// UnknownMemberError is returned when the value of ENUM or SET column is not a registered member of the type
+type UnknownMemberError struct {
+	Member string
+	Type   reflect.Type
+}
+
+func (ume *UnknownMemberError) Error() string {
+	return fmt.Sprintf("%q is not a member of %v", ume.Member, ume.Type)
+}
+
+// enumConverter returns converter for the fields of registered types and for the fields with `set` tag option,
+// nil is returned for other fields
+func (st *state) enumConverter(valueType reflect.Type, fieldOptions []string) converter {
+	if hasOption(fieldOptions, "set") {
+		if valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.String {
+			members, registered := st.enumOf(valueType.Elem())
+			return func(src interface{}, dst reflect.Value) error {
+				return convertSetMembers(src, dst, members, registered)
+			}
+		}
+		if members, registered := st.enumOf(valueType); registered && isIntegerKind(valueType.Kind()) {
+			return func(src interface{}, dst reflect.Value) error {
+				return convertSetBitmask(src, dst, members)
+			}
+		}
+		return nil
+	}
+
+	members, registered := st.enumOf(valueType)
+	if !registered || valueType.Kind() != reflect.String && !isIntegerKind(valueType.Kind()) {
+		return nil
+	}
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil {
+			return convertDefault(src, dst)
+		}
+		member := asString(src)
+		position := memberPosition(members, member)
+		if position < 0 {
+			return &ParseError{Value: member, Type: dst.Type(), Err: &UnknownMemberError{Member: member, Type: dst.Type()}}
+		}
+		if dst.Kind() == reflect.String {
+			dst.SetString(member)
+			return nil
+		}
+		return parseErrorOf(setInteger(dst, uint64(position)), src, dst)
+	}
+}
+
+func convertSetMembers(src interface{}, dst reflect.Value, members []string, registered bool) error {
+	if src == nil {
+		return convertDefault(src, dst)
+	}
+
+	elementType := dst.Type().Elem()
+	value := asString(src)
+	setMembers := reflect.MakeSlice(dst.Type(), 0, strings.Count(value, ",")+1)
+	for _, member := range splitSet(value) {
+		if registered && memberPosition(members, member) < 0 {
+			return &ParseError{Value: value, Type: dst.Type(), Err: &UnknownMemberError{Member: member, Type: elementType}}
+		}
+		setMembers = reflect.Append(setMembers, reflect.ValueOf(member).Convert(elementType))
+	}
+	dst.Set(setMembers)
+	return nil
+}
+
+func convertSetBitmask(src interface{}, dst reflect.Value, members []string) error {
+	if src == nil {
+		return convertDefault(src, dst)
+	}
+
+	value := asString(src)
+	var bitmask uint64
+	for _, member := range splitSet(value) {
+		position := memberPosition(members, member)
+		if position < 0 {
+			return &ParseError{Value: value, Type: dst.Type(), Err: &UnknownMemberError{Member: member, Type: dst.Type()}}
+		}
+		bitmask |= 1 << uint(position)
+	}
+	return parseErrorOf(setInteger(dst, bitmask), src, dst)
+}
+
+// splitSet splits value of SET column into members, the empty value has no members
+func splitSet(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func memberPosition(members []string, member string) int {
+	for i, m := range members {
+		if m == member {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func setInteger(dst reflect.Value, v uint64) error {
+	if dst.Kind() >= reflect.Uint && dst.Kind() <= reflect.Uint64 {
+		if dst.OverflowUint(v) {
+			return ErrOverflow
+		}
+		dst.SetUint(v)
+		return nil
+	}
+	if v > 1<<63-1 || dst.OverflowInt(int64(v)) {
+		return ErrOverflow
+	}
+	dst.SetInt(int64(v))
+	return nil
+}
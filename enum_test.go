@@ -0,0 +1,82 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type permission string
+
+type status int
+
+const (
+	statusActive status = iota
+	statusBlocked
+)
+
+func TestEnumConverters(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(permission("")), "read", "write", "admin")
+	defer RegisterEnum(reflect.TypeOf(permission("")))
+	RegisterEnum(reflect.TypeOf(status(0)), "active", "blocked")
+	defer RegisterEnum(reflect.TypeOf(status(0)))
+
+	copts := compileOptions{state: Default().state}
+	var st status
+	if err := copts.converter(reflect.TypeOf(st), nil)([]byte("blocked"), reflect.ValueOf(&st).Elem()); err != nil || st != statusBlocked {
+		t.Errorf("unexpected enum value: %v, error: %v", st, err)
+	}
+
+	var perms []permission
+	if err := copts.converter(reflect.TypeOf(perms), []string{"set"})("read,admin", reflect.ValueOf(&perms).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []permission{"read", "admin"}; !reflect.DeepEqual(perms, exp) {
+		t.Errorf("unexpected set members: expected %v, actual %v", exp, perms)
+	}
+
+	var names []string
+	if err := copts.converter(reflect.TypeOf(names), []string{"set"})("", reflect.ValueOf(&names).Elem()); err != nil || len(names) != 0 {
+		t.Errorf("no members expected for empty set, actual: %v, error: %v", names, err)
+	}
+
+	type permissions uint8
+	RegisterEnum(reflect.TypeOf(permissions(0)), "read", "write", "admin")
+	defer RegisterEnum(reflect.TypeOf(permissions(0)))
+	var mask permissions
+	if err := copts.converter(reflect.TypeOf(mask), []string{"set"})("write,admin", reflect.ValueOf(&mask).Elem()); err != nil || mask != 6 {
+		t.Errorf("unexpected bitmask: %b, error: %v", mask, err)
+	}
+
+	var perm permission
+	err := copts.converter(reflect.TypeOf(perm), nil)("delete", reflect.ValueOf(&perm).Elem())
+	var unknownMemberErr *UnknownMemberError
+	if !errors.As(err, &unknownMemberErr) || unknownMemberErr.Member != "delete" {
+		t.Errorf("unknown member error expected, actual: %v", err)
+	}
+}
+
+func TestPropagateSet(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'read,write', 'blocked')",
+		"SELECT col1, col2 FROM propagation",
+	)
+	defer release()
+
+	RegisterEnum(reflect.TypeOf(status(0)), "active", "blocked")
+	defer RegisterEnum(reflect.TypeOf(status(0)))
+
+	type valStruct struct {
+		Col1 []string `db_column:"col1,set"`
+		Col2 *status
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	blocked := statusBlocked
+	exp := []valStruct{{Col1: []string{"read", "write"}, Col2: &blocked}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
@@ -0,0 +1,226 @@
+package rowconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExplainJSON returns the plan Propagate compiles for the element type t, e.g. `reflect.TypeOf(User{})`, and the columns
+// as indented JSON, so tooling can diff the mapping across releases. The plan, compacted below, holds the type,
+// the strategy of the mapping ("struct", "value", "constructor", "tuple" or "row scanner") and for the first two
+// the actions of the columns:
+//
+//	{
+//	  "type": "example.com/app.User",
+//	  "strategy": "struct",
+//	  "columns": [
+//	    {"column": "id", "databaseType": "INT", "action": "field", "fields": ["ID"], "fieldType": "int64", "converter": "database/sql"},
+//	    {"column": "audit", "action": "skip"}
+//	  ]
+//	}
+//
+// The action is one of "field", "value", "json", "split", "setter", "combine", "blob" or "skip".
+// The converter is "database/sql" if the value is stored by database/sql as is, "mapping" for the converter of Mapping,
+// "scanner:<name>" for the scanner of `db_scanner` tag, "database type:<name>" for the converter registered
+// with RegisterDatabaseTypeConverter, "chain:<names>" for the converters of RegisterConverter in the order they
+// are tried and "rowconv" for the conversions of the package. opts are applied the same way as by Propagate
+// and errors of the compilation, e.g. of StrictColumnAmountCheck, are returned.
+func ExplainJSON(t reflect.Type, columns []Column, opts ...Option) ([]byte, error) {
+	return Default().ExplainJSON(t, columns, opts...)
+}
+
+// ExplainJSON is the same as ExplainJSON of the package for the mapper
+func (m *Mapper) ExplainJSON(t reflect.Type, columns []Column, opts ...Option) ([]byte, error) {
+	holderElementType, err := elementType(t)
+	if err != nil {
+		return nil, err
+	}
+	if !m.state.isSingleBasicType(holderElementType) && !isRowScannerType(derefType(holderElementType)) {
+		if _, _, err := unwrapPtrStructType(holderElementType); err != nil {
+			return nil, fmt.Errorf("can't explain %v: %w", t, err)
+		}
+	}
+	columnTypes := make([]columnType, len(columns))
+	for i, column := range columns {
+		columnTypes[i] = column.known()
+	}
+
+	copts := m.newOptions(opts).compile
+	if _, err := createRowScanner(holderElementType, columnTypes, copts); err != nil {
+		return nil, err
+	}
+	p, err := copts.explain(holderElementType, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// explainedPlan is the plan of the mapping of the columns to the element type, see ExplainJSON
+type explainedPlan struct {
+	Type     string            `json:"type"`
+	Strategy string            `json:"strategy"`
+	Columns  []explainedColumn `json:"columns,omitempty"`
+}
+
+// explainedColumn is the action made with the value of the column
+type explainedColumn struct {
+	Column       string   `json:"column"`
+	DatabaseType string   `json:"databaseType,omitempty"`
+	Action       string   `json:"action"`
+	Fields       []string `json:"fields,omitempty"`
+	FieldType    string   `json:"fieldType,omitempty"`
+	Converter    string   `json:"converter,omitempty"`
+	Options      []string `json:"options,omitempty"`
+}
+
+// explain describes the decisions of createRowScanner, it must be kept in line with it and createHolderSuppliers
+func (copts compileOptions) explain(holderElementType reflect.Type, columnTypes []columnType) (explainedPlan, error) {
+	p := explainedPlan{Type: holderElementType.String()}
+	if derefType(holderElementType).PkgPath() != "" {
+		p.Type = typeSignature(holderElementType)
+	}
+	actualType := derefType(holderElementType)
+	if _, found := copts.state.constructorOf(actualType); found {
+		p.Strategy = "constructor"
+		return p, nil
+	}
+	switch {
+	case isRowScannerType(actualType):
+		p.Strategy = "row scanner"
+		return p, nil
+	case isTupleType(actualType):
+		p.Strategy = "tuple"
+		return p, nil
+	case copts.state.isSingleBasicType(holderElementType):
+		p.Strategy = "value"
+		if len(columnTypes) > 0 {
+			column := copts.explainColumn(columnTypes[0], "value")
+			column.FieldType = holderElementType.String()
+			column.Converter = copts.converterName(columnTypes[0], holderElementType, nil, nil)
+			p.Columns = append(p.Columns, column)
+		}
+		return p, nil
+	}
+
+	p.Strategy = "struct"
+	accessors, err := copts.columnAccessors(holderElementType, columnTypes)
+	if err != nil {
+		return p, err
+	}
+	fieldCombiners, err := copts.state.createFieldCombiners(holderElementType, columnTypes)
+	if err != nil {
+		return p, err
+	}
+	combinedInto := map[int]string{}
+	for _, fc := range fieldCombiners {
+		for _, position := range fc.positions {
+			combinedInto[position] = strings.Join(fc.fieldPath, ".")
+		}
+	}
+	columnToSplit, err := copts.fieldSplits(holderElementType)
+	if err != nil {
+		return p, err
+	}
+
+	for i, columnType := range columnTypes {
+		var column explainedColumn
+		if fieldPath, found := combinedInto[i]; found {
+			column = copts.explainColumn(columnType, "combine")
+			column.Fields = []string{fieldPath}
+			p.Columns = append(p.Columns, column)
+			continue
+		}
+		if fs, found := columnToSplit[strings.ToLower(columnType.Name())]; found {
+			column = copts.explainColumn(columnType, "split")
+			column.Fields = accessorPaths(fs.accessors)
+			column.Converter = "splitter:" + fs.name
+			p.Columns = append(p.Columns, column)
+			continue
+		}
+		if !isSettableAccessor(holderElementType, accessors[i]) {
+			if setter, found := setterOf(holderElementType, columnType.Name()); found {
+				column = copts.explainColumn(columnType, "setter")
+				column.Fields = []string{setter.Name}
+				column.FieldType = setter.Type.In(1).String()
+				column.Converter = copts.converterName(columnType, setter.Type.In(1), nil, nil)
+				p.Columns = append(p.Columns, column)
+				continue
+			}
+		}
+
+		switch {
+		case len(accessors[i]) == 0:
+			column = copts.explainColumn(columnType, "skip")
+		case len(accessors[i]) == 1 && accessors[i][0].fieldType == blobSinkType:
+			column = copts.explainColumn(columnType, "blob")
+			column.Fields = accessorPaths(accessors[i])
+		case len(accessors[i]) == 1 && !strings.Contains(accessors[i][0].columnAlias, jsonPathSeparator):
+			accessor := accessors[i][0]
+			column = copts.explainColumn(columnType, "field")
+			column.Fields = accessorPaths(accessors[i])
+			column.FieldType = accessor.fieldType.String()
+			column.Converter = copts.converterName(columnType, accessor.fieldType, accessor.options, accessor.convert)
+			column.Options = accessor.options
+		default:
+			column = copts.explainColumn(columnType, "json")
+			column.Fields = accessorPaths(accessors[i])
+		}
+		p.Columns = append(p.Columns, column)
+	}
+	return p, nil
+}
+
+// populated returns the paths of the fields populated from the columns of the struct plan
+func (p explainedPlan) populated() map[string]struct{} {
+	populated := map[string]struct{}{}
+	for _, column := range p.Columns {
+		if column.Action == "skip" || column.Action == "setter" {
+			continue
+		}
+		for _, path := range column.Fields {
+			populated[path] = struct{}{}
+		}
+	}
+	return populated
+}
+
+func (copts compileOptions) explainColumn(columnType columnType, action string) explainedColumn {
+	return explainedColumn{Column: columnType.Name(), DatabaseType: columnType.DatabaseTypeName(), Action: action}
+}
+
+// converterName names the converter the value of the column is stored into the field of forType with
+func (copts compileOptions) converterName(columnType columnType, forType reflect.Type, fieldOptions []string, mappingConvert converter) string {
+	switch scanner, found := scannerOption(fieldOptions); {
+	case mappingConvert != nil:
+		return "mapping"
+	case found:
+		return "scanner:" + scanner
+	}
+	if copts.columnConverter(columnType, forType, fieldOptions) == nil {
+		return "database/sql"
+	}
+	if name := databaseTypeName(columnType); name != "" {
+		if _, found := copts.state.databaseTypeConverterOf(name); found {
+			return "database type:" + name
+		}
+	}
+	if chain := copts.state.converterChain(databaseTypeName(columnType), forType); len(chain) > 0 {
+		names := make([]string, len(chain))
+		for i, c := range chain {
+			names[i] = c.Name
+		}
+		return "chain:" + strings.Join(names, ",")
+	}
+	return "rowconv"
+}
+
+func accessorPaths(accessors []fieldAccessor) []string {
+	paths := make([]string, len(accessors))
+	for i, accessor := range accessors {
+		paths[i] = strings.Join(accessor.fieldPath, ".")
+	}
+	return paths
+}
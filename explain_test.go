@@ -0,0 +1,71 @@
+package rowconv
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplainJSON(t *testing.T) {
+	type valStruct struct {
+		Id    int64
+		Title string  `db_column:"name,trim"`
+		Total float64 `db_scanner:"cents"`
+	}
+	RegisterScanner("cents", scanCents)
+	t.Cleanup(func() { RegisterScanner("cents", nil) })
+
+	columns := []Column{
+		{Name: "id", DatabaseTypeName: "INTEGER"},
+		{Name: "name", DatabaseTypeName: "VARCHAR"},
+		{Name: "total"},
+		{Name: "audit"},
+	}
+	data, err := ExplainJSON(reflect.TypeOf(&valStruct{}), columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act explainedPlan
+	if err := json.Unmarshal(data, &act); err != nil {
+		t.Fatal(err)
+	}
+	exp := explainedPlan{
+		Type:     "*github.com/pavelmemory/rowconv.valStruct",
+		Strategy: "struct",
+		Columns: []explainedColumn{
+			{Column: "id", DatabaseType: "INTEGER", Action: "field", Fields: []string{"Id"}, FieldType: "int64", Converter: "database/sql"},
+			{Column: "name", DatabaseType: "VARCHAR", Action: "field", Fields: []string{"Title"}, FieldType: "string", Converter: "rowconv", Options: []string{"trim"}},
+			{Column: "total", Action: "field", Fields: []string{"Total"}, FieldType: "float64", Converter: "scanner:cents", Options: []string{"scanner=cents"}},
+			{Column: "audit", Action: "skip"},
+		},
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected plan: expected %+v, actual %+v", exp, act)
+	}
+
+	if _, err := ExplainJSON(reflect.TypeOf(valStruct{}), columns, WithStrictColumnAmountCheck(true)); err == nil || !strings.Contains(err.Error(), "audit") {
+		t.Errorf("error of the strict column amount check expected, actual: %v", err)
+	}
+}
+
+func TestExplainJSONValue(t *testing.T) {
+	if _, err := ExplainJSON(reflect.TypeOf([]struct{}{}), nil); err == nil {
+		t.Error("error expected for the slice instead of the element type")
+	}
+
+	data, err := ExplainJSON(reflect.TypeOf(""), []Column{{Name: "name", DatabaseTypeName: "VARCHAR"}}, WithTrimmedStrings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act explainedPlan
+	if err := json.Unmarshal(data, &act); err != nil {
+		t.Fatal(err)
+	}
+	exp := explainedPlan{Type: "string", Strategy: "value", Columns: []explainedColumn{
+		{Column: "name", DatabaseType: "VARCHAR", Action: "value", FieldType: "string", Converter: "rowconv"},
+	}}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected plan: expected %+v, actual %+v", exp, act)
+	}
+}
@@ -0,0 +1,147 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldErrorsMode defines if and how the failures of the fields are reported with FieldError
+type fieldErrorsMode uint8
+
+const (
+	fieldErrorsOff fieldErrorsMode = iota
+	fieldErrorsDetailed
+	fieldErrorsRedacted
+)
+
+// FieldError is returned by the propagations with WithFieldErrors when the value of the column can't be stored
+// into the field of the struct, so data-quality issues can be routed to the owners of the data automatically.
+// The cause, e.g. *ParseError, is available with errors.As.
+type FieldError struct {
+	// Row is the number of the failed row starting from 1
+	Row int
+	// Column is the name of the column/alias
+	Column string
+	// FieldPath is a sequence of names of the fields from the root struct to the field the column is mapped to
+	FieldPath []string
+	// Converter is the converter that failed, named the same way as by ExplainJSON
+	Converter string
+	// Value is the value returned by database driver in textual form, it is empty if Redacted is set
+	Value string
+	// Redacted is set if Value and the message of Err are omitted with WithRedactedFieldErrors
+	Redacted bool
+	// Err is the cause of the failure
+	Err error
+}
+
+func (fe *FieldError) Error() string {
+	value := fmt.Sprintf("%q", fe.Value)
+	if fe.Redacted {
+		value = "<redacted>"
+	}
+	return fmt.Sprintf("row %d: value %s of column/alias: %s can't be stored into field: %s with converter: %s: %v",
+		fe.Row, value, fe.Column, strings.Join(fe.FieldPath, "."), fe.Converter, fe.Err)
+}
+
+func (fe *FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// WithFieldErrors reports the failures of storing the values of the columns into the fields of the struct elements
+// with *FieldError that holds the row, the field path, the converter and the value.
+// The values are converted the same way as without the option, only the errors are completed with the details.
+func WithFieldErrors() Option {
+	return func(o *options) {
+		o.compile.fieldErrors = fieldErrorsDetailed
+	}
+}
+
+// WithRedactedFieldErrors is WithFieldErrors that omits the values of the columns, e.g. for the columns
+// with personal data the errors must not leak into logs. The message of the cause that may quote the value
+// is omitted too, while the cause itself is still available with errors.Is and errors.As.
+func WithRedactedFieldErrors() Option {
+	return func(o *options) {
+		o.compile.fieldErrors = fieldErrorsRedacted
+	}
+}
+
+// fieldDetails are the details of the field completing the errors of fieldScanner, see WithFieldErrors
+type fieldDetails struct {
+	fieldPath []string
+	converter string
+	redacted  bool
+}
+
+// fieldError wraps the error of storing src into the field with the details of the field
+func (fd *fieldDetails) fieldError(column string, src interface{}, err error) error {
+	fe := &FieldError{Column: column, FieldPath: fd.fieldPath, Converter: fd.converter, Redacted: fd.redacted, Err: err}
+	if fd.redacted {
+		fe.Err = &redactedError{cause: err}
+	} else {
+		fe.Value = asString(src)
+	}
+	return fe
+}
+
+// redactedError hides the message of the cause that may quote the value of the column
+type redactedError struct {
+	cause error
+}
+
+func (re *redactedError) Error() string {
+	return fmt.Sprintf("%T with redacted message", re.cause)
+}
+
+func (re *redactedError) Unwrap() error {
+	return re.cause
+}
+
+// holderDetailedByFieldIndexPath is holderConvertedByFieldIndexPath which errors are completed with the details
+func holderDetailedByFieldIndexPath(column string, holderIndexPath []int, convert converter, details *fieldDetails) holderSupplier {
+	return func(underlyingValue reflect.Value) interface{} {
+		return &fieldScanner{column: column, field: underlyingValue.FieldByIndex(holderIndexPath), convert: convert, details: details}
+	}
+}
+
+// sqlFieldError completes err of scanning the row with the details of the field database/sql failed to store
+// the value into: the columns of sqlFields are scanned one by one to find the failed one and its value.
+// err is returned as is if it is already FieldError or the failed field isn't found.
+func sqlFieldError(rows *sql.Rows, holders []interface{}, columnTypes []columnType, sqlFields []*fieldDetails, err error) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return err
+	}
+
+	dest := make([]interface{}, len(holders))
+	for i := range dest {
+		dest[i] = new(interface{})
+	}
+	for i, details := range sqlFields {
+		if details == nil {
+			continue
+		}
+
+		skip := dest[i]
+		dest[i] = holders[i]
+		columnErr := rows.Scan(dest...)
+		dest[i] = skip
+		if columnErr == nil {
+			continue
+		}
+
+		var src interface{}
+		dest[i] = &src
+		if rows.Scan(dest...) != nil {
+			return err
+		}
+		// FieldError holds the column, so the wrapping of database/sql is dropped
+		if cause := errors.Unwrap(columnErr); cause != nil {
+			columnErr = cause
+		}
+		return details.fieldError(columnTypes[i].Name(), src, columnErr)
+	}
+	return err
+}
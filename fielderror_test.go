@@ -0,0 +1,90 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPropagateWithFieldErrors(t *testing.T) {
+	type details struct {
+		Col1 int
+	}
+	type valStruct struct {
+		Id      int
+		Details details
+	}
+	propagate := func(opts ...Option) error {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, '10'), (2, 'secret')",
+			"SELECT id, col1 FROM propagation ORDER BY id",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		return Propagate(&valStructs, rows, opts...)
+	}
+
+	err := propagate(WithFieldErrors())
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("field error expected, actual: %v", err)
+	}
+	exp := FieldError{Row: 2, Column: "col1", FieldPath: []string{"Details", "Col1"}, Converter: "database/sql", Value: "secret", Err: fieldErr.Err}
+	if !reflect.DeepEqual(*fieldErr, exp) {
+		t.Errorf("unexpected field error: expected %+v, actual %+v", exp, *fieldErr)
+	}
+
+	err = propagate(WithRedactedFieldErrors())
+	if !errors.As(err, &fieldErr) || !fieldErr.Redacted || fieldErr.Value != "" || strings.Contains(err.Error(), "secret") {
+		t.Errorf("redacted field error expected, actual: %v", err)
+	}
+	if errors.Unwrap(fieldErr.Err) == nil {
+		t.Errorf("cause of redacted field error expected to be available, actual: %v", err)
+	}
+
+	if err := propagate(); err == nil || errors.As(err, &fieldErr) {
+		t.Errorf("error of database/sql expected without the option, actual: %v", err)
+	}
+}
+
+func TestPropagateWithFieldErrorsConversion(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 int64
+	}
+	propagate := func(values string, opts ...Option) ([]valStruct, error) {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES "+values,
+			"SELECT id, col1 FROM propagation ORDER BY id",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		err := Propagate(&valStructs, rows, opts...)
+		return valStructs, err
+	}
+
+	// the option doesn't change the conversion: numbers are parsed by database/sql strictly
+	exp, err := propagate("(1, '10'), (2, '-3')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := propagate("(1, '10'), (2, '-3')", WithFieldErrors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected results of propagation: expected %+v, actual %+v", exp, act)
+	}
+
+	if _, err := propagate("(1, '1e3')"); err == nil {
+		t.Error("error expected without the option")
+	}
+	_, err = propagate("(1, '1e3')", WithFieldErrors())
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Value != "1e3" || fieldErr.Converter != "database/sql" {
+		t.Errorf("field error expected, actual: %v", err)
+	}
+}
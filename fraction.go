@@ -0,0 +1,58 @@
+package rowconv
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FractionPolicy defines how numbers with fractional part, e.g. of NUMERIC or float columns,
+// are stored into fields of integer types
+type FractionPolicy uint8
+
+const (
+	// FractionError reports *ParseError for numbers with fractional part, it is the default policy
+	FractionError FractionPolicy = iota
+	// FractionTruncate drops fractional part: 2.7 -> 2, -2.7 -> -2
+	FractionTruncate
+	// FractionRound rounds to the nearest integer, half away from zero: 2.5 -> 3, -2.5 -> -3
+	FractionRound
+)
+
+// WithFractionPolicy configures how numbers with fractional part are stored into fields of integer types
+func WithFractionPolicy(policy FractionPolicy) Option {
+	return func(o *options) {
+		o.compile.fractionPolicy = policy
+	}
+}
+
+// converter returns converter that applies the policy to the number before storing it with convert,
+// nil is returned for FractionError
+func (fp FractionPolicy) converter(convert converter) converter {
+	if fp == FractionError {
+		return nil
+	}
+
+	return func(src interface{}, dst reflect.Value) error {
+		switch value := src.(type) {
+		case float64:
+			src = fp.apply(value)
+		case []byte, string:
+			text := strings.TrimSpace(asString(value))
+			if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+				if f, err := strconv.ParseFloat(text, 64); err == nil {
+					src = fp.apply(f)
+				}
+			}
+		}
+		return convert(src, dst)
+	}
+}
+
+func (fp FractionPolicy) apply(f float64) float64 {
+	if fp == FractionRound {
+		return math.Round(f)
+	}
+	return math.Trunc(f)
+}
@@ -0,0 +1,54 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFractionPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy FractionPolicy
+		src    interface{}
+		exp    int
+	}{
+		{policy: FractionTruncate, src: 2.7, exp: 2},
+		{policy: FractionTruncate, src: []byte("-2.7"), exp: -2},
+		{policy: FractionRound, src: 2.5, exp: 3},
+		{policy: FractionRound, src: "-2.5", exp: -3},
+		{policy: FractionRound, src: int64(7), exp: 7},
+	} {
+		copts := compileOptions{fractionPolicy: tc.policy, state: Default().state}
+		var act int
+		if err := copts.converter(reflect.TypeOf(act), nil)(tc.src, reflect.ValueOf(&act).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if act != tc.exp {
+			t.Errorf("unexpected result of policy %v for %v: expected %v, actual %v", tc.policy, tc.src, tc.exp, act)
+		}
+	}
+
+	copts := compileOptions{state: Default().state}
+	var i int
+	if err := convertDefault(2.5, reflect.ValueOf(&i).Elem()); err == nil {
+		t.Error("error expected for fraction by default")
+	}
+	if convert := copts.converter(reflect.TypeOf(i), nil); convert != nil {
+		t.Error("no converter expected by default")
+	}
+}
+
+func TestPropagateWithFractionPolicy(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, '2.5'), (2, '-2.5')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var values []int
+	if err := Propagate(&values, rows, WithFractionPolicy(FractionRound)); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int{3, -3}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, values)
+	}
+}
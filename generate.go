@@ -0,0 +1,145 @@
+package rowconv
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GenerateStruct reads columns of the table and writes into w the declaration of the struct named structName
+// with a field of the corresponding type and `db_column` tag for each of them, ready to be used with Propagate.
+// Fields of nullable columns, and of columns which nullability is not reported by the driver, are pointers.
+// Only the declaration is written, so the file it is put into must import packages it refers to, such as "time".
+func GenerateStruct(ctx context.Context, q Queryer, w io.Writer, structName, table string) error {
+	rows, err := q.QueryContext(ctx, "SELECT * FROM "+table+" WHERE 1 = 0")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	return generateStruct(w, structName, "is a row of the table "+table, columnTypes)
+}
+
+// GenerateStructFromQuery is GenerateStruct for the result of the SELECT statement, e.g. a report query
+// that doesn't correspond to a single table. The query is wrapped with LIMIT 0, so no rows are retrieved.
+func GenerateStructFromQuery(ctx context.Context, q Queryer, w io.Writer, structName, query string, args ...interface{}) error {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	rows, err := q.QueryContext(ctx, "SELECT * FROM ("+query+") AS generated LIMIT 0", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	return generateStruct(w, structName, "is a row of the result of the query", columnTypes)
+}
+
+func generateStruct(w io.Writer, structName, description string, columnTypes []*sql.ColumnType) error {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// %s %s\ntype %s struct {\n", structName, description, structName)
+	for i, columnType := range columnTypes {
+		fmt.Fprintf(&src, "\t%s %s `%s:%q`\n", generatedFieldName(columnType.Name(), i), generatedFieldType(columnType), dbColumn, strings.ToLower(columnType.Name()))
+	}
+	src.WriteString("}\n")
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// generatedFieldName converts column name into exported identifier: user_id -> UserId
+func generatedFieldName(column string, position int) string {
+	var name strings.Builder
+	for _, part := range strings.FieldsFunc(column, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(part)
+		name.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+	if name.Len() == 0 {
+		return fmt.Sprintf("Column%d", position+1)
+	}
+	if fieldName := name.String(); !unicode.IsLetter([]rune(fieldName)[0]) {
+		return "Column" + fieldName
+	}
+	return name.String()
+}
+
+var (
+	// nullTypes are wrappers of nullable values returned as scan types by drivers
+	nullTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+		reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+		reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+		reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+		reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(byte(0)),
+		reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+		reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+		reflect.TypeOf(sql.NullTime{}):    reflect.TypeOf(time.Time{}),
+	}
+
+	// databaseTypePrefixes resolve field types by names of database types when the driver doesn't report the scan type,
+	// they are checked in order, so INTERVAL goes before INT
+	databaseTypePrefixes = []struct {
+		prefix    string
+		fieldType string
+	}{
+		{"INTERVAL", "string"},
+		{"BIGINT", "int64"}, {"INT", "int64"}, {"SMALLINT", "int64"}, {"TINYINT", "int64"}, {"MEDIUMINT", "int64"},
+		{"BOOL", "bool"},
+		{"FLOAT", "float64"}, {"DOUBLE", "float64"}, {"REAL", "float64"},
+		{"DATE", "time.Time"}, {"TIME", "time.Time"},
+		{"BLOB", "[]byte"}, {"BYTEA", "[]byte"}, {"BINARY", "[]byte"}, {"VARBINARY", "[]byte"},
+	}
+)
+
+// generatedFieldType returns Go type suitable to store values of the column
+func generatedFieldType(columnType *sql.ColumnType) string {
+	nullable, known := columnType.Nullable()
+	nullable = nullable || !known
+
+	scanType := columnType.ScanType()
+	if valueType, found := nullTypes[scanType]; found {
+		scanType, nullable = valueType, true
+	}
+
+	var fieldType string
+	switch {
+	case isUniqueIdentifierColumn(columnType):
+		// converted into canonical UUID form on propagation
+		fieldType = "string"
+	case scanType == nil || scanType.Kind() == reflect.Interface || scanType == reflect.TypeOf(sql.RawBytes{}):
+		fieldType = "string"
+		databaseType := databaseTypeName(columnType)
+		for _, byPrefix := range databaseTypePrefixes {
+			if strings.HasPrefix(databaseType, byPrefix.prefix) {
+				fieldType = byPrefix.fieldType
+				break
+			}
+		}
+	case scanType.Kind() == reflect.Slice && scanType.Elem().Kind() == reflect.Uint8:
+		fieldType = "[]byte"
+	default:
+		fieldType = scanType.String()
+	}
+
+	if nullable && !strings.HasPrefix(fieldType, "[]") && !strings.HasPrefix(fieldType, "*") {
+		return "*" + fieldType
+	}
+	return fieldType
+}
@@ -0,0 +1,85 @@
+package rowconv
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	var generated strings.Builder
+	if err := GenerateStruct(ctx, tx, &generated, "Propagation", "propagation"); err != nil {
+		t.Fatal(err)
+	}
+	// fields are aligned by gofmt, so whitespaces are collapsed for comparison
+	normalized := strings.Join(strings.Fields(generated.String()), " ")
+	for _, exp := range []string{
+		"type Propagation struct {",
+		"`db_column:\"id\"`",
+		"`db_column:\"col1\"`",
+		"Col2 *string `db_column:\"col2\"`",
+		"`db_column:\"col3\"`",
+	} {
+		if !strings.Contains(normalized, exp) {
+			t.Errorf("generated struct expected to contain %s:\n%s", exp, generated.String())
+		}
+	}
+}
+
+func TestGeneratedFieldName(t *testing.T) {
+	for column, exp := range map[string]string{
+		"id":         "Id",
+		"user_id":    "UserId",
+		"Created At": "CreatedAt",
+		"2fa":        "Column2fa",
+		"???":        "Column3",
+	} {
+		if act := generatedFieldName(column, 2); act != exp {
+			t.Errorf("unexpected field name for column %q: expected %s, actual %s", column, exp, act)
+		}
+	}
+}
+
+func TestGenerateStructFromQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	var generated strings.Builder
+	if err := GenerateStructFromQuery(ctx, tx, &generated, "Report", "SELECT col1 AS name, COUNT(*) AS total FROM propagation GROUP BY col1;"); err != nil {
+		t.Fatal(err)
+	}
+	normalized := strings.Join(strings.Fields(generated.String()), " ")
+	for _, exp := range []string{
+		"type Report struct {",
+		"`db_column:\"name\"`",
+		"`db_column:\"total\"`",
+	} {
+		if !strings.Contains(normalized, exp) {
+			t.Errorf("generated struct expected to contain %s:\n%s", exp, generated.String())
+		}
+	}
+}
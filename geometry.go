@@ -0,0 +1,182 @@
+package rowconv
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Point holds a value of PostgreSQL point column: `(x,y)`
+type Point struct {
+	X, Y float64
+}
+
+// Scan implements sql.Scanner for the textual representation of the point
+func (p *Point) Scan(src interface{}) error {
+	coords, err := scanGeometry(src, reflect.TypeOf(p).Elem(), 2)
+	if err != nil {
+		return err
+	}
+	*p = Point{X: coords[0], Y: coords[1]}
+	return nil
+}
+
+// Value implements driver.Valuer, the point is stored in the textual representation
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+func (p Point) String() string {
+	return "(" + formatCoord(p.X) + "," + formatCoord(p.Y) + ")"
+}
+
+// Box holds a value of PostgreSQL box column: `(x1,y1),(x2,y2)`.
+// PostgreSQL reorders the corners, so the upper right one is returned first.
+type Box struct {
+	High, Low Point
+}
+
+// Scan implements sql.Scanner for the textual representation of the box
+func (b *Box) Scan(src interface{}) error {
+	coords, err := scanGeometry(src, reflect.TypeOf(b).Elem(), 4)
+	if err != nil {
+		return err
+	}
+	*b = Box{High: Point{X: coords[0], Y: coords[1]}, Low: Point{X: coords[2], Y: coords[3]}}
+	return nil
+}
+
+// Value implements driver.Valuer, the box is stored in the textual representation
+func (b Box) Value() (driver.Value, error) {
+	return b.High.String() + "," + b.Low.String(), nil
+}
+
+// Path holds a value of PostgreSQL path column: closed `((x1,y1),...)` or open `[(x1,y1),...]`
+type Path struct {
+	Points []Point
+	Open   bool
+}
+
+// Scan implements sql.Scanner for the textual representation of the path
+func (p *Path) Scan(src interface{}) error {
+	coords, err := scanGeometry(src, reflect.TypeOf(p).Elem(), -1)
+	if err != nil {
+		return err
+	}
+	*p = Path{Points: geometryPoints(coords), Open: strings.HasPrefix(strings.TrimSpace(asString(src)), "[")}
+	return nil
+}
+
+// Value implements driver.Valuer, the path is stored in the textual representation
+func (p Path) Value() (driver.Value, error) {
+	if p.Open {
+		return "[" + formatPoints(p.Points) + "]", nil
+	}
+	return "(" + formatPoints(p.Points) + ")", nil
+}
+
+// Polygon holds a value of PostgreSQL polygon column: `((x1,y1),...)`
+type Polygon struct {
+	Points []Point
+}
+
+// Scan implements sql.Scanner for the textual representation of the polygon
+func (p *Polygon) Scan(src interface{}) error {
+	coords, err := scanGeometry(src, reflect.TypeOf(p).Elem(), -1)
+	if err != nil {
+		return err
+	}
+	*p = Polygon{Points: geometryPoints(coords)}
+	return nil
+}
+
+// Value implements driver.Valuer, the polygon is stored in the textual representation
+func (p Polygon) Value() (driver.Value, error) {
+	return "(" + formatPoints(p.Points) + ")", nil
+}
+
+// Circle holds a value of PostgreSQL circle column: `<(x,y),r>`
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Scan implements sql.Scanner for the textual representation of the circle
+func (c *Circle) Scan(src interface{}) error {
+	coords, err := scanGeometry(src, reflect.TypeOf(c).Elem(), 3)
+	if err != nil {
+		return err
+	}
+	*c = Circle{Center: Point{X: coords[0], Y: coords[1]}, Radius: coords[2]}
+	return nil
+}
+
+// Value implements driver.Valuer, the circle is stored in the textual representation
+func (c Circle) Value() (driver.Value, error) {
+	return "<" + c.Center.String() + "," + formatCoord(c.Radius) + ">", nil
+}
+
+// scanGeometry parses the coordinates of the textual representation of the geometric value of the type,
+// their amount must be equal to expected or be even for the lists of the points if expected is negative
+func scanGeometry(src interface{}, geometryType reflect.Type, expected int) ([]float64, error) {
+	if src == nil {
+		return nil, fmt.Errorf("converting NULL to %v is unsupported", geometryType)
+	}
+
+	text := asString(src)
+	coords, err := parseCoords(text)
+	if err == nil {
+		switch {
+		case expected < 0 && (len(coords) == 0 || len(coords)%2 != 0):
+			err = fmt.Errorf("pairs of coordinates are expected, found: %d", len(coords))
+		case expected >= 0 && len(coords) != expected:
+			err = fmt.Errorf("%d coordinates are expected, found: %d", expected, len(coords))
+		}
+	}
+	if err != nil {
+		return nil, &ParseError{Value: text, Type: geometryType, Err: err}
+	}
+	return coords, nil
+}
+
+// parseCoords returns the numbers of the textual representation of the geometric value regardless of the brackets
+func parseCoords(text string) ([]float64, error) {
+	stripped := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("()[]<>{}", r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	var coords []float64
+	for _, part := range strings.Split(stripped, ",") {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		coords = append(coords, coord)
+	}
+	return coords, nil
+}
+
+func geometryPoints(coords []float64) []Point {
+	points := make([]Point, len(coords)/2)
+	for i := range points {
+		points[i] = Point{X: coords[2*i], Y: coords[2*i+1]}
+	}
+	return points
+}
+
+func formatPoints(points []Point) string {
+	formatted := make([]string, len(points))
+	for i, point := range points {
+		formatted[i] = point.String()
+	}
+	return strings.Join(formatted, ",")
+}
+
+func formatCoord(coord float64) string {
+	return strconv.FormatFloat(coord, 'g', -1, 64)
+}
@@ -0,0 +1,72 @@
+package rowconv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ sql.Scanner   = (*Point)(nil)
+	_ sql.Scanner   = (*Box)(nil)
+	_ sql.Scanner   = (*Path)(nil)
+	_ sql.Scanner   = (*Polygon)(nil)
+	_ sql.Scanner   = (*Circle)(nil)
+	_ driver.Valuer = Point{}
+	_ driver.Valuer = Box{}
+	_ driver.Valuer = Path{}
+	_ driver.Valuer = Polygon{}
+	_ driver.Valuer = Circle{}
+)
+
+func TestGeometryScan(t *testing.T) {
+	triangle := []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0.5, Y: -1.5}}
+	for _, tc := range []struct {
+		text string
+		dst  sql.Scanner
+		exp  interface{}
+	}{
+		{text: "(1.5,-2)", dst: &Point{}, exp: &Point{X: 1.5, Y: -2}},
+		{text: "(2,2),(0,0)", dst: &Box{}, exp: &Box{High: Point{X: 2, Y: 2}, Low: Point{}}},
+		{text: "((0,0),(1,0),(0.5,-1.5))", dst: &Path{}, exp: &Path{Points: triangle}},
+		{text: "[(0,0),(1,0),(0.5,-1.5)]", dst: &Path{}, exp: &Path{Points: triangle, Open: true}},
+		{text: "((0,0),(1,0),(0.5,-1.5))", dst: &Polygon{}, exp: &Polygon{Points: triangle}},
+		{text: "<(1,2),3>", dst: &Circle{}, exp: &Circle{Center: Point{X: 1, Y: 2}, Radius: 3}},
+		{text: "(1e+20,2)", dst: &Point{}, exp: &Point{X: 1e20, Y: 2}},
+	} {
+		if err := tc.dst.Scan([]byte(tc.text)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tc.dst, tc.exp) {
+			t.Errorf("unexpected value of %s: expected %+v, actual %+v", tc.text, tc.exp, tc.dst)
+		}
+		value, err := tc.dst.(driver.Valuer).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		back := reflect.New(reflect.TypeOf(tc.exp).Elem()).Interface().(sql.Scanner)
+		if err := back.Scan(value); err != nil || !reflect.DeepEqual(back, tc.exp) {
+			t.Errorf("value %v of %s expected to be scanned back: %+v, error: %v", value, tc.text, back, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		text string
+		dst  sql.Scanner
+	}{
+		{text: "(1,2,3)", dst: &Point{}},
+		{text: "(a,2)", dst: &Point{}},
+		{text: "((0,0),(1))", dst: &Polygon{}},
+		{text: "", dst: &Path{}},
+	} {
+		var parseErr *ParseError
+		if err := tc.dst.Scan(tc.text); !errors.As(err, &parseErr) {
+			t.Errorf("ParseError expected for malformed value %q of %T, actual: %v", tc.text, tc.dst, err)
+		}
+	}
+	if err := (&Point{}).Scan(nil); err == nil {
+		t.Error("error expected for NULL")
+	}
+}
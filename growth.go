@@ -0,0 +1,49 @@
+package rowconv
+
+import (
+	"reflect"
+)
+
+// SliceGrowth configures how slices of the destinations grow during the propagation of large result sets,
+// where doubling of append causes big reallocation spikes
+type SliceGrowth struct {
+	// Initial returns the capacity reserved before the first row, query is the executed query if it is known
+	// to the propagation, e.g. with Select, and empty otherwise. Capacity is not reserved if Initial is nil.
+	Initial func(query string) int
+	// Chunk is the amount of elements the capacity is grown by once it is exhausted,
+	// the doubling of append is used if it is not positive
+	Chunk int
+}
+
+// WithSliceGrowth applies growth to the slices the rows are propagated into, other destinations are not affected
+func WithSliceGrowth(growth SliceGrowth) Option {
+	return func(o *options) {
+		o.growth = growth
+	}
+}
+
+// withQuery provides the executed query to the options that depend on it
+func withQuery(query string) Option {
+	return func(o *options) {
+		o.query = query
+	}
+}
+
+// applyGrowth reserves the initial capacity of the slice and configures its further growth
+func (ss *sliceSink) applyGrowth(growth SliceGrowth, query string) {
+	ss.chunk = growth.Chunk
+	if growth.Initial != nil {
+		ss.reserve(growth.Initial(query))
+	}
+}
+
+// reserve grows capacity of the slice, so n more elements are appended without reallocation
+func (ss *sliceSink) reserve(n int) {
+	length := ss.slice.Len()
+	if n <= ss.slice.Cap()-length {
+		return
+	}
+	grown := reflect.MakeSlice(ss.slice.Type(), length, length+n)
+	reflect.Copy(grown, ss.slice)
+	ss.slice.Set(grown)
+}
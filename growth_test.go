@@ -0,0 +1,57 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithSliceGrowth(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')",
+		"SELECT id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var ids []int
+	growth := SliceGrowth{Initial: func(query string) int { return 2 }, Chunk: 4}
+	if err := Propagate(&ids, rows, WithSliceGrowth(growth)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("unexpeted results of propagation: %v", ids)
+	}
+	if cap(ids) != 6 {
+		t.Errorf("capacity grown by chunks expected: 6, actual: %d", cap(ids))
+	}
+}
+
+func TestSelectSliceGrowthQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	const query = "SELECT id FROM propagation"
+	var received string
+	growth := SliceGrowth{Initial: func(query string) int {
+		received = query
+		return 100
+	}}
+	var ids []int
+	if err := Select(WithContextOptions(ctx, WithSliceGrowth(growth)), tx, &ids, query); err != nil {
+		t.Fatal(err)
+	}
+	if received != query || cap(ids) != 100 {
+		t.Errorf("capacity reserved for the query expected, query: %q, capacity: %d", received, cap(ids))
+	}
+}
@@ -0,0 +1,251 @@
+package rowconv
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Dialect is the SQL dialect of the generated statements
+type Dialect int
+
+const (
+	// DialectMySQL is the dialect of MySQL and MariaDB
+	DialectMySQL Dialect = iota
+	// DialectPostgres is the dialect of PostgreSQL
+	DialectPostgres
+	// DialectSQLite is the dialect of SQLite
+	DialectSQLite
+	// DialectSQLServer is the dialect of Microsoft SQL Server
+	DialectSQLServer
+)
+
+// BindStyle returns the style of the placeholders of the dialect
+func (d Dialect) BindStyle() BindStyle {
+	switch d {
+	case DialectPostgres:
+		return BindDollar
+	case DialectSQLServer:
+		return BindAt
+	default:
+		return BindQuestion
+	}
+}
+
+// BuildInsert generates multi-row INSERT of the elements of rows into the table and returns it with the flat list
+// of the values for its placeholders. rows is a slice of structs (or references to them), each of their fields
+// is inserted into the column/alias it is mapped to, the same as for Propagate, so writes use the same metadata as reads.
+// NULL is inserted for the fields behind nil references. Fields implementing driver.Valuer, including with pointer receiver,
+// are inserted as the results of their Value.
+func BuildInsert(dialect Dialect, table string, rows interface{}) (string, []interface{}, error) {
+	return Default().BuildInsert(dialect, table, rows)
+}
+
+// BuildInsert is the same as BuildInsert of the package for the mapper
+func (m *Mapper) BuildInsert(dialect Dialect, table string, rows interface{}) (string, []interface{}, error) {
+	rowsValue, accessors, err := m.state.insertRows(rows)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildInsert(dialect, table, rowsValue, accessors)
+}
+
+// BuildUpsert generates multi-row INSERT the same way as BuildInsert, that updates the existing rows with the same keys
+// instead of failing. Keys are the columns/aliases of the fields tagged with `key` option, e.g. `db_column:"id,key"`,
+// other columns are updated with the inserted values: with `ON CONFLICT (keys) DO UPDATE` for PostgreSQL and SQLite
+// and with `ON DUPLICATE KEY UPDATE` for MySQL, where keys must be covered by the unique index. SQL Server is not supported.
+func BuildUpsert(dialect Dialect, table string, rows interface{}) (string, []interface{}, error) {
+	return Default().BuildUpsert(dialect, table, rows)
+}
+
+// BuildUpsert is the same as BuildUpsert of the package for the mapper
+func (m *Mapper) BuildUpsert(dialect Dialect, table string, rows interface{}) (string, []interface{}, error) {
+	rowsValue, accessors, err := m.state.insertRows(rows)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var keys, updated []string
+	for _, accessor := range accessors {
+		if hasOption(accessor.options, "key") {
+			keys = append(keys, accessor.columnAlias)
+		} else {
+			updated = append(updated, accessor.columnAlias)
+		}
+	}
+	if len(keys) == 0 {
+		return "", nil, fmt.Errorf("%v has no fields tagged with key option", derefType(rowsValue.Type().Elem()))
+	}
+
+	query, args, err := buildInsert(dialect, table, rowsValue, accessors)
+	if err != nil {
+		return "", nil, err
+	}
+	switch dialect {
+	case DialectPostgres, DialectSQLite:
+		query += " ON CONFLICT (" + strings.Join(keys, ", ") + ")"
+		if len(updated) == 0 {
+			return query + " DO NOTHING", args, nil
+		}
+		assignments := make([]string, len(updated))
+		for i, column := range updated {
+			assignments[i] = column + " = EXCLUDED." + column
+		}
+		return query + " DO UPDATE SET " + strings.Join(assignments, ", "), args, nil
+
+	case DialectMySQL:
+		if len(updated) == 0 {
+			// the row is left as it is
+			updated = keys[:1]
+		}
+		assignments := make([]string, len(updated))
+		for i, column := range updated {
+			assignments[i] = column + " = VALUES(" + column + ")"
+		}
+		return query + " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", "), args, nil
+
+	default:
+		return "", nil, fmt.Errorf("upsert is not supported for the dialect: %d", dialect)
+	}
+}
+
+// insertRows validates rows to insert and returns them with the accessors of the inserted fields
+func (st *state) insertRows(rows interface{}) (reflect.Value, []fieldAccessor, error) {
+	rowsValue := reflect.ValueOf(rows)
+	if rowsValue.Kind() != reflect.Slice {
+		return reflect.Value{}, nil, fmt.Errorf("slice is expected, received: %T", rows)
+	}
+	if rowsValue.Len() == 0 {
+		return reflect.Value{}, nil, errors.New("no rows to insert")
+	}
+
+	accessors, err := st.insertAccessors(rowsValue.Type().Elem())
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return rowsValue, accessors, nil
+}
+
+// buildInsert generates INSERT of the fields of the accessors of the rows
+func buildInsert(dialect Dialect, table string, rowsValue reflect.Value, accessors []fieldAccessor) (string, []interface{}, error) {
+	var query strings.Builder
+	query.WriteString("INSERT INTO " + table + " (" + accessorColumns(accessors) + ") VALUES ")
+	args := make([]interface{}, 0, rowsValue.Len()*len(accessors))
+	for i := 0; i < rowsValue.Len(); i++ {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteByte('(')
+		for j, accessor := range accessors {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			value, err := insertValue(rowsValue.Index(i), accessor)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, value)
+			query.WriteString(dialect.BindStyle().placeholder(len(args)))
+		}
+		query.WriteByte(')')
+	}
+	return query.String(), args, nil
+}
+
+// insertAccessors returns accessors of the fields of the struct contained in elementType that are written into
+// the columns, in order of declaration of the fields
+func (st *state) insertAccessors(elementType reflect.Type) ([]fieldAccessor, error) {
+	structType, _, err := unwrapPtrStructType(elementType)
+	if err != nil {
+		return nil, err
+	}
+	columnAliasToAccessor, err := st.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessors []fieldAccessor
+	for _, accessor := range columnAliasToAccessor {
+		// nested structs are written by their own fields, values inside of JSON columns can't be written separately
+		if accessor.nested || strings.Contains(accessor.columnAlias, jsonPathSeparator) {
+			continue
+		}
+		if !isExportedPath(structType, accessor.fieldIndex) {
+			continue
+		}
+		accessors = append(accessors, accessor)
+	}
+	if len(accessors) == 0 {
+		return nil, fmt.Errorf("%v has no fields mapped to columns", structType)
+	}
+	sort.Slice(accessors, func(i, j int) bool {
+		return lessIndex(accessors[i].fieldIndex, accessors[j].fieldIndex)
+	})
+	return accessors, nil
+}
+
+// isExportedPath returns true if all fields on the path to the field by index are exported, so the field can be read
+func isExportedPath(structType reflect.Type, index []int) bool {
+	for i := range index {
+		if _, field, _ := structFieldByIndex(structType, index[:i+1]); field.PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// accessorColumns returns comma-separated columns/aliases of the accessors
+func accessorColumns(accessors []fieldAccessor) string {
+	columns := make([]string, len(accessors))
+	for i, accessor := range accessors {
+		columns[i] = accessor.columnAlias
+	}
+	return strings.Join(columns, ", ")
+}
+
+// insertValue returns the value of the field of the row, nil if the field is behind nil reference
+func insertValue(row reflect.Value, accessor fieldAccessor) (interface{}, error) {
+	for row.Kind() == reflect.Ptr {
+		if row.IsNil() {
+			return nil, nil
+		}
+		row = row.Elem()
+	}
+	field, err := row.FieldByIndexErr(accessor.fieldIndex)
+	if err != nil {
+		return nil, nil
+	}
+	value, err := driverValue(field)
+	if err != nil {
+		return nil, fmt.Errorf("value of field %s can't be written: %w", strings.Join(accessor.fieldPath, "."), err)
+	}
+	return value, nil
+}
+
+// driverValue returns the value of the field, the result of Value for fields implementing driver.Valuer,
+// including the ones with pointer receiver, so custom types are written the same way they are scanned.
+// Nil references to types implementing driver.Valuer with value receiver are written as NULL, the same as database/sql does.
+func driverValue(field reflect.Value) (interface{}, error) {
+	if field.Kind() == reflect.Ptr && field.IsNil() {
+		if field.Type().Elem().Implements(valuerType) {
+			return nil, nil
+		}
+	}
+
+	switch {
+	case field.Type().Implements(valuerType):
+		return field.Interface().(driver.Valuer).Value()
+	case reflect.PtrTo(field.Type()).Implements(valuerType):
+		if !field.CanAddr() {
+			addressable := reflect.New(field.Type()).Elem()
+			addressable.Set(field)
+			field = addressable
+		}
+		return field.Addr().Interface().(driver.Valuer).Value()
+	default:
+		return field.Interface(), nil
+	}
+}
@@ -0,0 +1,223 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildInsert(t *testing.T) {
+	type audit struct {
+		CreatedBy string `db_column:"owner"`
+	}
+	type valStruct struct {
+		Id     int `db_column:"pk"`
+		Col1   string
+		Audit  *audit
+		hidden string
+	}
+	rows := []*valStruct{{Id: 1, Col1: "a", Audit: &audit{CreatedBy: "root"}}, {Id: 2, Col1: "b"}}
+
+	query, args, err := BuildInsert(DialectPostgres, "t", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "INSERT INTO t (pk, col1, owner) VALUES ($1, $2, $3), ($4, $5, $6)"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+	if exp := []interface{}{1, "a", "root", 2, "b", nil}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	query, _, err = BuildInsert(DialectMySQL, "t", rows[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "INSERT INTO t (pk, col1, owner) VALUES (?, ?, ?)"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+
+	if _, _, err := BuildInsert(DialectMySQL, "t", []valStruct{}); err == nil {
+		t.Error("error expected for no rows")
+	}
+	if _, _, err := BuildInsert(DialectMySQL, "t", []int{1}); err == nil {
+		t.Error("error expected for rows of non-struct type")
+	}
+}
+
+// upperValue is written upper-cased, its Value has pointer receiver
+type upperValue string
+
+func (uv *upperValue) Value() (driver.Value, error) {
+	if *uv == "" {
+		return nil, errors.New("empty value")
+	}
+	return strings.ToUpper(string(*uv)), nil
+}
+
+// csvValue is written as comma-separated values, its Value has value receiver
+type csvValue []string
+
+func (cv csvValue) Value() (driver.Value, error) {
+	return strings.Join(cv, ","), nil
+}
+
+func TestBuildInsertValuer(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 upperValue
+		Col2 *csvValue
+		Col3 csvValue
+	}
+	rows := []valStruct{{Id: 1, Col1: "a", Col2: &csvValue{"b", "c"}, Col3: csvValue{"d"}}, {Id: 2, Col1: "e"}}
+
+	_, args, err := BuildInsert(DialectMySQL, "t", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []interface{}{1, "A", "b,c", "d", 2, "E", nil, ""}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	if _, _, err := BuildInsert(DialectMySQL, "t", []valStruct{{Id: 3}}); err == nil {
+		t.Error("error of Value expected to be returned")
+	}
+}
+
+func TestBuildInsertExec(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+		Col2 *string
+	}
+	dialect := DialectMySQL
+	if driverName() == "postgres" {
+		dialect = DialectPostgres
+	}
+	query, args, err := BuildInsert(dialect, "propagation", []valStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []valStruct
+	if err := Select(ctx, tx, &results, "SELECT id, col1, col2 FROM propagation ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}}; !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
+
+func TestBuildUpsert(t *testing.T) {
+	type valStruct struct {
+		Id   int `db_column:"id,key"`
+		Col1 string
+		Col2 *string
+	}
+	rows := []valStruct{{Id: 1, Col1: "a"}}
+
+	query, args, err := BuildUpsert(DialectPostgres, "t", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "INSERT INTO t (id, col1, col2) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET col1 = EXCLUDED.col1, col2 = EXCLUDED.col2"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+	if len(args) != 3 {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	query, _, err = BuildUpsert(DialectMySQL, "t", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "INSERT INTO t (id, col1, col2) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE col1 = VALUES(col1), col2 = VALUES(col2)"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+
+	type keysOnly struct {
+		Id int `db_column:"id,key"`
+	}
+	query, _, err = BuildUpsert(DialectSQLite, "t", []keysOnly{{Id: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "INSERT INTO t (id) VALUES (?) ON CONFLICT (id) DO NOTHING"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+
+	type noKeys struct {
+		Id int
+	}
+	if _, _, err := BuildUpsert(DialectPostgres, "t", []noKeys{{Id: 1}}); err == nil {
+		t.Error("error expected for rows without keys")
+	}
+	if _, _, err := BuildUpsert(DialectSQLServer, "t", rows); err == nil {
+		t.Error("error expected for unsupported dialect")
+	}
+}
+
+func TestBuildUpsertExec(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a')"); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int `db_column:"id,key"`
+		Col1 string
+	}
+	dialect := DialectMySQL
+	switch driverName() {
+	case "postgres":
+		dialect = DialectPostgres
+	case "sqlite3":
+		dialect = DialectSQLite
+	}
+	query, args, err := BuildUpsert(dialect, "propagation", []valStruct{{Id: 1, Col1: "b"}, {Id: 2, Col1: "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []valStruct
+	if err := Select(ctx, tx, &results, "SELECT id, col1 FROM propagation ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Col1: "b"}, {Id: 2, Col1: "c"}}; !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
@@ -0,0 +1,44 @@
+package rowconv
+
+import (
+	"reflect"
+)
+
+// ColumnInterceptor receives the value of the column as returned by database driver, nil for NULL, before it is stored
+// into the field and returns the value to store instead, e.g. decrypted value or value normalized to the common unit
+type ColumnInterceptor func(column string, raw interface{}) (interface{}, error)
+
+// interceptor makes ColumnInterceptor comparable, so it can be a part of the cache key
+type interceptor struct {
+	intercept ColumnInterceptor
+}
+
+// WithColumnInterceptor passes values of the columns mapped to the fields through intercept between the scan
+// and the assignment to the field, e.g. by NewMapper to intercept values for all the models propagated with the mapper.
+// Mappers compiled with the option aren't kept in the cache shared by propagations, each propagation compiles its own one.
+func WithColumnInterceptor(intercept ColumnInterceptor) Option {
+	ic := &interceptor{intercept: intercept}
+	return func(o *options) {
+		o.compile.interceptor = ic
+	}
+}
+
+// intercepted returns converter that passes values of the column through the interceptor before convert,
+// convert is returned as is if there is no interceptor
+func (copts compileOptions) intercepted(column string, convert converter) converter {
+	if copts.interceptor == nil {
+		return convert
+	}
+	if convert == nil {
+		convert = convertReference(convertDefault)
+	}
+
+	intercept := copts.interceptor.intercept
+	return func(src interface{}, dst reflect.Value) error {
+		value, err := intercept(column, src)
+		if err != nil {
+			return err
+		}
+		return convert(value, dst)
+	}
+}
@@ -0,0 +1,70 @@
+package rowconv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithColumnInterceptor(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+		Col2 *string
+	}
+	var intercepted []string
+	mapper := NewMapper(WithColumnInterceptor(func(column string, raw interface{}) (interface{}, error) {
+		intercepted = append(intercepted, column)
+		if column == "col1" && raw != nil {
+			return strings.ToUpper(asString(raw)), nil
+		}
+		return raw, nil
+	}))
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	var results []valStruct
+	if err := mapper.Propagate(&results, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Col1: "A"}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+	if !reflect.DeepEqual(intercepted, []string{"id", "col1", "col2"}) {
+		t.Errorf("unexpected intercepted columns: %v", intercepted)
+	}
+}
+
+func TestWithColumnInterceptorNotCached(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	mapper := NewMapper()
+	for i := 0; i < 3; i++ {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+			"SELECT id, col1 FROM propagation",
+		)
+
+		var results []valStruct
+		err := mapper.Propagate(&results, rows, WithColumnInterceptor(func(column string, raw interface{}) (interface{}, error) {
+			return raw, nil
+		}))
+		release()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapper.state.scanDefinitions.RLock()
+	defer mapper.state.scanDefinitions.RUnlock()
+	if amount := len(mapper.state.scanDefinitions.byKey); amount != 0 {
+		t.Errorf("no cached definitions expected for the inline interceptors, actual: %d", amount)
+	}
+}
@@ -0,0 +1,161 @@
+package rowconv
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Interval holds a value of PostgreSQL interval column. Months and days are kept apart from the time
+// as their length in time varies, e.g. because of daylight saving time.
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// Duration returns the interval as time.Duration assuming a day is 24 hours and a month is 30 days
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i.Months)*30*24*time.Hour + time.Duration(i.Days)*24*time.Hour + time.Duration(i.Microseconds)*time.Microsecond
+}
+
+// Scan implements sql.Scanner for the interval in postgres output style: `1 year 2 mons -3 days 04:05:06.5`
+// and for the duration in the format of MySQL TIME: `-838:59:59`
+func (i *Interval) Scan(src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("converting NULL to %T is unsupported", i)
+	}
+
+	text := asString(src)
+	interval, err := parseInterval(text)
+	if err != nil {
+		return &ParseError{Value: text, Type: reflect.TypeOf(i).Elem(), Err: err}
+	}
+	*i = interval
+	return nil
+}
+
+// Value implements driver.Valuer, the interval is stored in postgres output style
+func (i Interval) Value() (driver.Value, error) {
+	micros, sign := i.Microseconds, ""
+	if micros < 0 {
+		micros, sign = -micros, "-"
+	}
+	clock := time.Duration(micros) * time.Microsecond
+	return fmt.Sprintf("%d mons %d days %s%02d:%02d:%09.6f", i.Months, i.Days, sign,
+		int64(clock/time.Hour), int64(clock%time.Hour/time.Minute), (clock % time.Minute).Seconds()), nil
+}
+
+// convertDuration stores the interval into time.Duration field, intervals of months can't be stored
+// as their length varies; numbers are stored as nanoseconds
+func convertDuration(src interface{}, dst reflect.Value) error {
+	if value, ok := src.(int64); ok {
+		dst.SetInt(value)
+		return nil
+	}
+	if src == nil {
+		return convertDefault(src, dst)
+	}
+
+	text := asString(src)
+	interval, err := parseInterval(text)
+	if err == nil && interval.Months != 0 {
+		err = errors.New("interval of months has no fixed duration")
+	}
+	if err != nil {
+		return &ParseError{Value: text, Type: dst.Type(), Err: err}
+	}
+	dst.SetInt(int64(interval.Duration()))
+	return nil
+}
+
+// addIntervalUnit adds amount of the unit of postgres output style to the interval
+func addIntervalUnit(interval *Interval, amount int64, unit string) error {
+	switch strings.TrimSuffix(strings.ToLower(unit), "s") {
+	case "year":
+		interval.Months += int32(amount * 12)
+	case "mon", "month":
+		interval.Months += int32(amount)
+	case "day":
+		interval.Days += int32(amount)
+	case "hour":
+		interval.Microseconds += amount * int64(time.Hour/time.Microsecond)
+	case "min", "minute":
+		interval.Microseconds += amount * int64(time.Minute/time.Microsecond)
+	case "sec", "second":
+		interval.Microseconds += amount * int64(time.Second/time.Microsecond)
+	default:
+		return errors.New("unknown unit: " + unit)
+	}
+	return nil
+}
+
+func parseInterval(text string) (Interval, error) {
+	var interval Interval
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return interval, errors.New("empty interval")
+	}
+
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			micros, err := parseClock(fields[i])
+			if err != nil {
+				return interval, err
+			}
+			interval.Microseconds += micros
+			continue
+		}
+
+		amount, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return interval, err
+		}
+		if i+1 == len(fields) {
+			return interval, fmt.Errorf("no unit for amount %d", amount)
+		}
+		i++
+		if err := addIntervalUnit(&interval, amount, fields[i]); err != nil {
+			return interval, err
+		}
+	}
+	return interval, nil
+}
+
+// parseClock parses [-+]hh:mm[:ss[.ffffff]] into microseconds
+func parseClock(clock string) (int64, error) {
+	sign := int64(1)
+	switch {
+	case strings.HasPrefix(clock, "-"):
+		sign, clock = -1, clock[1:]
+	case strings.HasPrefix(clock, "+"):
+		clock = clock[1:]
+	}
+
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errors.New("invalid time of interval: " + clock)
+	}
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	var seconds float64
+	if len(parts) == 3 {
+		if seconds, err = strconv.ParseFloat(parts[2], 64); err != nil {
+			return 0, err
+		}
+	}
+	micros := (hours*60+minutes)*int64(time.Minute/time.Microsecond) + int64(seconds*1e6+0.5)
+	return sign * micros, nil
+}
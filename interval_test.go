@@ -0,0 +1,59 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntervalScan(t *testing.T) {
+	for text, exp := range map[string]Interval{
+		"1 year 2 mons -3 days 04:05:06.5": {Months: 14, Days: -3, Microseconds: 14706500000},
+		"-01:00:00":                        {Microseconds: -3600000000},
+		"838:59:59":                        {Microseconds: 3020399000000},
+		"3 days":                           {Days: 3},
+		"1 day -00:00:01":                  {Days: 1, Microseconds: -1000000},
+	} {
+		var act Interval
+		if err := act.Scan([]byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Errorf("unexpected interval of %s: expected %+v, actual %+v", text, exp, act)
+		}
+	}
+
+	for _, text := range []string{"", "1", "1 fortnight", "1:2:3:4"} {
+		var i Interval
+		if err := i.Scan(text); err == nil {
+			t.Errorf("error expected for malformed interval: %q", text)
+		}
+	}
+}
+
+func TestIntervalValue(t *testing.T) {
+	exp := Interval{Months: 14, Days: -3, Microseconds: -14706500000}
+	value, err := exp.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act Interval
+	if err := act.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if act != exp {
+		t.Errorf("unexpected interval of %v: expected %+v, actual %+v", value, exp, act)
+	}
+}
+
+func TestConvertDuration(t *testing.T) {
+	copts := compileOptions{state: Default().state}
+	var d time.Duration
+	convert := copts.converter(reflect.TypeOf(d), nil)
+	if err := convert("1 day 01:30:00", reflect.ValueOf(&d).Elem()); err != nil || d != 25*time.Hour+30*time.Minute {
+		t.Errorf("unexpected duration: %v, error: %v", d, err)
+	}
+	if err := convert("1 mon", reflect.ValueOf(&d).Elem()); err == nil {
+		t.Error("error expected for interval of months")
+	}
+}
@@ -0,0 +1,88 @@
+package rowconv
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// PropagateJSON writes rows into w as a JSON array of objects while iterating over them, so the result set is
+// never fully kept in memory. Keys of the objects are column/alias names, values are as returned by database driver:
+// bytes of textual columns are written as strings and bytes of binary columns as base64 encoded strings.
+// UNIQUEIDENTIFIER values of SQL Server are written in canonical UUID form.
+func PropagateJSON(w io.Writer, rows *sql.Rows) error {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	// keys are encoded once as they are the same for all objects
+	keys := make([][]byte, len(columnTypes))
+	binary := make([]bool, len(columnTypes))
+	guid := make([]bool, len(columnTypes))
+	for i, columnType := range columnTypes {
+		if keys[i], err = json.Marshal(columnType.Name()); err != nil {
+			return err
+		}
+		binary[i] = isBinaryColumn(columnType)
+		guid[i] = isUniqueIdentifierColumn(columnType)
+	}
+
+	values := make([]interface{}, len(columnTypes))
+	scanDestinations := make([]interface{}, len(columnTypes))
+	for i := range values {
+		scanDestinations[i] = &values[i]
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteByte('[')
+	for first := true; rows.Next(); first = false {
+		if err := rows.Scan(scanDestinations...); err != nil {
+			return err
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		out.WriteByte('{')
+		for i, value := range values {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			out.Write(keys[i])
+			out.WriteByte(':')
+
+			if guid[i] {
+				value, _ = canonicalUniqueIdentifier(value)
+			}
+			if bytes, ok := value.([]byte); ok && !binary[i] {
+				value = string(bytes)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			out.Write(encoded)
+		}
+		out.WriteByte('}')
+
+		// rows are flushed one by one so the consumer can start processing right away
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	out.WriteByte(']')
+	return out.Flush()
+}
+
+// isBinaryColumn returns true if column holds binary data rather than text according to its database type name
+func isBinaryColumn(columnType *sql.ColumnType) bool {
+	typeName := databaseTypeName(columnType)
+	return strings.Contains(typeName, "BLOB") || strings.Contains(typeName, "BINARY") || typeName == "BYTEA"
+}
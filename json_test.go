@@ -0,0 +1,39 @@
+package rowconv
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPropagateJSON(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT col1, col2 AS alias FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var out bytes.Buffer
+	if err := PropagateJSON(&out, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := `[{"col1":"a","alias":null},{"col1":"b","alias":"c"}]`
+	if out.String() != exp {
+		t.Errorf("unexpeted results of propagation: expected %s, actual %s", exp, out.String())
+	}
+}
+
+func TestPropagateJSONEmpty(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT col1 FROM propagation WHERE id > 1",
+	)
+	defer release()
+
+	var out bytes.Buffer
+	if err := PropagateJSON(&out, rows); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[]" {
+		t.Errorf("unexpeted results of propagation: %s", out.String())
+	}
+}
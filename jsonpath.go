@@ -0,0 +1,181 @@
+package rowconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const jsonPathSeparator = "->"
+
+// splitJSONPath splits column/alias of the tag that refers to the value inside of the JSON column,
+// e.g. `db_column:"payload->user->name"`, into the name of the column and the path of the value
+func splitJSONPath(columnAlias string) (string, []string) {
+	parts := strings.Split(columnAlias, jsonPathSeparator)
+	return parts[0], parts[1:]
+}
+
+// jsonPathField is a field that receives the value found by the path inside of the JSON column,
+// empty path means the field receives the column value itself
+type jsonPathField struct {
+	columnAlias string
+	fieldIndex  []int
+	path        []string
+	convert     converter
+}
+
+// holderByJSONPaths creates holder that parses the JSON column once per row and distributes its values to the fields
+func holderByJSONPaths(column string, accessors []fieldAccessor, copts compileOptions) holderSupplier {
+	fields := make([]jsonPathField, len(accessors))
+	for i, accessor := range accessors {
+		convert := copts.converter(accessor.fieldType, accessor.options)
+		if convert == nil {
+			convert = convertReference(convertDefault)
+		}
+		_, path := splitJSONPath(accessor.columnAlias)
+		fields[i] = jsonPathField{columnAlias: accessor.columnAlias, fieldIndex: accessor.fieldIndex, path: path, convert: convert}
+	}
+
+	return func(underlyingValue reflect.Value) interface{} {
+		return &jsonPathScanner{column: column, fields: fields, underlyingValue: underlyingValue}
+	}
+}
+
+type jsonPathScanner struct {
+	column          string
+	fields          []jsonPathField
+	underlyingValue reflect.Value
+}
+
+func (js *jsonPathScanner) Scan(src interface{}) error {
+	var document interface{}
+	parsed := false
+	for _, field := range js.fields {
+		scanner := &fieldScanner{column: field.columnAlias, field: js.underlyingValue.FieldByIndex(field.fieldIndex), convert: field.convert}
+		if len(field.path) == 0 {
+			if err := scanner.Scan(src); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// fields referring to values inside of NULL column are left untouched
+		if src == nil {
+			continue
+		}
+		if !parsed {
+			decoder := json.NewDecoder(bytes.NewReader(asBytes(src)))
+			decoder.UseNumber()
+			if err := decoder.Decode(&document); err != nil {
+				return &ParseError{Column: js.column, Value: asString(src), Type: scanner.field.Type(), Err: err}
+			}
+			parsed = true
+		}
+
+		value, found := jsonPathValue(document, field.path)
+		if !found {
+			continue
+		}
+		if err := assignJSONValue(scanner, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonPathValue looks up the value by the path of object keys and array indexes
+func jsonPathValue(document interface{}, path []string) (interface{}, bool) {
+	for _, step := range path {
+		switch node := document.(type) {
+		case map[string]interface{}:
+			value, found := node[step]
+			if !found {
+				return nil, false
+			}
+			document = value
+		case []interface{}:
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			document = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return document, true
+}
+
+// assignJSONValue stores scalar JSON values the same way as column values,
+// objects and arrays are stored as JSON text into text fields and are unmarshalled into fields of other types
+func assignJSONValue(scanner *fieldScanner, value interface{}) error {
+	switch value := value.(type) {
+	case json.Number:
+		return scanner.Scan(string(value))
+	case map[string]interface{}, []interface{}:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		valueType := scanner.field.Type()
+		for valueType.Kind() == reflect.Ptr {
+			valueType = valueType.Elem()
+		}
+		if valueType.Kind() == reflect.String || valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8 {
+			return scanner.Scan(raw)
+		}
+		if err := json.Unmarshal(raw, scanner.field.Addr().Interface()); err != nil {
+			return &ParseError{Column: scanner.column, Value: string(raw), Type: scanner.field.Type(), Err: err}
+		}
+		return nil
+	default:
+		return scanner.Scan(value)
+	}
+}
+
+// jsonColumns are database types of JSON columns
+var jsonColumns = map[string]struct{}{
+	"JSON":  {},
+	"JSONB": {},
+}
+
+func isJSONColumn(columnType columnType) bool {
+	_, isJSON := jsonColumns[databaseTypeName(columnType)]
+	return isJSON
+}
+
+// isJSONTarget returns true for the types values of JSON columns are unmarshalled into without tag options:
+// structs, maps and slices (or references to them) that are not scanned otherwise
+func (st *state) isJSONTarget(forType reflect.Type) bool {
+	valueType := derefType(forType)
+	if reflect.PtrTo(valueType).Implements(scannerType) {
+		return false
+	}
+	switch valueType.Kind() {
+	case reflect.Struct:
+		return !st.isSmallestStructDecomposition(valueType)
+	case reflect.Map:
+		return true
+	case reflect.Slice:
+		return valueType.Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// convertJSON unmarshals value of JSON column into dst, NULL is stored as zero value
+func convertJSON(src interface{}, dst reflect.Value) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	raw := asBytes(src)
+	if err := json.Unmarshal(raw, dst.Addr().Interface()); err != nil {
+		return &ParseError{Value: string(raw), Type: dst.Type(), Err: err}
+	}
+	return nil
+}
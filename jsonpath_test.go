@@ -0,0 +1,71 @@
+package rowconv
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagateJSONPath(t *testing.T) {
+	rows, release := queryPropagation(t,
+		`INSERT INTO propagation(id, col1) VALUES (1, '{"u":{"n":"a","a":7}}'), (2, '{"u":{"n":null}}')`,
+		"SELECT id, col1 AS payload FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Name *string `db_column:"payload->u->n"`
+		Age  int     `db_column:"payload->u->a"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Name: StringRef("a"), Age: 7}, {Id: 2}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestJSONPathValue(t *testing.T) {
+	document := map[string]interface{}{
+		"user": map[string]interface{}{"tags": []interface{}{"a", "b"}},
+	}
+	for _, tc := range []struct {
+		path  []string
+		exp   interface{}
+		found bool
+	}{
+		{path: []string{"user", "tags", "1"}, exp: "b", found: true},
+		{path: []string{"user", "tags", "2"}},
+		{path: []string{"user", "name"}},
+		{path: []string{"user", "tags", "1", "x"}},
+	} {
+		if act, found := jsonPathValue(document, tc.path); found != tc.found || act != tc.exp {
+			t.Errorf("unexpected value by path %v: expected %v (%v), actual %v (%v)", tc.path, tc.exp, tc.found, act, found)
+		}
+	}
+}
+
+func TestIsJSONTarget(t *testing.T) {
+	type payload struct {
+		Name string
+	}
+	for forType, exp := range map[reflect.Type]bool{
+		reflect.TypeOf(payload{}):                true,
+		reflect.TypeOf(&payload{}):               true,
+		reflect.TypeOf(map[string]interface{}{}): true,
+		reflect.TypeOf([]int{}):                  true,
+		reflect.TypeOf([]byte{}):                 false,
+		reflect.TypeOf(""):                       false,
+		reflect.TypeOf(time.Time{}):              false,
+		reflect.TypeOf(sql.NullString{}):         false,
+		reflect.TypeOf(Range[int]{}):             false,
+	} {
+		if actual := Default().state.isJSONTarget(forType); actual != exp {
+			t.Errorf("unexpected JSON target check of %v: expected %v, actual %v", forType, exp, actual)
+		}
+	}
+}
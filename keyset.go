@@ -0,0 +1,125 @@
+package rowconv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Cursor is the position of the row in the order of the keys of its struct: the values of the fields tagged
+// with `key` option in order of their declaration. The cursor of the last row of the page is passed to SelectKeyset
+// to select the next page.
+type Cursor []interface{}
+
+// KeysetPage is the page of the rows selected by SelectKeyset
+type KeysetPage[T any] struct {
+	// Items are the rows of the page
+	Items []T
+	// Next is the cursor of the last row of the page, it is nil if there are no more rows
+	Next Cursor
+}
+
+// SelectKeyset selects the page of the rows of the table of struct T that follow the row at the cursor in order
+// of the keys of T and satisfy the optional condition with args, e.g. `SelectKeyset[User](ctx, db, DialectPostgres,
+// cursor, 100, "status = $1", status)`. The table is declared the same way as for SelectAll and the keys are the fields
+// tagged with `key` option, e.g. `db_column:"id,key"`; together they must identify the row for the pages to be stable.
+// The first page is selected with nil cursor. Unlike offset pagination the cost of the page doesn't depend on its
+// position when the keys are covered by the index. The placeholders of the condition must be in the style of the dialect.
+func SelectKeyset[T any](ctx context.Context, q Queryer, dialect Dialect, after Cursor, limit int, condition string, args ...interface{}) (KeysetPage[T], error) {
+	return SelectKeysetOn[T](Default(), ctx, q, dialect, after, limit, condition, args...)
+}
+
+// SelectKeysetOn is the same as SelectKeyset, the table, the columns and the keys of T are taken with the mapper m
+func SelectKeysetOn[T any](m *Mapper, ctx context.Context, q Queryer, dialect Dialect, after Cursor, limit int, condition string, args ...interface{}) (KeysetPage[T], error) {
+	if limit <= 0 {
+		return KeysetPage[T]{}, fmt.Errorf("invalid page limit: %d", limit)
+	}
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return KeysetPage[T]{}, fmt.Errorf("keyset pagination is supported only for struct types, received: %v", structType)
+	}
+	st := m.state
+	table, err := st.tableOf(structType)
+	if err != nil {
+		return KeysetPage[T]{}, err
+	}
+	accessors, err := st.insertAccessors(structType)
+	if err != nil {
+		return KeysetPage[T]{}, err
+	}
+	var keys []fieldAccessor
+	for _, accessor := range accessors {
+		if hasOption(accessor.options, "key") {
+			keys = append(keys, accessor)
+		}
+	}
+	if len(keys) == 0 {
+		return KeysetPage[T]{}, fmt.Errorf("%v has no fields tagged with key option", structType)
+	}
+	if len(after) != 0 && len(after) != len(keys) {
+		return KeysetPage[T]{}, fmt.Errorf("cursor of %v must have %d values, received: %d", structType, len(keys), len(after))
+	}
+
+	var conditions []string
+	if condition != "" {
+		conditions = append(conditions, "("+condition+")")
+	}
+	args = append([]interface{}(nil), args...)
+	if len(after) != 0 {
+		var predicate string
+		predicate, args = keysetPredicate(dialect, keys, after, args)
+		conditions = append(conditions, predicate)
+	}
+	query := selectQuery(table, accessors, strings.Join(conditions, " AND ")) + " ORDER BY " + accessorColumns(keys)
+	if dialect == DialectSQLServer {
+		query += " OFFSET 0 ROWS FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY"
+	} else {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+
+	var page KeysetPage[T]
+	if err := m.Select(ctx, q, &page.Items, query, args...); err != nil {
+		return KeysetPage[T]{}, err
+	}
+	if len(page.Items) == limit {
+		last := reflect.ValueOf(page.Items[len(page.Items)-1])
+		page.Next = make(Cursor, len(keys))
+		for i, key := range keys {
+			if page.Next[i], err = insertValue(last, key); err != nil {
+				return KeysetPage[T]{}, err
+			}
+		}
+	}
+	return page, nil
+}
+
+// keysetPredicate generates predicate of the rows that follow the cursor, the values of the cursor are appended to args.
+// Row values are compared as a whole, except for SQL Server that doesn't support their comparison:
+// `(k1, k2) > (?, ?)` is expanded into `(k1 > ? OR k1 = ? AND k2 > ?)` for it.
+func keysetPredicate(dialect Dialect, keys []fieldAccessor, after Cursor, args []interface{}) (string, []interface{}) {
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		return dialect.BindStyle().placeholder(len(args))
+	}
+
+	if dialect != DialectSQLServer {
+		placeholders := make([]string, len(after))
+		for i, value := range after {
+			placeholders[i] = bind(value)
+		}
+		return "(" + accessorColumns(keys) + ") > (" + strings.Join(placeholders, ", ") + ")", args
+	}
+
+	alternatives := make([]string, len(keys))
+	for i := range keys {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, keys[j].columnAlias+" = "+bind(after[j]))
+		}
+		terms = append(terms, keys[i].columnAlias+" > "+bind(after[i]))
+		alternatives[i] = strings.Join(terms, " AND ")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
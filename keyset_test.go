@@ -0,0 +1,88 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type keysetRow struct {
+	_    struct{} `db_table:"propagation"`
+	Col1 string   `db_column:"col1,key"`
+	Id   int      `db_column:"id,key"`
+}
+
+func TestSelectKeyset(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'b'), (2, 'a'), (3, 'b'), (4, 'a'), (5, 'c')"); err != nil {
+		t.Fatal(err)
+	}
+
+	dialect, condition := DialectMySQL, "id <> ?"
+	if driverName() == "postgres" {
+		dialect, condition = DialectPostgres, "id <> $1"
+	}
+
+	var act []keysetRow
+	var cursor Cursor
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("too many pages: %+v", act)
+		}
+		page, err := SelectKeyset[keysetRow](ctx, tx, dialect, cursor, 2, condition, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act = append(act, page.Items...)
+		if page.Next == nil {
+			break
+		}
+		cursor = page.Next
+	}
+	exp := []keysetRow{{Col1: "a", Id: 2}, {Col1: "b", Id: 1}, {Col1: "b", Id: 3}, {Col1: "c", Id: 5}}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpeted results of pagination: expected %+v, actual %+v", exp, act)
+	}
+
+	if _, err := SelectKeyset[keysetRow](ctx, tx, dialect, Cursor{"a"}, 2, ""); err == nil {
+		t.Error("error expected for cursor that doesn't match the keys")
+	}
+	if _, err := SelectKeyset[tableUser](ctx, tx, dialect, nil, 2, ""); err == nil {
+		t.Error("error expected for struct without keys")
+	}
+}
+
+func TestKeysetPredicate(t *testing.T) {
+	keys, err := Default().state.insertAccessors(reflect.TypeOf(keysetRow{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	predicate, args := keysetPredicate(DialectPostgres, keys, Cursor{"b", 3}, []interface{}{4})
+	if exp := "(col1, id) > ($2, $3)"; predicate != exp {
+		t.Errorf("unexpected predicate: expected %s, actual %s", exp, predicate)
+	}
+	if exp := []interface{}{4, "b", 3}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	predicate, args = keysetPredicate(DialectSQLServer, keys, Cursor{"b", 3}, nil)
+	if exp := "(col1 > @p1 OR col1 = @p2 AND id > @p3)"; predicate != exp {
+		t.Errorf("unexpected predicate: expected %s, actual %s", exp, predicate)
+	}
+	if exp := []interface{}{"b", "b", 3}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+}
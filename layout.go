@@ -0,0 +1,42 @@
+package rowconv
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// layoutOptionPrefix prefixes the option carrying the layout of `db_layout` tag of the field
+const layoutOptionPrefix = "layout="
+
+// layoutOption returns the time layout of the field declared with `db_layout` tag, e.g. `db_layout:"02.01.2006"`
+func layoutOption(options []string) (string, bool) {
+	for _, opt := range options {
+		if strings.HasPrefix(opt, layoutOptionPrefix) {
+			return strings.TrimPrefix(opt, layoutOptionPrefix), true
+		}
+	}
+	return "", false
+}
+
+// layoutTimeConverter parses textual values of DATE/TEXT columns in the layout into the fields of time.Time type.
+// Values without the offset are parsed in loc, in UTC if it is nil. Values of time.Time type are stored as is.
+func layoutTimeConverter(layout string, loc *time.Location) converter {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(src interface{}, dst reflect.Value) error {
+		switch src.(type) {
+		case string, []byte:
+		default:
+			return convertDefault(src, dst)
+		}
+
+		t, err := time.ParseInLocation(layout, asString(src), loc)
+		if err != nil {
+			return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLayoutTag(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 time.Time  `db_layout:"02.01.2006"`
+		Col2 *time.Time `db_layout:"Jan 2, 2006 15:04"`
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '16.10.2026', 'Oct 16, 2026 10:30'), (2, '01.02.2003', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	col2 := time.Date(2026, time.October, 16, 10, 30, 0, 0, time.UTC)
+	exp := []valStruct{
+		{Id: 1, Col1: time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), Col2: &col2},
+		{Id: 2, Col1: time.Date(2003, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+	if problems := Validate(reflect.TypeOf(valStruct{})); len(problems) != 0 {
+		t.Errorf("no problems expected, actual: %v", problems)
+	}
+}
+
+func TestLayoutTimeConverter(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	convert := layoutTimeConverter("2006/01/02", loc)
+
+	var act time.Time
+	if err := convert([]byte("2026/10/16"), reflect.ValueOf(&act).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2026, time.October, 16, 0, 0, 0, 0, loc); !act.Equal(exp) || act.Location() != loc {
+		t.Errorf("unexpected time: expected %v, actual %v", exp, act)
+	}
+
+	var pe *ParseError
+	if err := convert("16.10.2026", reflect.ValueOf(&act).Elem()); !errors.As(err, &pe) {
+		t.Errorf("ParseError expected for the value in another layout, actual: %v", err)
+	}
+
+	type invalid struct {
+		Col1 string `db_layout:"2006-01-02"`
+	}
+	if problems := Validate(reflect.TypeOf(invalid{})); len(problems) != 1 {
+		t.Errorf("layout of non-time field expected to be reported, actual: %v", problems)
+	}
+}
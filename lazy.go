@@ -0,0 +1,131 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// errLazyConsumed is returned by Lazy once its rows are consumed
+var errLazyConsumed = errors.New("rows of the lazy result are already consumed")
+
+// Lazy is the result that holds the rows along with the mapper compiled for elements of type T, but defers scanning
+// until All, First, Each or Into is called, so the caller can choose how the rows are materialized after inspecting
+// the context, e.g. collect them into a slice for a small JSON response or stream them into a CSV writer.
+// The rows are consumed and closed by the first of these calls, further calls fail. Lazy is not safe for concurrent use.
+type Lazy[T any] struct {
+	rows        *sql.Rows
+	columns     []string
+	elementType reflect.Type
+	scanDef     scanDefinition
+	opts        *options
+	consumed    bool
+}
+
+// NewLazy compiles the mapper of rows into elements of type T, the same as accepted by Propagate, and returns the result
+// that scans rows on demand with the options applied. The rows are closed if the mapper can't be compiled.
+func NewLazy[T any](rows *sql.Rows, opts ...Option) (*Lazy[T], error) {
+	return NewLazyOn[T](Default(), rows, opts...)
+}
+
+// NewLazyOn is the same as NewLazy with the options of the mapper m
+func NewLazyOn[T any](m *Mapper, rows *sql.Rows, opts ...Option) (*Lazy[T], error) {
+	lazy := &Lazy[T]{rows: rows, opts: m.newOptions(append(append([]Option(nil), opts...), WithCloseRows(true)))}
+	if err := lazy.compile(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return lazy, nil
+}
+
+func (l *Lazy[T]) compile() error {
+	var err error
+	if l.columns, err = l.rows.Columns(); err != nil {
+		return err
+	}
+	columnTypes, err := rowsColumnTypes(l.rows)
+	if err != nil {
+		return err
+	}
+	if l.elementType, err = elementType(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return err
+	}
+	l.scanDef, err = scanDefinitionOf(l.elementType, columnTypes, l.opts.compile)
+	return err
+}
+
+// Columns returns the names of the columns of the rows, they are available before the rows are consumed
+func (l *Lazy[T]) Columns() []string {
+	return append([]string(nil), l.columns...)
+}
+
+// All scans all rows into a slice
+func (l *Lazy[T]) All() ([]T, error) {
+	var all []T
+	sink, err := NewSliceSink(&all)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Into(sink); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// First scans the first row, the rest of the rows are discarded. sql.ErrNoRows is returned if there are no rows.
+func (l *Lazy[T]) First() (T, error) {
+	var first T
+	sink := &firstRowSink{dst: reflect.ValueOf(&first).Elem()}
+	if err := l.Into(sink); err != nil && err != errFirstRowPropagated {
+		return first, err
+	}
+	if !sink.propagated {
+		return first, sql.ErrNoRows
+	}
+	return first, nil
+}
+
+// Each scans rows one by one and passes them to fn without collecting them, the iteration stops at the first error of fn
+func (l *Lazy[T]) Each(fn func(v T) error) error {
+	return l.Into(&funcSink[T]{fn: fn})
+}
+
+// Into scans rows one by one into the sink, e.g. the one created by NewWriterSink or NewEncoderSink
+func (l *Lazy[T]) Into(sink Sink) error {
+	if l.consumed {
+		return errLazyConsumed
+	}
+	l.consumed = true
+
+	return l.opts.closingRows(l.rows, func() (err error) {
+		defer l.opts.compile.state.recoverPropagation(l.elementType, l.rows, &err)
+
+		if sink, err = l.opts.wrapSink(sink, l.elementType); err != nil {
+			return err
+		}
+		if err := propagateRows(l.scanDef.scanner(l.opts.unmappedColumn), sink, l.rows, l.opts); err != nil {
+			return err
+		}
+		return sink.Flush()
+	})
+}
+
+// Close releases the rows without scanning them, it is a no-op once the rows are consumed
+func (l *Lazy[T]) Close() error {
+	if l.consumed {
+		return nil
+	}
+	l.consumed = true
+	return l.rows.Close()
+}
+
+// funcSink passes values to fn
+type funcSink[T any] struct {
+	fn func(v T) error
+}
+
+func (fs *funcSink[T]) Add(v reflect.Value) error {
+	return fs.fn(v.Interface().(T))
+}
+
+func (fs *funcSink[T]) Flush() error { return nil }
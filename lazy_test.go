@@ -0,0 +1,81 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	query := func() *Lazy[valStruct] {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+			"SELECT id, col1 FROM propagation ORDER BY id",
+		)
+		t.Cleanup(release)
+
+		lazy, err := NewLazy[valStruct](rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lazy
+	}
+
+	lazy := query()
+	if exp := []string{"id", "col1"}; !reflect.DeepEqual(lazy.Columns(), exp) {
+		t.Errorf("unexpected columns: expected %v, actual %v", exp, lazy.Columns())
+	}
+	all, err := lazy.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}}; !reflect.DeepEqual(all, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, all)
+	}
+	if _, err := lazy.All(); err == nil {
+		t.Error("error expected for consumed rows")
+	}
+
+	first, err := query().First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (valStruct{Id: 1, Col1: "a"}); first != exp {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, first)
+	}
+
+	stop := errors.New("stop")
+	var ids []int
+	err = query().Each(func(v valStruct) error {
+		ids = append(ids, v.Id)
+		return stop
+	})
+	if !errors.Is(err, stop) || !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("iteration expected to stop at the first row: %v, %v", ids, err)
+	}
+
+	if err := query().Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLazyNoRows(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation WHERE id > 1",
+	)
+	defer release()
+
+	lazy, err := NewLazy[int](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lazy.First(); err != sql.ErrNoRows {
+		t.Errorf("sql.ErrNoRows expected, actual: %v", err)
+	}
+}
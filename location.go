@@ -0,0 +1,58 @@
+package rowconv
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// zonelessTimeColumns are database types of timestamps and dates stored without time zone
+var zonelessTimeColumns = map[string]struct{}{
+	"DATETIME":      {},
+	"DATETIME2":     {},
+	"SMALLDATETIME": {},
+	"TIMESTAMP":     {},
+	"DATE":          {},
+}
+
+// WithTimeLocation interprets values of the timestamp and date columns without time zone, such as DATETIME of MySQL,
+// TIMESTAMP WITHOUT TIME ZONE of PostgreSQL and DATETIME2 of SQL Server, in loc instead of the location chosen by the driver.
+// Values of the columns with time zone, e.g. DATETIMEOFFSET of SQL Server, keep the offset they are returned with.
+// The wall clock of the value is kept, e.g. 10:00 UTC becomes 10:00 in loc, and textual values are parsed in loc.
+// Values of YEAR columns of MySQL stored into the fields of time.Time type become January 1 of the year in loc.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.compile.timeLocation = loc
+	}
+}
+
+func isZonelessTimeColumn(columnType columnType) bool {
+	_, zoneless := zonelessTimeColumns[databaseTypeName(columnType)]
+	return zoneless
+}
+
+// zonelessTimeLayouts are layouts of textual timestamps and dates returned by drivers
+var zonelessTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// inLocation moves the wall clock of the time value into loc, textual values are parsed in loc
+func inLocation(src interface{}, loc *time.Location) interface{} {
+	switch value := src.(type) {
+	case time.Time:
+		year, month, day := value.Date()
+		hour, min, sec := value.Clock()
+		return time.Date(year, month, day, hour, min, sec, value.Nanosecond(), loc)
+	case []byte, string:
+		text := asString(value)
+		for _, layout := range zonelessTimeLayouts {
+			if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+				return t
+			}
+		}
+	}
+	return src
+}
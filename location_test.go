@@ -0,0 +1,33 @@
+package rowconv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	exp := time.Date(2020, 1, 2, 10, 4, 5, 6, loc)
+
+	for _, src := range []interface{}{
+		time.Date(2020, 1, 2, 10, 4, 5, 6, time.UTC),
+		[]byte("2020-01-02 10:04:05.000000006"),
+		"2020-01-02T10:04:05.000000006",
+	} {
+		act, ok := inLocation(src, loc).(time.Time)
+		if !ok || !act.Equal(exp) || act.Location() != loc {
+			t.Errorf("unexpected time of %v: expected %v, actual %v", src, exp, act)
+		}
+	}
+
+	if act := inLocation("not a time", loc); act != "not a time" {
+		t.Errorf("unparsable value expected to be kept, actual: %v", act)
+	}
+}
+
+func TestWithTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	if opts := newOptions([]Option{WithTimeLocation(loc)}); opts.compile.timeLocation != loc {
+		t.Errorf("location expected to be configured, actual: %v", opts.compile.timeLocation)
+	}
+}
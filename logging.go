@@ -0,0 +1,77 @@
+package rowconv
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// Logger receives reports about notable events of propagation, *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type loggerHolder struct {
+	Logger
+}
+
+// SetLogger configures logger used by all propagations that have no logger of their own, nil disables logging
+func SetLogger(l Logger) {
+	Default().SetLogger(l)
+}
+
+// SetLogger is the same as SetLogger of the package for the mapper
+func (m *Mapper) SetLogger(l Logger) {
+	m.state.logger.Store(loggerHolder{Logger: l})
+}
+
+func (st *state) defaultLogger() Logger {
+	return st.logger.Load().(loggerHolder).Logger
+}
+
+// WithLogger configures logger of the propagation instead of the one configured with SetLogger
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// SlowRow describes a row which scan and conversion took longer than configured threshold
+type SlowRow struct {
+	// Number of the row starting from 1
+	Number int
+	// Type of the element the row was converted to
+	Type reflect.Type
+	// Columns of the row
+	Columns []string
+	// Elapsed is the time spent on scan and conversion of the row
+	Elapsed time.Duration
+}
+
+// WithSlowRowThreshold reports each row which scan and conversion took longer than threshold.
+// Reports are passed to the hook, if it is provided, or written to the logger otherwise.
+func WithSlowRowThreshold(threshold time.Duration, hook func(SlowRow)) Option {
+	return func(o *options) {
+		o.slowRowThreshold = threshold
+		o.slowRowHook = hook
+	}
+}
+
+func (o *options) reportSlowRow(slowRow SlowRow, rows *sql.Rows) {
+	slowRow.Columns, _ = rows.Columns()
+	if o.slowRowHook != nil {
+		o.slowRowHook(slowRow)
+		return
+	}
+	if l := o.log(); l != nil {
+		l.Printf("rowconv: row #%d of %v took %v exceeding threshold %v, columns: %v",
+			slowRow.Number, slowRow.Type, slowRow.Elapsed, o.slowRowThreshold, slowRow.Columns)
+	}
+}
+
+func (o *options) log() Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return o.compile.state.defaultLogger()
+}
@@ -0,0 +1,61 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagateWithSlowRowThreshold(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT id, col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	var slowRows []SlowRow
+	var valStructs []valStruct
+	// every row is slower than a nanosecond
+	err := Propagate(&valStructs, rows, WithSlowRowThreshold(time.Nanosecond, func(slowRow SlowRow) {
+		slowRows = append(slowRows, slowRow)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slowRows) != 2 {
+		t.Fatalf("unexpected amount of slow rows: %d", len(slowRows))
+	}
+	for i, slowRow := range slowRows {
+		if slowRow.Number != i+1 || slowRow.Type != reflect.TypeOf(valStruct{}) || len(slowRow.Columns) != 2 {
+			t.Errorf("unexpected slow row report: %+v", slowRow)
+		}
+	}
+}
+
+type recordingLogger []string
+
+func (rl *recordingLogger) Printf(format string, v ...interface{}) {
+	*rl = append(*rl, fmt.Sprintf(format, v...))
+}
+
+func TestPropagateWithSlowRowThresholdLogged(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	var logged recordingLogger
+	var ids []int
+	if err := Propagate(&ids, rows, WithLogger(&logged), WithSlowRowThreshold(time.Nanosecond, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 1 {
+		t.Errorf("unexpected log records: %v", logged)
+	}
+}
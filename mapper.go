@@ -0,0 +1,84 @@
+package rowconv
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Middleware transforms the element mapped from the row before it is put into the destination,
+// e.g. decrypts, trims or normalizes its fields. The returned value must be of the same type as v.
+type Middleware func(v reflect.Value) (reflect.Value, error)
+
+// WithMiddleware appends middlewares applied to each element in order, after the ones already configured
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+type middlewareSink struct {
+	Sink
+	middlewares []Middleware
+}
+
+func (ms *middlewareSink) Add(v reflect.Value) error {
+	elementType := v.Type()
+	for _, middleware := range ms.middlewares {
+		var err error
+		if v, err = middleware(v); err != nil {
+			return err
+		}
+		if !v.IsValid() || v.Type() != elementType {
+			return fmt.Errorf("middleware must return the value of type %v", elementType)
+		}
+	}
+	return ms.Sink.Add(v)
+}
+
+// Mapper propagates rows with the options it is created with, so the options such as middlewares are configured
+// once per Mapper instance instead of every call. Options passed to its methods are applied after its own.
+// Each Mapper has its own configuration, registrations and cache of compiled mappers, so libraries embedded into
+// the same binary don't affect each other with them. Functions of the package use the mapper returned by Default.
+// Mapper is safe for concurrent use.
+type Mapper struct {
+	opts  []Option
+	state *state
+}
+
+var defaultMapper = NewMapper()
+
+// Default returns the mapper used by the functions of the package, e.g. Propagate and RegisterEnum
+func Default() *Mapper {
+	return defaultMapper
+}
+
+// NewMapper creates Mapper with the options applied to each propagation. It has the default configuration and
+// no registrations: those made with the functions of the package apply only to the mapper returned by Default.
+func NewMapper(opts ...Option) *Mapper {
+	return &Mapper{opts: append([]Option(nil), opts...), state: newState()}
+}
+
+// Propagate is the same as Propagate of the package with the options of the mapper
+func (m *Mapper) Propagate(dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return propagate(dst, rows, m.newOptions(opts))
+}
+
+// PropagateAndClose is the same as PropagateAndClose of the package with the options of the mapper
+func (m *Mapper) PropagateAndClose(dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return propagate(dst, rows, m.newOptions(append(append([]Option(nil), opts...), WithCloseRows(true))))
+}
+
+// PropagateSink is the same as PropagateSink of the package with the options of the mapper
+func (m *Mapper) PropagateSink(sink Sink, elementType reflect.Type, rows *sql.Rows, opts ...Option) error {
+	o := m.newOptions(opts)
+	return o.closingRows(rows, func() (err error) {
+		defer o.compile.state.recoverPropagation(elementType, rows, &err)
+		return propagateSink(sink, elementType, rows, o)
+	})
+}
+
+// newOptions returns the options of the propagation with the mapper: its own options followed by opts
+func (m *Mapper) newOptions(opts []Option) *options {
+	return newStateOptions(m.state, append(m.opts[:len(m.opts):len(m.opts)], opts...))
+}
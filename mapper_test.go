@@ -0,0 +1,155 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMapperMiddlewares(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	upper := func(v reflect.Value) (reflect.Value, error) {
+		s := v.Interface().(*valStruct)
+		s.Col1 = strings.ToUpper(s.Col1)
+		return v, nil
+	}
+	suffix := func(v reflect.Value) (reflect.Value, error) {
+		s := v.Interface().(*valStruct)
+		s.Col1 += "!"
+		return v, nil
+	}
+	mapper := NewMapper(WithMiddleware(upper))
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT id, col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var results []*valStruct
+	if err := mapper.Propagate(&results, rows, WithMiddleware(suffix)); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*valStruct{{Id: 1, Col1: "A!"}, {Id: 2, Col1: "B!"}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
+
+func TestMapperMiddlewareError(t *testing.T) {
+	rejected := errors.New("rejected")
+	mapper := NewMapper(WithMiddleware(func(v reflect.Value) (reflect.Value, error) {
+		return v, rejected
+	}))
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	var ids []int
+	if err := mapper.Propagate(&ids, rows); !errors.Is(err, rejected) {
+		t.Errorf("error of the middleware expected, actual: %v", err)
+	}
+}
+
+func TestMapperIsolation(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterScanner("upper", func(src interface{}) (interface{}, error) {
+		return strings.ToUpper(asString(src)), nil
+	})
+	mapper.StrictColumnAmountCheck(true)
+
+	type valStruct struct {
+		Id   int
+		Col1 string `db_scanner:"upper"`
+	}
+	query := func() (*sql.Rows, func()) {
+		return queryPropagation(t,
+			"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b')",
+			"SELECT id, col1, col2 FROM propagation",
+		)
+	}
+
+	rows, release := query()
+	var valStructs []valStruct
+	err := mapper.Propagate(&valStructs, rows)
+	release()
+	if err == nil {
+		t.Error("error of the strict column amount check of the mapper expected")
+	}
+
+	rows, release = query()
+	err = Propagate(&valStructs, rows)
+	release()
+	if err == nil || !strings.Contains(err.Error(), "upper") {
+		t.Errorf("scanner registered on the mapper expected to be unknown to Default, actual: %v", err)
+	}
+	if problems := mapper.Validate(reflect.TypeOf(valStruct{})); len(problems) != 0 {
+		t.Errorf("no problems expected for the mapper, actual: %v", problems)
+	}
+}
+
+func TestMapperIsolationHelpers(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Name string
+	}
+	mapper := NewMapper()
+	if err := RegisterMappingOn[valStruct](mapper, map[string]string{"Name": "col1,trim"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// parameters are bound by the mapping registered on the mapper
+	arg := valStruct{Id: 1, Name: "a"}
+	if _, args, err := mapper.BindNamed(BindQuestion, "SELECT :id, :col1", arg); err != nil || !reflect.DeepEqual(args, []interface{}{1, "a"}) {
+		t.Errorf("parameters expected to be bound by the mapping of the mapper, actual: %v %v", args, err)
+	}
+	if _, _, err := BindNamed(BindQuestion, "SELECT :id, :col1", arg); err == nil {
+		t.Error("mapping registered on the mapper expected to be unknown to Default")
+	}
+
+	propagateSets := func(propagateSets func(rows *sql.Rows, dsts ...interface{}) error) []valStruct {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a  ')",
+			"SELECT id, col1 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		if err := propagateSets(rows, &valStructs); err != nil {
+			t.Fatal(err)
+		}
+		return valStructs
+	}
+	if act := propagateSets(mapper.PropagateSets); !reflect.DeepEqual(act, []valStruct{{Id: 1, Name: "a"}}) {
+		t.Errorf("value expected to be trimmed by the mapping of the mapper, actual: %+v", act)
+	}
+	if act := propagateSets(PropagateSets); !reflect.DeepEqual(act, []valStruct{{Id: 1}}) {
+		t.Errorf("column expected not to be mapped to the field by Default, actual: %+v", act)
+	}
+
+	// options of the fields of Mapping are taken with the mapper of the propagation
+	mapping, err := For[valStruct]().Column("col2").Field("Name").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b  ')",
+		"SELECT id, col2 FROM propagation",
+	)
+	defer release()
+	var valStructs []valStruct
+	if err := mapper.Propagate(&valStructs, rows, WithMapping(mapping)); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Name: "b"}}; !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
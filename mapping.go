@@ -0,0 +1,59 @@
+package rowconv
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ColumnMapping describes how the column/alias is mapped to the field of the struct
+type ColumnMapping struct {
+	// Column is the name of the column/alias the field is mapped to
+	Column string
+	// FieldPath is a sequence of names of the fields from the root struct to the mapped field
+	FieldPath []string
+	// FieldIndex is a sequence of indexes suitable for reflect.Value.FieldByIndex
+	FieldIndex []int
+	// Type is a type of the field
+	Type reflect.Type
+	// Tag is a raw value of the `db_column` tag of the field, empty if there is no tag
+	Tag string
+	// Options are options of the tag, such as `key` or `trim`
+	Options []string
+}
+
+// Mappings returns mappings of the struct t (or reference to it) to the columns, the same ones used by Propagate.
+// Mappings are ordered by declaration of the fields, fields of nested structs follow the field of the struct.
+func Mappings(t reflect.Type) ([]ColumnMapping, error) {
+	return Default().Mappings(t)
+}
+
+// Mappings is the same as Mappings of the package for the mapper
+func (m *Mapper) Mappings(t reflect.Type) ([]ColumnMapping, error) {
+	structType, _, err := unwrapPtrStructType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	columnAliasToAccessor, err := m.state.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	// accessors are cached, so the caller receives copies of their slices
+	mappings := make([]ColumnMapping, 0, len(columnAliasToAccessor))
+	for _, accessor := range columnAliasToAccessor {
+		_, field, _ := structFieldByIndex(structType, accessor.fieldIndex)
+		mappings = append(mappings, ColumnMapping{
+			Column:     accessor.columnAlias,
+			FieldPath:  append(accessor.fieldPath[:0:0], accessor.fieldPath...),
+			FieldIndex: append(accessor.fieldIndex[:0:0], accessor.fieldIndex...),
+			Type:       accessor.fieldType,
+			Tag:        field.Tag.Get(dbColumn),
+			Options:    append(accessor.options[:0:0], accessor.options...),
+		})
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return lessIndex(mappings[i].FieldIndex, mappings[j].FieldIndex)
+	})
+	return mappings, nil
+}
@@ -0,0 +1,38 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMappings(t *testing.T) {
+	type columns struct {
+		Col1 string `db_column:"name,trim"`
+	}
+	type refStruct struct {
+		PK      int `db_column:"id,key"`
+		Created *time.Time
+		Nested  *columns
+	}
+
+	mappings, err := Mappings(reflect.TypeOf(&refStruct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []ColumnMapping{
+		{Column: "id", FieldPath: []string{"PK"}, FieldIndex: []int{0}, Type: reflect.TypeOf(0), Tag: "id,key", Options: []string{"key"}},
+		{Column: "created", FieldPath: []string{"Created"}, FieldIndex: []int{1}, Type: reflect.TypeOf(&time.Time{}), Options: []string{}},
+		{Column: "nested", FieldPath: []string{"Nested"}, FieldIndex: []int{2}, Type: reflect.TypeOf(&columns{}), Options: []string{}},
+		{Column: "name", FieldPath: []string{"Nested", "Col1"}, FieldIndex: []int{2, 0}, Type: reflect.TypeOf(""), Tag: "name,trim", Options: []string{"trim"}},
+	}
+	if !reflect.DeepEqual(mappings, exp) {
+		t.Errorf("unexpected mappings: expected %+v, actual %+v", exp, mappings)
+	}
+}
+
+func TestMappingsNotStruct(t *testing.T) {
+	if _, err := Mappings(reflect.TypeOf(0)); err == nil {
+		t.Error("error expected for non-struct type")
+	}
+}
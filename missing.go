@@ -0,0 +1,49 @@
+package rowconv
+
+import "reflect"
+
+// missingFieldPolicy makes the function of WithMissingField comparable, so it can be a part of the cache key
+type missingFieldPolicy struct {
+	missing func(field string) error
+}
+
+// WithMissingField calls missing during the compilation of the mapper for each exported field of the struct element
+// that no column is mapped to, field is the path of the field, e.g. "Address.City". The error of missing fails
+// the compilation, so the policy of the team can be enforced per mapper, e.g. a warning in development and
+// an error in CI, instead of StrictColumnAmountCheck. Mappers compiled with the option aren't kept in the cache
+// shared by propagations, so missing is called for each propagation.
+func WithMissingField(missing func(field string) error) Option {
+	policy := &missingFieldPolicy{missing: missing}
+	return func(o *options) {
+		o.compile.missingField = policy
+	}
+}
+
+// checkMissingFields reports the fields of the struct element type without columns to the policy of WithMissingField
+func (copts compileOptions) checkMissingFields(elementType reflect.Type, columnTypes []columnType) error {
+	if copts.missingField == nil {
+		return nil
+	}
+	structType, _, err := unwrapPtrStructType(elementType)
+	if err != nil {
+		return nil
+	}
+	p, err := copts.explain(elementType, columnTypes)
+	if err != nil || p.Strategy != "struct" {
+		return err
+	}
+
+	fields, err := coverableFields(copts.state, structType)
+	if err != nil {
+		return err
+	}
+	populated := p.populated()
+	for _, field := range fields {
+		if _, found := populated[field]; !found {
+			if err := copts.missingField.missing(field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
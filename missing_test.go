@@ -0,0 +1,73 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPropagateWithMissingField(t *testing.T) {
+	type valStruct struct {
+		Id    int
+		Col1  string
+		Col3  *string
+		Stale bool
+	}
+	propagate := func(opts ...Option) error {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+			"SELECT id, col1 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		return Propagate(&valStructs, rows, opts...)
+	}
+
+	var missing []string
+	warn := WithMissingField(func(field string) error {
+		missing = append(missing, field)
+		return nil
+	})
+	if err := propagate(warn); err != nil {
+		t.Fatal(err)
+	}
+	if err := propagate(warn); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"Col3", "Stale", "Col3", "Stale"}; !reflect.DeepEqual(missing, exp) {
+		t.Errorf("missing fields expected to be reported for each propagation: expected %v, actual %v", exp, missing)
+	}
+
+	rejected := errors.New("rejected")
+	if err := propagate(WithMissingField(func(string) error { return rejected })); !errors.Is(err, rejected) {
+		t.Errorf("error of the policy expected, actual: %v", err)
+	}
+}
+
+func TestWithMissingFieldNotCached(t *testing.T) {
+	type valStruct struct {
+		Id    int
+		Stale bool
+	}
+	mapper := NewMapper()
+	for i := 0; i < 3; i++ {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+			"SELECT id FROM propagation",
+		)
+
+		var valStructs []valStruct
+		err := mapper.Propagate(&valStructs, rows, WithMissingField(func(string) error { return nil }))
+		release()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapper.state.scanDefinitions.RLock()
+	defer mapper.state.scanDefinitions.RUnlock()
+	if amount := len(mapper.state.scanDefinitions.byKey); amount != 0 {
+		t.Errorf("no cached definitions expected for the inline policies, actual: %d", amount)
+	}
+}
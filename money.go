@@ -0,0 +1,139 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MoneyFormat defines the separators of currency-formatted values stored into the fields tagged with `money` option:
+// `db_column:"price,money"`. Currency symbols and codes of the values are ignored, negative values are prefixed with
+// the minus sign or enclosed in parentheses. Fields of integer types receive the amount in cents, so "$1,234.50"
+// is stored as 123450, other fields, including decimal types implementing sql.Scanner, receive the decimal text "1234.50".
+type MoneyFormat struct {
+	// DecimalSeparator separates the fractional part, e.g. '.' of "$1,234.50" or ',' of "1.234,50 €"
+	DecimalSeparator rune
+	// GroupSeparator separates the groups of the digits of the integer part, 0 if the values have no group separator
+	GroupSeparator rune
+}
+
+// postgresMoneyFormat is the format of money output of PostgreSQL with en_US lc_monetary: "$1,234.50", "-$1,234.50"
+var postgresMoneyFormat = MoneyFormat{DecimalSeparator: '.', GroupSeparator: ','}
+
+// DefaultMoneyFormat configures the format of the values stored into the fields tagged with `money` option
+// used by the propagations without WithMoneyFormat. It is the format of money output of PostgreSQL with en_US
+// lc_monetary, "$1,234.50", unless configured otherwise; the zero format restores it.
+func DefaultMoneyFormat(format MoneyFormat) {
+	Default().DefaultMoneyFormat(format)
+}
+
+// DefaultMoneyFormat is the same as DefaultMoneyFormat of the package for the mapper
+func (m *Mapper) DefaultMoneyFormat(format MoneyFormat) {
+	if format == (MoneyFormat{}) {
+		format = postgresMoneyFormat
+	}
+	m.state.moneyFormat.Store(format)
+}
+
+func (st *state) defaultMoneyFormat() MoneyFormat {
+	return st.moneyFormat.Load().(MoneyFormat)
+}
+
+// WithMoneyFormat configures the separators of the values stored into the fields tagged with `money` option,
+// the format configured with DefaultMoneyFormat is used if it is not provided
+func WithMoneyFormat(format MoneyFormat) Option {
+	return func(o *options) {
+		o.compile.moneyFormat = format
+	}
+}
+
+// moneyConverter parses currency-formatted values of the format into the fields of valueType, see MoneyFormat
+func moneyConverter(valueType reflect.Type, format MoneyFormat) converter {
+	if format == (MoneyFormat{}) {
+		format = postgresMoneyFormat
+	}
+	cents := isIntegerKind(valueType.Kind())
+
+	return func(src interface{}, dst reflect.Value) error {
+		if src == nil {
+			return convertDefault(src, dst)
+		}
+
+		amount, err := parseMoney(asString(src), format)
+		if err == nil && cents {
+			var centsAmount int64
+			if centsAmount, err = moneyCents(amount); err == nil {
+				return convertDefault(centsAmount, dst)
+			}
+		}
+		if err != nil {
+			return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+		}
+		return convertDefault(amount, dst)
+	}
+}
+
+// parseMoney returns the amount of currency-formatted text as decimal text with '.' separator, e.g. "-1234.50"
+func parseMoney(text string, format MoneyFormat) (string, error) {
+	text = strings.TrimSpace(text)
+	negative := strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")")
+	if negative {
+		text = text[1 : len(text)-1]
+	}
+
+	var amount strings.Builder
+	var digits, fraction, fractionGroup bool
+	for _, r := range text {
+		switch {
+		case '0' <= r && r <= '9':
+			if fractionGroup {
+				return "", errors.New("group separator in the fractional part")
+			}
+			amount.WriteRune(r)
+			digits = true
+		case r == format.DecimalSeparator:
+			if fraction {
+				return "", errors.New("more than one decimal separator")
+			}
+			amount.WriteByte('.')
+			fraction = true
+		case r == format.GroupSeparator && format.GroupSeparator != 0:
+			// the separator may be followed by the currency, e.g. space of "1 234,50 zł"
+			fractionGroup = fraction
+		case r == '-':
+			if digits || negative {
+				return "", errors.New("misplaced minus sign")
+			}
+			negative = true
+		case unicode.IsSpace(r) || unicode.IsLetter(r) || unicode.Is(unicode.Sc, r):
+			// currency symbols and codes
+		default:
+			return "", fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	if !digits {
+		return "", errors.New("no digits")
+	}
+
+	if negative {
+		return "-" + amount.String(), nil
+	}
+	return amount.String(), nil
+}
+
+// moneyCents converts the decimal amount into cents, fractions of the cent are reported as the error
+func moneyCents(amount string) (int64, error) {
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if trimmed := strings.TrimRight(fraction, "0"); len(trimmed) > 2 {
+		return 0, errors.New("fractions of the cent can't be stored")
+	}
+	fraction = (fraction + "00")[:2]
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, ErrOverflow
+	}
+	return cents, nil
+}
@@ -0,0 +1,96 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMoneyConverter(t *testing.T) {
+	european := MoneyFormat{DecimalSeparator: ',', GroupSeparator: '.'}
+	for _, tc := range []struct {
+		src    string
+		format MoneyFormat
+		cents  int64
+		amount string
+	}{
+		{src: "$1,234.50", cents: 123450, amount: "1234.50"},
+		{src: "-$1,234.50", cents: -123450, amount: "-1234.50"},
+		{src: "($0.07)", cents: -7, amount: "-0.07"},
+		{src: "USD 12", cents: 1200, amount: "12"},
+		{src: "1.234,5 €", format: european, cents: 123450, amount: "1234.5"},
+		{src: "1 234,50 zł", format: MoneyFormat{DecimalSeparator: ',', GroupSeparator: ' '}, cents: 123450, amount: "1234.50"},
+		{src: "$1.500", cents: 150, amount: "1.500"},
+	} {
+		var cents int64
+		if err := moneyConverter(reflect.TypeOf(cents), tc.format)(tc.src, reflect.ValueOf(&cents).Elem()); err != nil || cents != tc.cents {
+			t.Errorf("unexpected cents of %q: expected %d, actual %d, error: %v", tc.src, tc.cents, cents, err)
+		}
+		var amount string
+		if err := moneyConverter(reflect.TypeOf(amount), tc.format)(tc.src, reflect.ValueOf(&amount).Elem()); err != nil || amount != tc.amount {
+			t.Errorf("unexpected amount of %q: expected %s, actual %s, error: %v", tc.src, tc.amount, amount, err)
+		}
+	}
+
+	for _, src := range []string{"$1.5,0", "$1.005", "$1.2.3", "$", "1-2", "#12", "$92233720368547758.08"} {
+		var cents int64
+		var parseErr *ParseError
+		if err := moneyConverter(reflect.TypeOf(cents), MoneyFormat{})(src, reflect.ValueOf(&cents).Elem()); !errors.As(err, &parseErr) {
+			t.Errorf("ParseError expected for %q, actual: %v", src, err)
+		}
+	}
+}
+
+func TestMoneyOption(t *testing.T) {
+	type valStruct struct {
+		Id     int
+		Cents  int64    `db_column:"col1,money"`
+		Amount *float64 `db_column:"col2,money"`
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '$1,234.50', '-$0.25'), (2, '$3', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	amount := -0.25
+	exp := []valStruct{{Id: 1, Cents: 123450, Amount: &amount}, {Id: 2, Cents: 300}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestMapperDefaultMoneyFormat(t *testing.T) {
+	type valStruct struct {
+		Id    int
+		Cents int64 `db_column:"col1,money"`
+	}
+	mapper := NewMapper()
+	mapper.DefaultMoneyFormat(MoneyFormat{DecimalSeparator: ',', GroupSeparator: '.'})
+
+	propagate := func(m *Mapper, value string) ([]valStruct, error) {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, '"+value+"')",
+			"SELECT id, col1 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		err := m.Propagate(&valStructs, rows)
+		return valStructs, err
+	}
+
+	exp := []valStruct{{Id: 1, Cents: 123450}}
+	if act, err := propagate(mapper, "1.234,50 €"); err != nil || !reflect.DeepEqual(act, exp) {
+		t.Errorf("format of the mapper expected to be used: expected %+v, actual %+v %v", exp, act, err)
+	}
+	// the format of the mapper doesn't affect Default
+	if act, err := propagate(Default(), "$1,234.50"); err != nil || !reflect.DeepEqual(act, exp) {
+		t.Errorf("format of Default expected to be used: expected %+v, actual %+v %v", exp, act, err)
+	}
+}
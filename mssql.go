@@ -0,0 +1,38 @@
+package rowconv
+
+// uniqueIdentifier is the database type name of GUID columns of SQL Server
+const uniqueIdentifier = "UNIQUEIDENTIFIER"
+
+func isUniqueIdentifierColumn(columnType columnType) bool {
+	return databaseTypeName(columnType) == uniqueIdentifier
+}
+
+// canonicalUniqueIdentifier formats 16-byte value of UNIQUEIDENTIFIER column of SQL Server in canonical UUID form.
+// SQL Server keeps the first three groups of the GUID in little-endian byte order, so they are reversed before formatting.
+// Values of other shapes are returned as is.
+func canonicalUniqueIdentifier(src interface{}) (interface{}, error) {
+	if raw, ok := src.([]byte); ok && len(raw) == 16 {
+		return formatUUID(swapUniqueIdentifierBytes(raw)), nil
+	}
+	return src, nil
+}
+
+// UUIDUniqueIdentifier converts UUID in canonical form into 16 bytes in the order of UNIQUEIDENTIFIER of SQL Server,
+// so it can be passed as an argument of the query to the column of UNIQUEIDENTIFIER or BINARY(16) type.
+func UUIDUniqueIdentifier(uuid string) ([]byte, error) {
+	raw, err := UUIDBinary(uuid)
+	if err != nil {
+		return nil, err
+	}
+	return swapUniqueIdentifierBytes(raw), nil
+}
+
+// swapUniqueIdentifierBytes converts between RFC 4122 and SQL Server byte orders of the GUID, the conversion is symmetric
+func swapUniqueIdentifierBytes(raw []byte) []byte {
+	return []byte{
+		raw[3], raw[2], raw[1], raw[0],
+		raw[5], raw[4],
+		raw[7], raw[6],
+		raw[8], raw[9], raw[10], raw[11], raw[12], raw[13], raw[14], raw[15],
+	}
+}
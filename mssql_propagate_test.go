@@ -0,0 +1,33 @@
+// +build mssql
+
+package rowconv
+
+import (
+	"net/url"
+
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	mssql = "sqlserver"
+	// mssqlPassword satisfies the password policy of SQL Server, the container has no other users than sa
+	mssqlPassword = "Passw0rd!"
+)
+
+func driverName() string {
+	return mssql
+}
+
+func dataSourceURL() string {
+	return mssql + "://sa:" + url.QueryEscape(mssqlPassword) + "@127.0.0.1:" + port
+}
+
+func ddlCreateTestTempTable() string {
+	return `
+IF OBJECT_ID('propagation') IS NULL CREATE TABLE propagation(
+	id DECIMAL(6,0) PRIMARY KEY,
+	col1 NVARCHAR(20) NOT NULL,
+	col2 NVARCHAR(10),
+	col3 DATETIME2
+)`
+}
@@ -0,0 +1,50 @@
+package rowconv
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestConvertUniqueIdentifier(t *testing.T) {
+	const canonical = "00112233-4455-6677-8899-aabbccddeeff"
+	raw := []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	column := Column{Name: "id", DatabaseTypeName: "UNIQUEIDENTIFIER"}.known()
+
+	copts := compileOptions{state: Default().state}
+	for _, fieldOptions := range [][]string{nil, {"uuid"}} {
+		convert := copts.columnConverter(column, reflect.TypeOf(StringRef("")), fieldOptions)
+		for _, src := range []interface{}{raw, canonical} {
+			var act *string
+			if err := convert(src, reflect.ValueOf(&act).Elem()); err != nil {
+				t.Fatal(err)
+			}
+			if act == nil || *act != canonical {
+				t.Errorf("unexpected UUID of %v with options %v: expected %s, actual %v", src, fieldOptions, canonical, act)
+			}
+		}
+
+		var act *string
+		if err := convert(nil, reflect.ValueOf(&act).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if act != nil {
+			t.Errorf("nil expected for NULL, actual %v", *act)
+		}
+	}
+
+	if convert := copts.columnConverter(column, reflect.TypeOf([]byte(nil)), nil); convert != nil {
+		t.Error("bytes of UNIQUEIDENTIFIER are expected to be stored as is")
+	}
+
+	back, err := UUIDUniqueIdentifier(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back, raw) {
+		t.Errorf("unexpected UNIQUEIDENTIFIER bytes: expected %x, actual %x", raw, back)
+	}
+	if _, err := UUIDUniqueIdentifier("00112233445566778899aabbccddeeff"); err == nil {
+		t.Error("error expected for UUID not in canonical form")
+	}
+}
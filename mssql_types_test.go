@@ -0,0 +1,162 @@
+// +build mssql
+
+package rowconv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMSSQLUniqueIdentifier(t *testing.T) {
+	const canonical = "00112233-4455-6677-8899-aabbccddeeff"
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, N'a'), (2, N'b')",
+		"SELECT id, CAST(IIF(id = 1, '"+canonical+"', NULL) AS UNIQUEIDENTIFIER) AS guid FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Guid *string
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Guid: StringRef(canonical)}, {Id: 2}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestMSSQLUniqueIdentifierArgument(t *testing.T) {
+	const canonical = "00112233-4455-6677-8899-aabbccddeeff"
+	raw, err := UUIDUniqueIdentifier(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT CAST(CAST(@p1 AS BINARY(16)) AS UNIQUEIDENTIFIER) AS guid", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var guids []string
+	if err := PropagateAndClose(&guids, rows); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{canonical}; !reflect.DeepEqual(guids, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, guids)
+	}
+}
+
+func TestMSSQLDateTimeOffset(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col3) VALUES (1, N'a', '2020-01-02 03:04:05.1234567')",
+		"SELECT id, col3 AS local, CAST('2020-01-02 03:04:05.1234567 +02:00' AS DATETIMEOFFSET) AS zoned FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id    int
+		Local time.Time
+		Zoned time.Time
+	}
+	var valStructs []valStruct
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	if err := Propagate(&valStructs, rows, WithTimeLocation(loc)); err != nil {
+		t.Fatal(err)
+	}
+	if len(valStructs) != 1 {
+		t.Fatalf("unexpeted results of propagation: %+v", valStructs)
+	}
+
+	// DATETIME2 has no time zone, so it is moved into the location, DATETIMEOFFSET keeps its offset
+	act := valStructs[0]
+	if exp := time.Date(2020, 1, 2, 3, 4, 5, 123456700, loc); !act.Local.Equal(exp) || act.Local.Location() != loc {
+		t.Errorf("unexpected DATETIME2 value: expected %v, actual %v", exp, act.Local)
+	}
+	if _, offset := act.Zoned.Zone(); offset != 2*60*60 {
+		t.Errorf("unexpected offset of DATETIMEOFFSET value: %v", act.Zoned)
+	}
+	if exp := time.Date(2020, 1, 2, 1, 4, 5, 123456700, time.UTC); !act.Zoned.Equal(exp) {
+		t.Errorf("unexpected DATETIMEOFFSET value: expected %v, actual %v", exp, act.Zoned)
+	}
+}
+
+func TestMSSQLNVarChar(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, N'привет', N''), (2, N'42', N'b')",
+		"SELECT id, col1, CAST(col1 AS NCHAR(8)) AS padded, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id     int
+		Col1   []byte
+		Padded string  `db_column:"padded,trim"`
+		Col2   *string `db_column:"col2,emptynull"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: 1, Col1: []byte("привет"), Padded: "привет"},
+		{Id: 2, Col1: []byte("42"), Padded: "42", Col2: StringRef("b")},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestMSSQLNVarCharNumbers(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, N'42')",
+		"SELECT col1 FROM propagation",
+	)
+	defer release()
+
+	var numbers []int
+	if err := Propagate(&numbers, rows, WithLenientNumbers()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int{42}; !reflect.DeepEqual(numbers, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, numbers)
+	}
+}
+
+func TestMSSQLDatabaseTypeNames(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, N'a')",
+		`SELECT id, col1, col3, CAST(col3 AS SMALLDATETIME) AS small,
+			CAST(col3 AS DATETIMEOFFSET) AS zoned, NEWID() AS guid
+		FROM propagation`,
+	)
+	defer release()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act []string
+	for _, columnType := range columnTypes {
+		act = append(act, columnType.DatabaseTypeName())
+	}
+	exp := []string{"DECIMAL", "NVARCHAR", "DATETIME2", "SMALLDATETIME", "DATETIMEOFFSET", "UNIQUEIDENTIFIER"}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected database type names: expected %v, actual %v", exp, act)
+	}
+	for _, columnType := range columnTypes[2:4] {
+		if !isZonelessTimeColumn(columnType) {
+			t.Errorf("%s is expected to be zoneless", columnType.DatabaseTypeName())
+		}
+	}
+	if isZonelessTimeColumn(columnTypes[4]) {
+		t.Error("DATETIMEOFFSET is expected to have time zone")
+	}
+	if fieldType := generatedFieldType(columnTypes[5]); strings.TrimPrefix(fieldType, "*") != "string" {
+		t.Errorf("unexpected generated type of UNIQUEIDENTIFIER: %s", fieldType)
+	}
+}
@@ -0,0 +1,61 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// yearType is the database type name of YEAR columns of MySQL
+const yearType = "YEAR"
+
+func isYearColumn(columnType columnType) bool {
+	return databaseTypeName(columnType) == yearType
+}
+
+// bitType is the database type name of BIT columns of MySQL
+const bitType = "BIT"
+
+func isBitColumn(columnType columnType) bool {
+	return databaseTypeName(columnType) == bitType
+}
+
+// yearConverter stores the value of YEAR column of MySQL, returned as int64 or as text depending on the protocol,
+// into the field of time.Time type as January 1 of the year in loc, in UTC if it is nil.
+// The zero year "0000" is stored as zero time.Time.
+func yearConverter(loc *time.Location) converter {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(src interface{}, dst reflect.Value) error {
+		var year int64
+		switch value := src.(type) {
+		case nil:
+			return convertDefault(src, dst)
+		case int64:
+			year = value
+		case []byte, string:
+			var err error
+			if year, err = strconv.ParseInt(strings.TrimSpace(asString(value)), 10, 32); err != nil {
+				return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+			}
+		case time.Time:
+			dst.Set(reflect.ValueOf(value))
+			return nil
+		default:
+			return convertDefault(src, dst)
+		}
+
+		switch {
+		case year == 0:
+			dst.Set(reflect.ValueOf(time.Time{}))
+		case year < 0 || year > 9999:
+			return &ParseError{Value: asString(src), Type: dst.Type(), Err: errors.New("year is out of range")}
+		default:
+			dst.Set(reflect.ValueOf(time.Date(int(year), time.January, 1, 0, 0, 0, 0, loc)))
+		}
+		return nil
+	}
+}
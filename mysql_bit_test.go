@@ -0,0 +1,46 @@
+// +build mysql
+
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagateBit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "CREATE TEMPORARY TABLE flags(id INT PRIMARY KEY, flag BIT(1))"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO flags(id, flag) VALUES (1, b'0'), (2, b'1')"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT id, flag FROM flags ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type valStruct struct {
+		Id   int
+		Flag bool
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Flag: false}, {Id: 2, Flag: true}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
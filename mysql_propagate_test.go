@@ -15,7 +15,7 @@ func driverName() string {
 }
 
 func dataSourceURL() string {
-	return user + ":" + password + "@tcp(127.0.0.1:" + port + ")/" + schema + "?parseTime=true"
+	return user + ":" + password + "@tcp(127.0.0.1:" + port + ")/" + schema + "?parseTime=true&multiStatements=true"
 }
 
 func ddlCreateTestTempTable() string {
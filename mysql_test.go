@@ -0,0 +1,42 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConvertYear(t *testing.T) {
+	column := Column{Name: "founded", DatabaseTypeName: "YEAR"}.known()
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	exp := time.Date(2024, time.January, 1, 0, 0, 0, 0, loc)
+
+	convert := compileOptions{timeLocation: loc, state: Default().state}.columnConverter(column, reflect.TypeOf(&time.Time{}), nil)
+	for _, src := range []interface{}{int64(2024), []byte("2024"), "2024"} {
+		var act *time.Time
+		if err := convert(src, reflect.ValueOf(&act).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if act == nil || !act.Equal(exp) || act.Location() != loc {
+			t.Errorf("unexpected time of year %v: expected %v, actual %v", src, exp, act)
+		}
+	}
+
+	var act *time.Time
+	if err := convert(nil, reflect.ValueOf(&act).Elem()); err != nil || act != nil {
+		t.Errorf("nil expected for NULL, actual: %v, error: %v", act, err)
+	}
+	if err := convert([]byte("0000"), reflect.ValueOf(&act).Elem()); err != nil || act == nil || !act.IsZero() {
+		t.Errorf("zero time expected for zero year, actual: %v, error: %v", act, err)
+	}
+	var parseErr *ParseError
+	if err := convert([]byte("20x4"), reflect.ValueOf(&act).Elem()); !errors.As(err, &parseErr) {
+		t.Errorf("ParseError expected for malformed year, actual: %v", err)
+	}
+
+	copts := compileOptions{state: Default().state}
+	if convert := copts.columnConverter(column, reflect.TypeOf(int16(0)), nil); convert != nil {
+		t.Error("years are expected to be stored into integer fields by database/sql")
+	}
+}
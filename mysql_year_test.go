@@ -0,0 +1,32 @@
+// +build mysql
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagateYear(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		// CAST AS YEAR requires MySQL 8.0.22 or later
+		"SELECT id, CAST(2023 + id AS YEAR) AS founded, CAST(2023 + id AS YEAR) AS founded_at FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id        int
+		Founded   int16
+		FoundedAt time.Time `db_column:"founded_at"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Founded: 2024, FoundedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
@@ -0,0 +1,171 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Execer executes queries that don't return rows; it is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// BindStyle is the style of positional placeholders the named parameters are rewritten to
+type BindStyle int
+
+const (
+	// BindQuestion is `?` used by MySQL and SQLite
+	BindQuestion BindStyle = iota
+	// BindDollar is `$1` used by PostgreSQL
+	BindDollar
+	// BindAt is `@p1` used by SQL Server
+	BindAt
+)
+
+func (bs BindStyle) placeholder(n int) string {
+	switch bs {
+	case BindDollar:
+		return "$" + strconv.Itoa(n)
+	case BindAt:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// BindNamed rewrites `:name` parameters of the query into positional placeholders of the style and returns the query
+// with the values of the parameters in order. Values are taken from arg: a struct (or reference to it) by the
+// column/alias its fields are mapped to, the same as for Propagate, or a map with string keys by the key.
+// Parameter names are case-insensitive for structs. Quoted literals and `::` casts of PostgreSQL are left as they are.
+func BindNamed(style BindStyle, query string, arg interface{}) (string, []interface{}, error) {
+	return Default().BindNamed(style, query, arg)
+}
+
+// BindNamed is the same as BindNamed of the package, the fields of the struct are mapped with the mapping of the mapper
+func (m *Mapper) BindNamed(style BindStyle, query string, arg interface{}) (string, []interface{}, error) {
+	value, err := m.state.namedValueLookup(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var bound strings.Builder
+	var args []interface{}
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			bound.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			v, err := value(name)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, v)
+			bound.WriteString(style.placeholder(len(args)))
+			i = end - 1
+			continue
+		}
+		bound.WriteByte(c)
+	}
+	return bound.String(), args, nil
+}
+
+// NamedQuery executes query with `:name` parameters bound from arg using q, see BindNamed
+func NamedQuery(ctx context.Context, q Queryer, style BindStyle, query string, arg interface{}) (*sql.Rows, error) {
+	return Default().NamedQuery(ctx, q, style, query, arg)
+}
+
+// NamedQuery is the same as NamedQuery of the package for the mapper
+func (m *Mapper) NamedQuery(ctx context.Context, q Queryer, style BindStyle, query string, arg interface{}) (*sql.Rows, error) {
+	bound, args, err := m.BindNamed(style, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return q.QueryContext(ctx, bound, args...)
+}
+
+// NamedExec executes query with `:name` parameters bound from arg using e, see BindNamed
+func NamedExec(ctx context.Context, e Execer, style BindStyle, query string, arg interface{}) (sql.Result, error) {
+	return Default().NamedExec(ctx, e, style, query, arg)
+}
+
+// NamedExec is the same as NamedExec of the package for the mapper
+func (m *Mapper) NamedExec(ctx context.Context, e Execer, style BindStyle, query string, arg interface{}) (sql.Result, error) {
+	bound, args, err := m.BindNamed(style, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecContext(ctx, bound, args...)
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
+
+// namedValueLookup returns lookup of the values of the parameters by their names in arg
+func (st *state) namedValueLookup(arg interface{}) (func(name string) (interface{}, error), error) {
+	argValue := reflect.ValueOf(arg)
+	for argValue.Kind() == reflect.Ptr && !argValue.IsNil() {
+		argValue = argValue.Elem()
+	}
+
+	switch {
+	case argValue.Kind() == reflect.Map && argValue.Type().Key().Kind() == reflect.String:
+		return func(name string) (interface{}, error) {
+			v := argValue.MapIndex(reflect.ValueOf(name).Convert(argValue.Type().Key()))
+			if !v.IsValid() {
+				return nil, fmt.Errorf("no value for parameter :%s", name)
+			}
+			return v.Interface(), nil
+		}, nil
+
+	case argValue.Kind() == reflect.Struct:
+		columnAliasToAccessor, err := st.createFieldsAccessors(argValue.Type())
+		if err != nil {
+			return nil, err
+		}
+		return func(name string) (interface{}, error) {
+			accessor, found := columnAliasToAccessor[strings.ToLower(name)]
+			if !found {
+				return nil, fmt.Errorf("no field of %v is mapped to parameter :%s", argValue.Type(), name)
+			}
+			v, err := argValue.FieldByIndexErr(accessor.fieldIndex)
+			if err != nil {
+				return nil, fmt.Errorf("parameter :%s: %w", name, err)
+			}
+			if !v.CanInterface() {
+				return nil, fmt.Errorf("unexported field %s is mapped to parameter :%s", strings.Join(accessor.fieldPath, "."), name)
+			}
+			value, err := driverValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("parameter :%s: %w", name, err)
+			}
+			return value, nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("struct or map with string keys is expected, received: %T", arg)
+	}
+}
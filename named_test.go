@@ -0,0 +1,96 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBindNamed(t *testing.T) {
+	type audit struct {
+		CreatedBy string `db_column:"owner"`
+	}
+	type valStruct struct {
+		Id    int `db_column:"pk"`
+		Col1  string
+		Audit audit
+	}
+	arg := &valStruct{Id: 1, Col1: "a", Audit: audit{CreatedBy: "root"}}
+
+	query, args, err := BindNamed(BindDollar, "SELECT ':pk', id::text FROM t WHERE id = :pk AND col1 = :Col1 OR owner = :owner", arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "SELECT ':pk', id::text FROM t WHERE id = $1 AND col1 = $2 OR owner = $3"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+	if exp := []interface{}{1, "a", "root"}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	query, args, err = BindNamed(BindQuestion, "UPDATE t SET col1 = :col1 WHERE id = :id", map[string]interface{}{"id": 2, "col1": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "UPDATE t SET col1 = ? WHERE id = ?"; query != exp {
+		t.Errorf("unexpected query: expected %q, actual %q", exp, query)
+	}
+	if exp := []interface{}{"b", 2}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	_, args, err = BindNamed(BindQuestion, "SELECT :name", struct{ Name upperValue }{Name: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []interface{}{"C"}; !reflect.DeepEqual(args, exp) {
+		t.Errorf("unexpected args: expected %v, actual %v", exp, args)
+	}
+
+	if _, _, err := BindNamed(BindQuestion, "SELECT :missing", arg); err == nil {
+		t.Error("error expected for the parameter without value")
+	}
+	if _, _, err := BindNamed(BindQuestion, "SELECT :id", 1); err == nil {
+		t.Error("error expected for the argument of unsupported type")
+	}
+}
+
+func TestNamedExecAndQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	style := BindQuestion
+	if driverName() == "postgres" {
+		style = BindDollar
+	}
+	if _, err := NamedExec(ctx, tx, style, "INSERT INTO propagation(id, col1) VALUES (:id, :col1)", valStruct{Id: 1, Col1: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := NamedQuery(ctx, tx, style, "SELECT id, col1 FROM propagation WHERE id = :id", map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []valStruct
+	if err := PropagateAndClose(&results, rows); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Col1: "a"}}; !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
@@ -0,0 +1,95 @@
+package rowconv
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+)
+
+// networkConverters store values of inet/cidr/macaddr columns, both textual (PostgreSQL)
+// and binary, e.g. VARBINARY produced by INET6_ATON (MySQL), into fields of network types
+var networkConverters = map[reflect.Type]converter{
+	reflect.TypeOf(net.IP{}):           convertIP,
+	reflect.TypeOf(netip.Addr{}):       convertAddr,
+	reflect.TypeOf(netip.Prefix{}):     convertPrefix,
+	reflect.TypeOf(net.HardwareAddr{}): convertHardwareAddr,
+}
+
+func isNetworkType(t reflect.Type) bool {
+	_, found := networkConverters[t]
+	return found
+}
+
+func convertIP(src interface{}, dst reflect.Value) error {
+	addr, err := parseAddr(src)
+	if err != nil {
+		return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+	}
+	dst.Set(reflect.ValueOf(net.IP(addr.AsSlice())))
+	return nil
+}
+
+func convertAddr(src interface{}, dst reflect.Value) error {
+	addr, err := parseAddr(src)
+	if err != nil {
+		return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+	}
+	dst.Set(reflect.ValueOf(addr))
+	return nil
+}
+
+func convertPrefix(src interface{}, dst reflect.Value) error {
+	text := asString(src)
+	if strings.Contains(text, "/") {
+		prefix, err := netip.ParsePrefix(text)
+		if err != nil {
+			return &ParseError{Value: text, Type: dst.Type(), Err: err}
+		}
+		dst.Set(reflect.ValueOf(prefix))
+		return nil
+	}
+
+	// single address is a network of itself
+	addr, err := parseAddr(src)
+	if err != nil {
+		return &ParseError{Value: text, Type: dst.Type(), Err: err}
+	}
+	dst.Set(reflect.ValueOf(netip.PrefixFrom(addr, addr.BitLen())))
+	return nil
+}
+
+func convertHardwareAddr(src interface{}, dst reflect.Value) error {
+	text := asString(src)
+	mac, err := net.ParseMAC(text)
+	if err != nil {
+		// binary form of EUI-48, EUI-64 and 20-octet IP over InfiniBand addresses
+		raw, ok := src.([]byte)
+		if !ok || len(raw) != 6 && len(raw) != 8 && len(raw) != 20 {
+			return &ParseError{Value: text, Type: dst.Type(), Err: err}
+		}
+		mac = append(net.HardwareAddr(nil), raw...)
+	}
+	dst.Set(reflect.ValueOf(mac))
+	return nil
+}
+
+// parseAddr parses textual address, the prefix length of inet value (192.168.0.1/24) is dropped,
+// or binary address of 4 or 16 bytes
+func parseAddr(src interface{}) (netip.Addr, error) {
+	text := asString(src)
+	if i := strings.IndexByte(text, '/'); i >= 0 {
+		text = text[:i]
+	}
+	addr, err := netip.ParseAddr(text)
+	if err == nil {
+		return addr, nil
+	}
+
+	if raw, ok := src.([]byte); ok && (len(raw) == 4 || len(raw) == 16) {
+		addr, _ = netip.AddrFromSlice(raw)
+		return addr, nil
+	}
+	return netip.Addr{}, fmt.Errorf("invalid IP address: %w", err)
+}
@@ -0,0 +1,58 @@
+package rowconv
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestConvertNetwork(t *testing.T) {
+	for _, tc := range []struct {
+		src interface{}
+		exp interface{}
+	}{
+		{src: []byte("192.168.0.1/24"), exp: net.ParseIP("192.168.0.1").To4()},
+		{src: []byte{10, 0, 0, 1}, exp: net.IP{10, 0, 0, 1}},
+		{src: "::1", exp: netip.MustParseAddr("::1")},
+		{src: "10.0.0.0/8", exp: netip.MustParsePrefix("10.0.0.0/8")},
+		{src: "1.2.3.4", exp: netip.MustParsePrefix("1.2.3.4/32")},
+		{src: "08:00:2b:01:02:03", exp: net.HardwareAddr{8, 0, 0x2b, 1, 2, 3}},
+		{src: []byte{1, 2, 3, 4, 5, 6}, exp: net.HardwareAddr{1, 2, 3, 4, 5, 6}},
+	} {
+		dst := reflect.New(reflect.TypeOf(tc.exp)).Elem()
+		if err := convertDefault(tc.src, dst); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(dst.Interface(), tc.exp) {
+			t.Errorf("unexpected result of conversion of %v: expected %v, actual %v", tc.src, tc.exp, dst.Interface())
+		}
+	}
+
+	var addr netip.Addr
+	if err := convertDefault("not an address", reflect.ValueOf(&addr).Elem()); err == nil {
+		t.Error("error expected for malformed address")
+	}
+}
+
+func TestPropagateNetwork(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '10.0.0.1', '10.0.0.0/8')",
+		"SELECT col1, col2 FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 netip.Addr
+		Col2 *netip.Prefix
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	prefix := netip.MustParsePrefix("10.0.0.0/8")
+	exp := []valStruct{{Col1: netip.MustParseAddr("10.0.0.1"), Col2: &prefix}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
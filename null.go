@@ -0,0 +1,58 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NullPolicy stores value that represents NULL into the field dst
+type NullPolicy func(dst reflect.Value) error
+
+// RegisterNullPolicy configures how NULL is stored into fields of type t, so fields of value types
+// don't have to be references to accept NULL. The policy should be registered before the first propagation
+// into the struct with such fields, as compiled mappers are cached. nil policy removes the registration.
+func RegisterNullPolicy(t reflect.Type, policy NullPolicy) {
+	Default().RegisterNullPolicy(t, policy)
+}
+
+// RegisterNullPolicy is the same as RegisterNullPolicy of the package for the mapper
+func (m *Mapper) RegisterNullPolicy(t reflect.Type, policy NullPolicy) {
+	m.state.nullPolicies.Lock()
+	if policy == nil {
+		delete(m.state.nullPolicies.byType, t)
+	} else {
+		m.state.nullPolicies.byType[t] = policy
+	}
+	m.state.nullPolicies.Unlock()
+}
+
+func (st *state) nullPolicy(t reflect.Type) (NullPolicy, bool) {
+	st.nullPolicies.RLock()
+	policy, found := st.nullPolicies.byType[t]
+	st.nullPolicies.RUnlock()
+	return policy, found
+}
+
+// NullAsZero stores zero value of the field type: nil for references, 0 for numbers, "" for strings, etc.
+func NullAsZero() NullPolicy {
+	return func(dst reflect.Value) error {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+}
+
+// NullAsValue stores the sentinel value v, it must be assignable to the field type
+func NullAsValue(v interface{}) NullPolicy {
+	sentinel := reflect.ValueOf(v)
+	return func(dst reflect.Value) error {
+		if !sentinel.IsValid() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if !sentinel.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("NULL sentinel of type %v can't be stored into the type: %v", sentinel.Type(), dst.Type())
+		}
+		dst.Set(sentinel)
+		return nil
+	}
+}
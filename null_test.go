@@ -0,0 +1,44 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateWithNullPolicy(t *testing.T) {
+	type status string
+	RegisterNullPolicy(reflect.TypeOf(status("")), NullAsValue(status("unknown")))
+	defer RegisterNullPolicy(reflect.TypeOf(status("")), nil)
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'active')",
+		"SELECT id, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col2 status
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Col2: "unknown"}, {Id: 2, Col2: "active"}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestNullPolicies(t *testing.T) {
+	var i int = 5
+	if err := NullAsZero()(reflect.ValueOf(&i).Elem()); err != nil || i != 0 {
+		t.Errorf("zero value expected, actual: %v, error: %v", i, err)
+	}
+	if err := NullAsValue(-1)(reflect.ValueOf(&i).Elem()); err != nil || i != -1 {
+		t.Errorf("sentinel value expected, actual: %v, error: %v", i, err)
+	}
+	if err := NullAsValue("-1")(reflect.ValueOf(&i).Elem()); err == nil {
+		t.Error("error expected for sentinel of non-assignable type")
+	}
+}
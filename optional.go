@@ -0,0 +1,69 @@
+package rowconv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+)
+
+// Optional holds a value of the nullable column, it is absent if the column is NULL.
+// It is an alternative to the reference fields: `Optional[int]` instead of `*int`.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some returns present Optional holding v
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// None returns absent Optional
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Get returns the value and true if it is present or zero value and false otherwise
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Present returns true if the value is present
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+// OrElse returns the value if it is present or def otherwise
+func (o Optional[T]) OrElse(def T) T {
+	if o.present {
+		return o.value
+	}
+	return def
+}
+
+// Scan implements sql.Scanner, NULL makes the value absent
+func (o *Optional[T]) Scan(src interface{}) error {
+	var zero T
+	o.value, o.present = zero, false
+	if src == nil {
+		return nil
+	}
+
+	if scanner, ok := interface{}(&o.value).(sql.Scanner); ok {
+		if err := scanner.Scan(src); err != nil {
+			return err
+		}
+	} else if err := convertDefault(src, reflect.ValueOf(&o.value).Elem()); err != nil {
+		return err
+	}
+	o.present = true
+	return nil
+}
+
+// Value implements driver.Valuer, absent value is stored as NULL
+func (o Optional[T]) Value() (driver.Value, error) {
+	if !o.present {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
@@ -0,0 +1,55 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateOptional(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   Optional[int]
+		Col2 Optional[string]
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: Some(1), Col2: None[string]()},
+		{Id: Some(2), Col2: Some("c")},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestOptional(t *testing.T) {
+	present := Some(5)
+	if v, ok := present.Get(); v != 5 || !ok {
+		t.Errorf("unexpected value of present optional: %v, %v", v, ok)
+	}
+	if v := None[int]().OrElse(7); v != 7 {
+		t.Errorf("default value expected for absent optional, actual: %v", v)
+	}
+
+	if v, err := None[int]().Value(); v != nil || err != nil {
+		t.Errorf("NULL expected for absent optional, actual: %v, error: %v", v, err)
+	}
+	if v, err := present.Value(); v != int64(5) || err != nil {
+		t.Errorf("unexpected value for present optional: %v, error: %v", v, err)
+	}
+
+	var o Optional[float64]
+	if err := o.Scan([]byte("1.5")); err != nil || o.OrElse(0) != 1.5 {
+		t.Errorf("unexpected scan result: %+v, error: %v", o, err)
+	}
+	if err := o.Scan(nil); err != nil || o.Present() {
+		t.Errorf("absent optional expected after scan of NULL: %+v, error: %v", o, err)
+	}
+}
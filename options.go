@@ -0,0 +1,260 @@
+package rowconv
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Option configures a single propagation
+type Option func(*options)
+
+type options struct {
+	compile compileOptions
+
+	distinct      bool
+	distinctOn    string
+	progressEvery int
+	progress      func(rowsSoFar int)
+	middlewares   []Middleware
+	growth        SliceGrowth
+	query         string
+
+	closeRows bool
+
+	logger           Logger
+	unmappedColumn   unmappedColumnFunc
+	slowRowThreshold time.Duration
+	slowRowHook      func(SlowRow)
+}
+
+// newOptions returns the options of the propagation with the mapper returned by Default
+func newOptions(opts []Option) *options {
+	return Default().newOptions(opts)
+}
+
+// newStateOptions returns the options of the propagation with the mapper of st
+func newStateOptions(st *state, opts []Option) *options {
+	// strict checks are captured once, so changing them concurrently doesn't affect propagation in progress
+	o := &options{compile: compileOptions{
+		columnTypeCheck:   st.strictColumnTypeCheck(),
+		columnAmountCheck: st.strictColumnAmountCheck(),
+		moneyFormat:       st.defaultMoneyFormat(),
+		state:             st,
+	}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDistinctOn drops rows which value of the field/column was already seen during the propagation,
+// only the first row with each value is kept. fieldOrColumn is a column/alias name the field is mapped to
+// or a name of the field. For elements of basic types the whole value is compared and fieldOrColumn is ignored.
+func WithDistinctOn(fieldOrColumn string) Option {
+	return func(o *options) {
+		o.distinct = true
+		o.distinctOn = fieldOrColumn
+	}
+}
+
+// WithStrictColumnTypeCheck overrides StrictColumnTypeCheck for a single propagation
+func WithStrictColumnTypeCheck(strict bool) Option {
+	return func(o *options) {
+		o.compile.columnTypeCheck = strict
+	}
+}
+
+// WithStrictColumnAmountCheck overrides StrictColumnAmountCheck for a single propagation
+func WithStrictColumnAmountCheck(strict bool) Option {
+	return func(o *options) {
+		o.compile.columnAmountCheck = strict
+	}
+}
+
+// WithLenientNumbers enables parsing of numeric strings returned for text columns into fields of number types.
+// Surrounding whitespaces are ignored and whole numbers in floating point notation are accepted by integer fields.
+// Malformed values are reported with *ParseError.
+func WithLenientNumbers() Option {
+	return func(o *options) {
+		o.compile.lenientNumbers = true
+	}
+}
+
+// WithTrimmedStrings right-trims whitespaces of the values stored into fields of string type,
+// e.g. padding of fixed-width CHAR(n) columns. Single field can be trimmed with `trim` tag option: `db_column:"code,trim"`.
+func WithTrimmedStrings() Option {
+	return func(o *options) {
+		o.compile.trimStrings = true
+	}
+}
+
+// WithEmptyAsNull treats empty strings as NULL: fields of reference types are set to nil and
+// fields of value types to zero value. Single field can be configured with `emptynull` tag option:
+// `db_column:"middle_name,emptynull"`.
+func WithEmptyAsNull() Option {
+	return func(o *options) {
+		o.compile.emptyAsNull = true
+	}
+}
+
+// WithRawBytes enables zero-copy scanning of the columns without mapping: their values are read into sql.RawBytes
+// instead of being copied by database/sql, which saves allocations when most of the columns are discarded.
+// Values of the mapped columns are copied only if the field retains them, e.g. fields of []byte and string types.
+func WithRawBytes() Option {
+	return func(o *options) {
+		o.compile.rawBytes = true
+	}
+}
+
+// WithoutCache compiles the mapper for each propagation and doesn't keep it, nor the providers of the structs,
+// in the caches shared by propagations. It suits short-lived tools that map each type once and don't benefit
+// from caching, but pay for its locks and allocations. Struct pooling isn't applied to such propagations.
+func WithoutCache() Option {
+	return func(o *options) {
+		o.compile.noCache = true
+	}
+}
+
+// WithCloseRows defines if the rows are closed once the propagation is over, successfully or not.
+// By default the rows are left open for the caller, though database/sql closes them once all of them are consumed.
+func WithCloseRows(close bool) Option {
+	return func(o *options) {
+		o.closeRows = close
+	}
+}
+
+// closingRows runs propagate and closes the rows afterwards if it is configured,
+// the error of closing is returned only if the propagation succeeded
+func (o *options) closingRows(rows *sql.Rows, propagate func() error) error {
+	if !o.closeRows {
+		return propagate()
+	}
+
+	err := propagate()
+	if closeErr := rows.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// WithProgress invokes fn every time another `every` rows are propagated with the amount of rows propagated so far.
+// fn is called synchronously, so it should be fast to not slow down the propagation.
+func WithProgress(every int, fn func(rowsSoFar int)) Option {
+	return func(o *options) {
+		if every > 0 && fn != nil {
+			o.progressEvery = every
+			o.progress = fn
+		}
+	}
+}
+
+// wrapSink decorates sink according to the options
+func (o *options) wrapSink(sink Sink, elementType reflect.Type) (Sink, error) {
+	if ss, ok := sink.(*sliceSink); ok {
+		ss.applyGrowth(o.growth, o.query)
+	}
+	if o.distinct {
+		key, err := o.compile.state.distinctKeyExtractor(elementType, o.distinctOn)
+		if err != nil {
+			return nil, err
+		}
+		sink = &distinctSink{Sink: sink, key: key, seen: map[interface{}]struct{}{}}
+	}
+	if len(o.middlewares) != 0 {
+		// elements are transformed before other decorators see them
+		sink = &middlewareSink{Sink: sink, middlewares: o.middlewares}
+	}
+	if o.progress != nil {
+		// progress is the outermost to count all rows, including ones dropped by other decorators
+		sink = &progressSink{Sink: sink, every: o.progressEvery, fn: o.progress}
+	}
+	return sink, nil
+}
+
+type progressSink struct {
+	Sink
+	every int
+	fn    func(rowsSoFar int)
+	rows  int
+}
+
+func (ps *progressSink) Add(v reflect.Value) error {
+	if ps.rows++; ps.rows%ps.every == 0 {
+		ps.fn(ps.rows)
+	}
+	return ps.Sink.Add(v)
+}
+
+type distinctSink struct {
+	Sink
+	key  func(v reflect.Value) interface{}
+	seen map[interface{}]struct{}
+}
+
+func (ds *distinctSink) Add(v reflect.Value) error {
+	key := ds.key(v)
+	if _, seen := ds.seen[key]; seen {
+		return nil
+	}
+	ds.seen[key] = struct{}{}
+	return ds.Sink.Add(v)
+}
+
+func (st *state) distinctKeyExtractor(elementType reflect.Type, fieldOrColumn string) (func(v reflect.Value) interface{}, error) {
+	if st.isSingleBasicType(elementType) {
+		if !isComparableKey(elementType) {
+			return nil, fmt.Errorf("values of %v can't be distinct: the type is not comparable", elementType)
+		}
+		return comparableKey, nil
+	}
+
+	structType, _, err := unwrapPtrStructType(elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	columnAliasToAccessor, err := st.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	var fieldIndex []int
+	var fieldType reflect.Type
+	if accessor, found := columnAliasToAccessor[fieldOrColumn]; found {
+		fieldIndex, fieldType = accessor.fieldIndex, accessor.fieldType
+	} else if field, found := structType.FieldByName(fieldOrColumn); found {
+		fieldIndex, fieldType = field.Index, field.Type
+	} else {
+		return nil, fmt.Errorf("no field or column %q to distinct on in %v", fieldOrColumn, elementType)
+	}
+	if !isComparableKey(fieldType) {
+		return nil, fmt.Errorf("field or column %q of %v can't be distinct on: %v is not comparable", fieldOrColumn, elementType, fieldType)
+	}
+
+	return func(v reflect.Value) interface{} {
+		underlyingValue, _, _ := unwrapPtrStructValue(v)
+		return comparableKey(underlyingValue.FieldByIndex(fieldIndex))
+	}, nil
+}
+
+// isComparableKey returns true if values of t can be converted into the key of the map by comparableKey
+func isComparableKey(t reflect.Type) bool {
+	t = derefType(t)
+	return t.Comparable() || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// comparableKey returns value that can be used as a key of the map, references are resolved to the values
+func comparableKey(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return string(v.Bytes())
+	}
+	return v.Interface()
+}
@@ -0,0 +1,152 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPropagateWithDistinctOn(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'x'), (2, 'a', 'y'), (3, 'b', NULL), (4, 'b', 'z')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type refStruct struct {
+		PK   int    `db_column:"id"`
+		Name string `db_column:"col1"`
+		Col2 *string
+	}
+	var byColumn []*refStruct
+	if err := Propagate(&byColumn, rows, WithDistinctOn("col1")); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*refStruct{{PK: 1, Name: "a", Col2: StringRef("x")}, {PK: 3, Name: "b"}}
+	if !reflect.DeepEqual(byColumn, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, byColumn)
+	}
+}
+
+func TestPropagateWithDistinctOnBasicType(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'a')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var col1s []string
+	if err := Propagate(&col1s, rows, WithDistinctOn("")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(col1s, []string{"a", "b"}) {
+		t.Errorf("unexpeted results of propagation: %v", col1s)
+	}
+}
+
+func TestPropagateWithDistinctOnUnknownField(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var valStructs []struct {
+		Id   int
+		Col1 string
+	}
+	if err := Propagate(&valStructs, rows, WithDistinctOn("unknown")); err == nil {
+		t.Error("error expected for unknown field")
+	}
+}
+
+func TestPropagateWithDistinctOnNotComparableField(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var valStructs []struct {
+		Id   int
+		Col1 []string `db_column:"col1,set"`
+	}
+	var panicErr *PanicError
+	if err := Propagate(&valStructs, rows, WithDistinctOn("col1")); err == nil || errors.As(err, &panicErr) || !strings.Contains(err.Error(), "not comparable") {
+		t.Errorf("error of not comparable field expected, actual: %v", err)
+	}
+}
+
+func TestPropagateWithProgress(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')",
+		"SELECT id FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var reported []int
+	var ids []int
+	if err := Propagate(&ids, rows, WithProgress(2, func(rowsSoFar int) { reported = append(reported, rowsSoFar) })); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reported, []int{2, 4}) {
+		t.Errorf("unexpected progress reports: %v", reported)
+	}
+}
+
+func TestPropagateWithRawBytes(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id int
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows, WithRawBytes()); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1}, {Id: 2}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateWithoutCache(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT id, col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type refStruct struct {
+		Id   int
+		Col1 string
+	}
+	var refStructs []*refStruct
+	if err := NewMapper(WithoutCache()).Propagate(&refStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*refStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}}
+	if !reflect.DeepEqual(refStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, refStructs)
+	}
+
+	elementType := reflect.TypeOf(&refStruct{})
+	Default().state.scanDefinitions.RLock()
+	for key := range Default().state.scanDefinitions.byKey {
+		if key.elementType == elementType {
+			t.Error("scan definition is not expected to be cached")
+		}
+	}
+	Default().state.scanDefinitions.RUnlock()
+	Default().state.structProviders.RLock()
+	_, found := Default().state.structProviders.byType[elementType]
+	Default().state.structProviders.RUnlock()
+	if found {
+		t.Error("struct provider is not expected to be cached")
+	}
+}
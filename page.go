@@ -0,0 +1,115 @@
+package rowconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PageTotalColumn is the column/alias of the total count of the rows selected by the query passed to SelectPage:
+// `SELECT id, name, COUNT(*) OVER() AS total_count FROM users ORDER BY id`
+const PageTotalColumn = "total_count"
+
+// Page is the window of the rows selected by SelectPage or SelectPageCounted
+type Page[T any] struct {
+	// Items are the rows of the page
+	Items []T
+	// Total is the amount of the rows selected by the query regardless of the page
+	Total int64
+	// Offset is the amount of the rows skipped before the page
+	Offset int
+	// Limit is the maximum amount of the rows of the page
+	Limit int
+}
+
+// SelectPage selects the page of the rows of the query with args and returns them along with the total count
+// of the rows in one round trip. The query must select the total count with the window function as PageTotalColumn
+// along with the columns of T, which must be a struct or a reference to it. The query is limited to the page with
+// the clause of the dialect, so it must be ordered for the pages to be stable; the OFFSET FETCH clause of SQL Server
+// is valid only after ORDER BY, so the query of DialectSQLServer is rejected without it.
+// Total is 0 if the page is empty, e.g. when offset is beyond the last row, use SelectPageCounted if it must be
+// reported anyway.
+func SelectPage[T any](ctx context.Context, q Queryer, dialect Dialect, offset, limit int, query string, args ...interface{}) (Page[T], error) {
+	return SelectPageOn[T](Default(), ctx, q, dialect, offset, limit, query, args...)
+}
+
+// SelectPageOn is the same as SelectPage with the options of the mapper m
+func SelectPageOn[T any](m *Mapper, ctx context.Context, q Queryer, dialect Dialect, offset, limit int, query string, args ...interface{}) (Page[T], error) {
+	if err := checkPage(offset, limit); err != nil {
+		return Page[T]{}, err
+	}
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+	if derefType(itemType).Kind() != reflect.Struct {
+		return Page[T]{}, fmt.Errorf("struct type is expected as the item of the page with the total count column, received: %v", itemType)
+	}
+	paged, err := pageQuery(dialect, query, offset, limit)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	// rows are propagated into the struct of T with the count next to it
+	rowType := reflect.StructOf([]reflect.StructField{
+		{Name: "Item", Type: itemType},
+		{Name: "Total", Type: reflect.TypeOf(int64(0)), Tag: `db_column:"` + PageTotalColumn + `"`},
+	})
+	rows := reflect.New(reflect.SliceOf(rowType))
+	if err := m.Select(ctx, q, rows.Interface(), paged, args...); err != nil {
+		return Page[T]{}, err
+	}
+
+	page := Page[T]{Items: make([]T, rows.Elem().Len()), Offset: offset, Limit: limit}
+	for i := range page.Items {
+		row := rows.Elem().Index(i)
+		page.Items[i] = row.Field(0).Interface().(T)
+		page.Total = row.Field(1).Int()
+	}
+	return page, nil
+}
+
+// SelectPageCounted selects the page of the rows of the query with args the same way as SelectPage, but the total count
+// is selected by the paired COUNT query over the same query with args, so the query selects only the columns of T.
+// T may be of any type supported by Select, e.g. int64 for the page of identifiers.
+func SelectPageCounted[T any](ctx context.Context, q Queryer, dialect Dialect, offset, limit int, query string, args ...interface{}) (Page[T], error) {
+	return SelectPageCountedOn[T](Default(), ctx, q, dialect, offset, limit, query, args...)
+}
+
+// SelectPageCountedOn is the same as SelectPageCounted with the options of the mapper m
+func SelectPageCountedOn[T any](m *Mapper, ctx context.Context, q Queryer, dialect Dialect, offset, limit int, query string, args ...interface{}) (Page[T], error) {
+	if err := checkPage(offset, limit); err != nil {
+		return Page[T]{}, err
+	}
+	paged, err := pageQuery(dialect, query, offset, limit)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	page := Page[T]{Offset: offset, Limit: limit}
+	if err := m.Get(ctx, q, &page.Total, "SELECT COUNT(*) FROM ("+query+") counted", args...); err != nil {
+		return Page[T]{}, err
+	}
+	if err := m.Select(ctx, q, &page.Items, paged, args...); err != nil {
+		return Page[T]{}, err
+	}
+	return page, nil
+}
+
+func checkPage(offset, limit int) error {
+	if offset < 0 || limit <= 0 {
+		return fmt.Errorf("invalid page: offset %d, limit %d", offset, limit)
+	}
+	return nil
+}
+
+// pageQuery limits the query to the page with the clause of the dialect
+func pageQuery(dialect Dialect, query string, offset, limit int) (string, error) {
+	if dialect == DialectSQLServer {
+		if !strings.Contains(strings.ToUpper(query), "ORDER BY") {
+			return "", errors.New("query must have ORDER BY clause to be limited to the page with OFFSET FETCH of SQL Server")
+		}
+		return query + " OFFSET " + strconv.Itoa(offset) + " ROWS FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY", nil
+	}
+	return query + " LIMIT " + strconv.Itoa(limit) + " OFFSET " + strconv.Itoa(offset), nil
+}
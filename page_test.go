@@ -0,0 +1,94 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSelectPage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')"); err != nil {
+		t.Fatal(err)
+	}
+
+	dialect, condition := DialectMySQL, "id > ?"
+	if driverName() == "postgres" {
+		dialect, condition = DialectPostgres, "id > $1"
+	}
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+
+	page, err := SelectPage[valStruct](ctx, tx, dialect, 1, 2,
+		"SELECT id, col1, COUNT(*) OVER() AS "+PageTotalColumn+" FROM propagation WHERE "+condition+" ORDER BY id", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := Page[valStruct]{Items: []valStruct{{Id: 3, Col1: "c"}, {Id: 4, Col1: "d"}}, Total: 4, Offset: 1, Limit: 2}
+	if !reflect.DeepEqual(page, exp) {
+		t.Errorf("unexpeted page: expected %+v, actual %+v", exp, page)
+	}
+
+	counted, err := SelectPageCounted[valStruct](ctx, tx, dialect, 1, 2, "SELECT id, col1 FROM propagation WHERE "+condition+" ORDER BY id", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counted, exp) {
+		t.Errorf("unexpeted page: expected %+v, actual %+v", exp, counted)
+	}
+
+	counted, err = SelectPageCounted[valStruct](ctx, tx, dialect, 10, 2, "SELECT id, col1 FROM propagation ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counted.Items) != 0 || counted.Total != 5 {
+		t.Errorf("unexpeted page beyond the last row: %+v", counted)
+	}
+
+	if _, err := SelectPage[valStruct](ctx, tx, dialect, 0, 0, "SELECT id, col1 FROM propagation"); err == nil {
+		t.Error("error expected for empty page")
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	if act, err := pageQuery(DialectPostgres, "SELECT 1", 20, 10); err != nil || act != "SELECT 1 LIMIT 10 OFFSET 20" {
+		t.Errorf("unexpected query: %s, error: %v", act, err)
+	}
+	if act, err := pageQuery(DialectSQLServer, "SELECT 1 ORDER BY 1", 20, 10); err != nil || act != "SELECT 1 ORDER BY 1 OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY" {
+		t.Errorf("unexpected query: %s, error: %v", act, err)
+	}
+	if _, err := pageQuery(DialectSQLServer, "SELECT 1", 20, 10); err == nil {
+		t.Error("error expected for the query without ORDER BY for SQL Server")
+	}
+}
+
+func TestSelectPageErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := SelectPage[int64](ctx, db, DialectPostgres, 0, 10, "SELECT id, COUNT(*) OVER() AS "+PageTotalColumn+" FROM propagation ORDER BY id"); err == nil {
+		t.Error("error expected for the item of non-struct type")
+	}
+
+	type valStruct struct {
+		Id int
+	}
+	if _, err := SelectPage[valStruct](ctx, db, DialectSQLServer, 0, 10, "SELECT id, COUNT(*) OVER() AS "+PageTotalColumn+" FROM propagation"); err == nil {
+		t.Error("error expected for the query without ORDER BY for SQL Server")
+	}
+	if _, err := SelectPageCounted[valStruct](ctx, db, DialectSQLServer, 0, 10, "SELECT id FROM propagation"); err == nil {
+		t.Error("error expected for the query without ORDER BY for SQL Server")
+	}
+}
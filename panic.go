@@ -0,0 +1,86 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PanicError is returned when propagation panics, e.g. because of the reflection misuse on the destination
+type PanicError struct {
+	// Type is the type of the destination
+	Type reflect.Type
+	// Columns are the names of the columns of the rows
+	Columns []string
+	// Column is the name of the column/alias that was processed, it is empty if the panic happened outside the column processing
+	Column string
+	// FieldPath is a sequence of names of the fields from the root struct to the field the column is mapped to
+	FieldPath []string
+	// Value is the value passed to panic
+	Value interface{}
+}
+
+func (pe *PanicError) Error() string {
+	msg := fmt.Sprintf("propagation into the type: %v of columns: %v panicked", pe.Type, pe.Columns)
+	if pe.Column != "" {
+		msg += fmt.Sprintf(" on column/alias: %s", pe.Column)
+	}
+	if len(pe.FieldPath) != 0 {
+		msg += fmt.Sprintf(" mapped to field: %s", strings.Join(pe.FieldPath, "."))
+	}
+	return fmt.Sprintf("%s: %v", msg, pe.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (pe *PanicError) Unwrap() error {
+	err, _ := pe.Value.(error)
+	return err
+}
+
+// recoverColumnPanic must be deferred by scan destinations, it converts a panic of the column processing into PanicError stored in err.
+// The panic can't be left to the propagation as database/sql keeps the rows locked if it happens during the scan.
+func recoverColumnPanic(column string, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Column: column, Value: r}
+	}
+}
+
+// recoverPropagation must be deferred, it converts a panic of propagation into dstType into PanicError stored in err.
+// PanicError of the column, possibly wrapped by database/sql, is replaced with the one completed with the details of the propagation.
+func (st *state) recoverPropagation(dstType reflect.Type, rows *sql.Rows, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Value: r}
+	}
+
+	var panicErr *PanicError
+	if !errors.As(*err, &panicErr) || panicErr.Type != nil {
+		return
+	}
+	panicErr.Type = dstType
+	panicErr.Columns, _ = rows.Columns()
+	if panicErr.Column != "" {
+		panicErr.FieldPath = st.mappedFieldPath(dstType, panicErr.Column)
+	}
+	*err = panicErr
+}
+
+// mappedFieldPath returns path to the field of the struct contained in dstType the column is mapped to
+func (st *state) mappedFieldPath(dstType reflect.Type, column string) []string {
+	for dstType != nil {
+		switch dstType.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan, reflect.Map:
+			dstType = dstType.Elem()
+		case reflect.Struct:
+			accessors, err := st.createFieldsAccessors(dstType)
+			if err != nil {
+				return nil
+			}
+			return accessors[strings.ToLower(column)].fieldPath
+		default:
+			return nil
+		}
+	}
+	return nil
+}
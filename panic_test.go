@@ -0,0 +1,67 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type panickingScanner struct{}
+
+func (ps *panickingScanner) Scan(src interface{}) error {
+	panic("scanner misuse")
+}
+
+type panickingSink struct{}
+
+func (ps panickingSink) Add(v reflect.Value) error {
+	panic(errors.New("sink misuse"))
+}
+
+func (ps panickingSink) Flush() error { return nil }
+
+func TestPropagatePanicOfColumnIsReturnedAsError(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	type nested struct {
+		Col1 panickingScanner
+	}
+	type valStruct struct {
+		Id     int
+		Nested nested
+	}
+	var valStructs []valStruct
+	err := Propagate(&valStructs, rows)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("PanicError expected, actual: %v", err)
+	}
+	if panicErr.Type != reflect.TypeOf(&valStructs) || !reflect.DeepEqual(panicErr.Columns, []string{"id", "col1"}) ||
+		panicErr.Column != "col1" || !reflect.DeepEqual(panicErr.FieldPath, []string{"Nested", "Col1"}) || panicErr.Value != "scanner misuse" {
+		t.Errorf("unexpected panic error: %+v", panicErr)
+	}
+}
+
+func TestPropagateSinkPanicIsReturnedAsError(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	err := PropagateSink(panickingSink{}, reflect.TypeOf(0), rows)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("PanicError expected, actual: %v", err)
+	}
+	if panicErr.Type != reflect.TypeOf(0) || panicErr.Column != "" || panicErr.FieldPath != nil {
+		t.Errorf("unexpected panic error: %+v", panicErr)
+	}
+	if err.Error() != "propagation into the type: int of columns: [id] panicked: sink misuse" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
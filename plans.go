@@ -0,0 +1,237 @@
+package rowconv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SavePlans writes all plans of mapping columns to struct fields compiled so far into w.
+// Plans loaded with LoadPlans at the next start let Propagate skip inspection of struct types,
+// which is significant for services with many query shapes.
+func SavePlans(w io.Writer) error {
+	return Default().SavePlans(w)
+}
+
+// SavePlans is the same as SavePlans of the package for the mapper
+func (m *Mapper) SavePlans(w io.Writer) error {
+	pm := m.state.plans
+	pm.RLock()
+	plans := make([]plan, 0, len(pm.byKey))
+	for _, p := range pm.byKey {
+		plans = append(plans, p)
+	}
+	pm.RUnlock()
+
+	// stable order keeps saved files diffable
+	sort.Slice(plans, func(i, j int) bool { return plans[i].key() < plans[j].key() })
+	return json.NewEncoder(w).Encode(plans)
+}
+
+// LoadPlans reads plans written by SavePlans. A loaded plan is used only if the struct, including its nested structs,
+// still has the same fields of the same types and tags and its fields are still mapped to the same columns,
+// e.g. not changed by RegisterMapping or TagFallback, otherwise the mapping is compiled as usual.
+func LoadPlans(r io.Reader) error {
+	return Default().LoadPlans(r)
+}
+
+// LoadPlans is the same as LoadPlans of the package for the mapper
+func (m *Mapper) LoadPlans(r io.Reader) error {
+	var plans []plan
+	if err := json.NewDecoder(r).Decode(&plans); err != nil {
+		return err
+	}
+
+	pm := m.state.plans
+	pm.Lock()
+	for _, p := range plans {
+		pm.byKey[p.key()] = p
+	}
+	pm.Unlock()
+	return nil
+}
+
+// plan is a serializable mapping of the columns to the fields of the type
+type plan struct {
+	Type    string         `json:"type"`
+	Layout  string         `json:"layout"`
+	Columns []string       `json:"columns"`
+	Fields  []plannedField `json:"fields"`
+}
+
+// plannedField is a field the column at the position is mapped to
+type plannedField struct {
+	Column int    `json:"column"`
+	Index  []int  `json:"index"`
+	Type   string `json:"type"`
+}
+
+func (p plan) key() string {
+	return planKey(p.Type, p.Columns)
+}
+
+func planKey(typeSignature string, columns []string) string {
+	return typeSignature + "(" + strings.Join(columns, ",") + ")"
+}
+
+// typeSignature identifies the type across restarts of the program
+func typeSignature(t reflect.Type) string {
+	prefix := ""
+	for t.Kind() == reflect.Ptr && t.Name() == "" {
+		prefix += "*"
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return prefix + t.String()
+	}
+	return prefix + t.PkgPath() + "." + t.Name()
+}
+
+// typeLayout is the hash of the fields of the struct and of its nested structs, so the plan of another struct
+// of the same name, e.g. declared in another function, or of the changed struct is not applied to it
+func typeLayout(t reflect.Type) string {
+	structType, _, err := unwrapPtrStructType(t)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.New()
+	writeLayout(hash, structType, map[reflect.Type]bool{})
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeLayout writes the names, types and tags of the fields of structType into w, nested structs are written
+// in braces after the fields of their types. Unexported fields, e.g. of time.Time, are not nested into,
+// as they are never mapped, unless embedded.
+func writeLayout(w io.Writer, structType reflect.Type, visited map[reflect.Type]bool) {
+	visited[structType] = true
+	defer delete(visited, structType)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		io.WriteString(w, field.Name+" "+field.Type.String()+" "+strconv.Quote(string(field.Tag)))
+		if fieldType := derefType(field.Type); fieldType.Kind() == reflect.Struct && !visited[fieldType] && (field.PkgPath == "" || field.Anonymous) {
+			io.WriteString(w, "{")
+			writeLayout(w, fieldType, visited)
+			io.WriteString(w, "}")
+		}
+		io.WriteString(w, ";")
+	}
+}
+
+func planColumns(columnTypes []columnType) []string {
+	columns := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		columns[i] = strings.ToLower(columnType.Name())
+	}
+	return columns
+}
+
+type planManager struct {
+	byKey map[string]plan
+	// st is the state of the mapper the plans are restored with
+	st *state
+	sync.RWMutex
+}
+
+func (pm *planManager) record(dstType reflect.Type, columnTypes []columnType, accessors [][]fieldAccessor) {
+	p := plan{
+		Type:    typeSignature(dstType),
+		Layout:  typeLayout(dstType),
+		Columns: planColumns(columnTypes),
+	}
+	for i, columnAccessors := range accessors {
+		for _, accessor := range columnAccessors {
+			p.Fields = append(p.Fields, plannedField{Column: i, Index: accessor.fieldIndex, Type: accessor.fieldType.String()})
+		}
+	}
+
+	pm.Lock()
+	pm.byKey[p.key()] = p
+	pm.Unlock()
+}
+
+// accessors restores accessors of the fields from the plan, if there is a plan that still matches the type
+func (pm *planManager) accessors(dstType reflect.Type, columnTypes []columnType) ([][]fieldAccessor, bool) {
+	columns := planColumns(columnTypes)
+	pm.RLock()
+	p, found := pm.byKey[planKey(typeSignature(dstType), columns)]
+	pm.RUnlock()
+	if !found || p.Layout != typeLayout(dstType) {
+		return nil, false
+	}
+
+	structType, _, err := unwrapPtrStructType(dstType)
+	if err != nil {
+		return nil, false
+	}
+
+	accessors := make([][]fieldAccessor, len(columns))
+	for _, plannedField := range p.Fields {
+		if plannedField.Column < 0 || plannedField.Column >= len(columns) {
+			return nil, false
+		}
+
+		owner, field, ok := structFieldByIndex(structType, plannedField.Index)
+		if !ok || field.Type.String() != plannedField.Type {
+			return nil, false
+		}
+		columnAlias, options := pm.st.fieldColumnTag(owner, field)
+		if column, _ := splitJSONPath(columnAlias); column != columns[plannedField.Column] {
+			return nil, false
+		}
+		accessors[plannedField.Column] = append(accessors[plannedField.Column], fieldAccessor{
+			columnAlias: columnAlias,
+			fieldType:   field.Type,
+			fieldIndex:  plannedField.Index,
+			fieldPath:   fieldPathByIndex(structType, plannedField.Index),
+			options:     options,
+		})
+	}
+
+	// the fields mapped to the columns now, e.g. by the mapping registered since the plan was saved,
+	// must be the ones of the plan, otherwise they are never populated with it
+	columnAliasToAccessor, err := pm.st.createFieldsAccessors(dstType)
+	if err != nil {
+		return nil, false
+	}
+	for i, matched := range matchColumnAccessors(columnAliasToAccessor, columnTypes) {
+		if len(matched) != len(accessors[i]) {
+			return nil, false
+		}
+	}
+	return accessors, true
+}
+
+// fieldPathByIndex returns the names of the fields from the struct to the field of the valid index
+func fieldPathByIndex(structType reflect.Type, index []int) []string {
+	path := make([]string, len(index))
+	for i := range index {
+		_, field, _ := structFieldByIndex(structType, index[:i+1])
+		path[i] = field.Name
+	}
+	return path
+}
+
+// structFieldByIndex is reflect.Type.FieldByIndex that reports invalid index instead of panic,
+// the struct type the field belongs to is returned with it
+func structFieldByIndex(structType reflect.Type, index []int) (reflect.Type, reflect.StructField, bool) {
+	var owner reflect.Type
+	var field reflect.StructField
+	for _, fieldIndex := range index {
+		for structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct || fieldIndex < 0 || fieldIndex >= structType.NumField() {
+			return nil, reflect.StructField{}, false
+		}
+		owner, field = structType, structType.Field(fieldIndex)
+		structType = field.Type
+	}
+	return owner, field, len(index) > 0
+}
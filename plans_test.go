@@ -0,0 +1,146 @@
+package rowconv
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadPlans(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string `db_column:"col1"`
+	}
+
+	propagate := func() []valStruct {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+			"SELECT id, col1, col2 FROM propagation ORDER BY id",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		if err := Propagate(&valStructs, rows); err != nil {
+			t.Fatal(err)
+		}
+		return valStructs
+	}
+
+	propagate()
+	var saved bytes.Buffer
+	if err := SavePlans(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(saved.String(), typeSignature(reflect.TypeOf(valStruct{}))) {
+		t.Fatalf("plan for the type expected to be saved: %s", saved.String())
+	}
+
+	Default().state.plans.Lock()
+	Default().state.plans.byKey = map[string]plan{}
+	Default().state.plans.Unlock()
+	Default().state.scanDefinitions.Lock()
+	Default().state.scanDefinitions.byKey = map[definitionKey]*definitionsLRU{}
+	Default().state.scanDefinitions.Unlock()
+
+	if err := LoadPlans(&saved); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}}
+	if act := propagate(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, act)
+	}
+}
+
+func TestPlanOfSameNamedType(t *testing.T) {
+	columnTypes := []columnType{Column{Name: "id"}.known(), Column{Name: "col1"}.known()}
+	idOnly := func() reflect.Type {
+		type valStruct struct {
+			Id int
+		}
+		return reflect.TypeOf(valStruct{})
+	}()
+	idAndCol1 := func() reflect.Type {
+		type valStruct struct {
+			Id   int
+			Col1 string
+		}
+		return reflect.TypeOf(valStruct{})
+	}()
+
+	Default().state.plans.record(idOnly, columnTypes, [][]fieldAccessor{{{fieldIndex: []int{0}, fieldType: idOnly.Field(0).Type}}, nil})
+	if _, found := Default().state.plans.accessors(idOnly, columnTypes); !found {
+		t.Error("plan expected to be found for the recorded type")
+	}
+	if _, found := Default().state.plans.accessors(idAndCol1, columnTypes); found {
+		t.Error("plan of another type of the same name is not expected to be found")
+	}
+}
+
+func TestStalePlans(t *testing.T) {
+	columnTypes := []columnType{Column{Name: "id"}.known(), Column{Name: "col1"}.known()}
+	idOnly := [][]fieldAccessor{{{fieldIndex: []int{0}, fieldType: reflect.TypeOf(0)}}, nil}
+
+	// the nested struct of the same name gained the field of the column
+	withoutNested := func() reflect.Type {
+		type nested struct{ Col2 string }
+		type valStruct struct {
+			Id     int
+			Nested nested
+		}
+		return reflect.TypeOf(valStruct{})
+	}()
+	withNested := func() reflect.Type {
+		type nested struct{ Col1 string }
+		type valStruct struct {
+			Id     int
+			Nested nested
+		}
+		return reflect.TypeOf(valStruct{})
+	}()
+	mapper := NewMapper()
+	mapper.state.plans.record(withoutNested, columnTypes, idOnly)
+	if _, found := mapper.state.plans.accessors(withoutNested, columnTypes); !found {
+		t.Error("plan expected to be found for the recorded type")
+	}
+	if _, found := mapper.state.plans.accessors(withNested, columnTypes); found {
+		t.Error("plan is not expected to be found for the type with changed nested struct")
+	}
+
+	// the field is mapped to the column by the mapping registered after the plan is recorded
+	type valStruct struct {
+		Id   int
+		Name string
+	}
+	structType := reflect.TypeOf(valStruct{})
+	mapper.state.plans.record(structType, columnTypes, idOnly)
+	if _, found := mapper.state.plans.accessors(structType, columnTypes); !found {
+		t.Error("plan expected to be found for the recorded type")
+	}
+	if err := RegisterMappingOn[valStruct](mapper, map[string]string{"Name": "col1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := mapper.state.plans.accessors(structType, columnTypes); found {
+		t.Error("plan is not expected to be found once the column it left unmapped is mapped")
+	}
+}
+
+func TestStructFieldByIndex(t *testing.T) {
+	type inner struct {
+		Col2 string
+	}
+	type valStruct struct {
+		Id    int
+		Inner *inner
+	}
+	structType := reflect.TypeOf(valStruct{})
+
+	if owner, field, found := structFieldByIndex(structType, []int{1, 0}); !found || field.Name != "Col2" || owner != reflect.TypeOf(inner{}) {
+		t.Errorf("unexpected field by index: %+v of %v", field, owner)
+	}
+	for _, index := range [][]int{nil, {2}, {0, 0}, {1, 1}} {
+		if _, field, found := structFieldByIndex(structType, index); found {
+			t.Errorf("no field expected by index %v, actual: %+v", index, field)
+		}
+	}
+}
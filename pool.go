@@ -0,0 +1,90 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// StructPooling configures mapper to take structs from a pool instead of allocating a new one for each row.
+// Only elements of pointer to struct type are pooled. Structs that are no longer used by the caller
+// should be returned back with Release, otherwise pooling gives no benefit.
+func StructPooling(enabled bool) {
+	Default().StructPooling(enabled)
+}
+
+// StructPooling is the same as StructPooling of the package for the mapper
+func (m *Mapper) StructPooling(enabled bool) {
+	m.state.structPooling.Store(enabled)
+}
+
+func (st *state) structPoolingEnabled() bool {
+	return st.structPooling.Load().(bool)
+}
+
+// Release returns struct referenced by v into the pool, so it can be reused by the next propagations.
+// v must be a pointer to struct and must not be used by the caller after the call.
+func Release(v interface{}) error {
+	return Default().Release(v)
+}
+
+// Release is the same as Release of the package for the mapper
+func (m *Mapper) Release(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("pointer to the struct is expected, received: %T", v)
+	}
+	if value.IsNil() {
+		return nil
+	}
+
+	// zero the struct right away so pooled values don't hold references to the released data
+	value.Elem().Set(reflect.Zero(value.Elem().Type()))
+	m.state.structPools.pool(value.Type()).Put(v)
+	return nil
+}
+
+type structPoolManager struct {
+	byType map[reflect.Type]*sync.Pool
+	sync.RWMutex
+}
+
+func (spm *structPoolManager) pool(forType reflect.Type) *sync.Pool {
+	spm.RLock()
+	pool, found := spm.byType[forType]
+	spm.RUnlock()
+	if found {
+		return pool
+	}
+
+	spm.Lock()
+	defer spm.Unlock()
+	if pool, found = spm.byType[forType]; !found {
+		pool = &sync.Pool{}
+		spm.byType[forType] = pool
+	}
+	return pool
+}
+
+// provider returns struct provider that reuses released values of forType,
+// the second result is false if values of forType can't be pooled.
+// Provider of forType must be created by providers in advance.
+func (spm *structPoolManager) provider(forType reflect.Type, providers *structProvideManager) (structProvider, bool) {
+	if forType.Kind() != reflect.Ptr || forType.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	pool := spm.pool(forType)
+	actualType := forType.Elem()
+	initializer := providers.initializer(actualType)
+	return func() (reflect.Value, error) {
+		holderValue := reflect.New(actualType)
+		if released := pool.Get(); released != nil {
+			holderValue = reflect.ValueOf(released)
+		}
+		if err := initializer(holderValue.Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+		return holderValue, nil
+	}, true
+}
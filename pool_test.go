@@ -0,0 +1,57 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructPooling(t *testing.T) {
+	StructPooling(true)
+	defer StructPooling(false)
+
+	type columns struct {
+		Col1 string
+		Col2 *string
+	}
+	type refStruct struct {
+		Id      int
+		Columns *columns
+	}
+
+	retrieve := func() []*refStruct {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+			"SELECT id, col1, col2 FROM propagation ORDER BY id",
+		)
+		defer release()
+
+		var refStructs []*refStruct
+		if err := Propagate(&refStructs, rows); err != nil {
+			t.Fatal(err)
+		}
+		return refStructs
+	}
+
+	for _, refStruct := range retrieve() {
+		if err := Release(refStruct); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refStructs := retrieve()
+	exp := []*refStruct{
+		{Id: 1, Columns: &columns{Col1: "a", Col2: StringRef("b")}},
+		{Id: 2, Columns: &columns{Col1: "c"}},
+	}
+	if !reflect.DeepEqual(refStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, refStructs)
+	}
+}
+
+func TestReleaseNotStructPointer(t *testing.T) {
+	for _, v := range []interface{}{nil, 1, StringRef("a"), struct{}{}} {
+		if err := Release(v); err == nil {
+			t.Errorf("error expected for releasing of %#v", v)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// +build postgres
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateComposite(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id, ROW(id, col1, col2) AS props FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type props struct {
+		Id   int
+		Col1 string
+		Col2 *string
+	}
+	type valStruct struct {
+		Id    int
+		Props props `db_column:"props,composite"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: 1, Props: props{Id: 1, Col1: "a"}},
+		{Id: 2, Props: props{Id: 2, Col1: "b", Col2: StringRef("c")}},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
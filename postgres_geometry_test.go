@@ -0,0 +1,38 @@
+// +build postgres
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateGeometry(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, point(id, 2) AS location, box(point(0, 0), point(id, id)) AS area, "+
+			"polygon '((0,0),(1,0),(0,1))' AS shape, NULL::circle AS zone FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id       int
+		Location Point
+		Area     Box
+		Shape    Polygon
+		Zone     *Circle
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{
+		Id:       1,
+		Location: Point{X: 1, Y: 2},
+		Area:     Box{High: Point{X: 1, Y: 1}},
+		Shape:    Polygon{Points: []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}},
+	}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
@@ -0,0 +1,39 @@
+// +build postgres
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateJSONColumns(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		`SELECT id, CASE WHEN id = 1 THEN '{"name": "a", "tags": ["x", "y"]}'::jsonb END AS payload,
+			'{"k": 1}'::json AS attrs, '[1, 2]'::jsonb AS ids FROM propagation ORDER BY id`,
+	)
+	defer release()
+
+	type payload struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	type valStruct struct {
+		Id      int
+		Payload *payload
+		Attrs   map[string]int
+		Ids     []int
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: 1, Payload: &payload{Name: "a", Tags: []string{"x", "y"}}, Attrs: map[string]int{"k": 1}, Ids: []int{1, 2}},
+		{Id: 2, Attrs: map[string]int{"k": 1}, Ids: []int{1, 2}},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
@@ -0,0 +1,33 @@
+// +build postgres
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateRange(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT id, int4range(id::int, NULL) AS ids, 'empty'::numrange AS amounts FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id      int
+		Ids     Range[int]
+		Amounts Range[float64]
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: 1, Ids: Range[int]{Lower: 1, Bounds: RangeLowerInclusive | RangeUpperUnbounded}, Amounts: Range[float64]{Bounds: RangeEmpty}},
+		{Id: 2, Ids: Range[int]{Lower: 2, Bounds: RangeLowerInclusive | RangeUpperUnbounded}, Amounts: Range[float64]{Bounds: RangeEmpty}},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
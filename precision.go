@@ -0,0 +1,36 @@
+package rowconv
+
+import (
+	"reflect"
+	"time"
+)
+
+// WithTimePrecision truncates values stored into the fields of time.Time type to the precision, e.g. time.Microsecond,
+// so values are compared deterministically regardless of the fraction of the second kept by the database and the driver:
+// TIMESTAMP(6) of PostgreSQL and DATETIME(6) of MySQL keep microseconds, DATETIME2 of SQL Server keeps 100 nanoseconds
+// and textual timestamps of SQLite keep as many digits as written. Without the option the fraction is stored as returned
+// by database driver. The precision must divide a second evenly, 0 disables truncation.
+func WithTimePrecision(precision time.Duration) Option {
+	return func(o *options) {
+		o.compile.timePrecision = precision
+	}
+}
+
+// truncatedTime creates converter that truncates the time stored with convert to the precision
+func truncatedTime(precision time.Duration, convert converter) converter {
+	return func(src interface{}, dst reflect.Value) error {
+		if err := convert(src, dst); err != nil {
+			return err
+		}
+
+		value := dst
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil
+			}
+			value = value.Elem()
+		}
+		value.Set(reflect.ValueOf(value.Interface().(time.Time).Truncate(precision)))
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimePrecision(t *testing.T) {
+	exp := time.Date(2026, time.October, 16, 10, 30, 0, 123456000, time.UTC)
+	retrieval := map[string]string{
+		"postgres":   "SELECT id, TIMESTAMP '2026-10-16 10:30:00.123456' AS col3 FROM propagation",
+		"mysql":      "SELECT id, CAST('2026-10-16 10:30:00.123456' AS DATETIME(6)) AS col3 FROM propagation",
+		"sqlserver":  "SELECT id, CAST('2026-10-16 10:30:00.1234567' AS DATETIME2(7)) AS col3 FROM propagation",
+		"clickhouse": "SELECT id, toDateTime64('2026-10-16 10:30:00.123456789', 9, 'UTC') AS col3 FROM propagation",
+		"sqlite3":    "SELECT id, col3 FROM propagation",
+	}[driverName()]
+	switch driverName() {
+	case "sqlserver":
+		exp = exp.Add(700 * time.Nanosecond)
+	case "clickhouse", "sqlite3":
+		exp = exp.Add(789 * time.Nanosecond)
+	case "postgres", "mysql":
+	default:
+		t.Skip("no timestamps with fraction of the second for the driver: " + driverName())
+	}
+
+	// SQLite keeps the timestamp as written, other databases select the literal of the type with fraction
+	insert := "INSERT INTO propagation(id, col1) VALUES (1, 'a')"
+	if driverName() == "sqlite3" {
+		insert = "INSERT INTO propagation(id, col1, col3) VALUES (1, 'a', '2026-10-16 10:30:00.123456789')"
+	}
+
+	type valStruct struct {
+		Id   int
+		Col3 *time.Time
+	}
+	propagate := func(opts ...Option) time.Time {
+		rows, release := queryPropagation(t, insert, retrieval)
+		defer release()
+
+		var valStructs []valStruct
+		if err := Propagate(&valStructs, rows, opts...); err != nil {
+			t.Fatal(err)
+		}
+		if len(valStructs) != 1 || valStructs[0].Col3 == nil {
+			t.Fatalf("unexpeted results of propagation: %+v", valStructs)
+		}
+		return *valStructs[0].Col3
+	}
+
+	if act := propagate(); !act.Equal(exp) {
+		t.Errorf("fraction of the second expected to be preserved: expected %v, actual %v", exp, act)
+	}
+	if act, exp := propagate(WithTimePrecision(time.Millisecond)), exp.Truncate(time.Millisecond); !act.Equal(exp) {
+		t.Errorf("time expected to be truncated: expected %v, actual %v", exp, act)
+	}
+}
+
+func TestTruncatedTime(t *testing.T) {
+	src := time.Date(2026, time.October, 16, 10, 30, 0, 123456789, time.UTC)
+	convert := truncatedTime(time.Microsecond, convertReference(convertDefault))
+
+	var value time.Time
+	if err := convert(src, reflect.ValueOf(&value).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := src.Truncate(time.Microsecond); !value.Equal(exp) {
+		t.Errorf("unexpected truncated time: expected %v, actual %v", exp, value)
+	}
+
+	ref := &value
+	if err := convert(nil, reflect.ValueOf(&ref).Elem()); err != nil || ref != nil {
+		t.Errorf("nil expected for NULL, actual: %v, error: %v", ref, err)
+	}
+}
@@ -0,0 +1,153 @@
+package rowconv
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// columnType is the part of sql.ColumnType mappers are compiled from,
+// it is implemented by *sql.ColumnType of the rows and by Column known ahead of time
+type columnType interface {
+	Name() string
+	DatabaseTypeName() string
+	ScanType() reflect.Type
+}
+
+// rowsColumnTypes returns column types of the rows
+func rowsColumnTypes(rows *sql.Rows) ([]columnType, error) {
+	sqlColumnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	columnTypes := make([]columnType, len(sqlColumnTypes))
+	for i, sqlColumnType := range sqlColumnTypes {
+		columnTypes[i] = sqlColumnType
+	}
+	return columnTypes, nil
+}
+
+func sameColumnType(a, b columnType) bool {
+	if sqlA, ok := a.(*sql.ColumnType); ok {
+		if sqlB, ok := b.(*sql.ColumnType); ok {
+			return *sqlA == *sqlB
+		}
+	}
+	return a.Name() == b.Name() && a.DatabaseTypeName() == b.DatabaseTypeName() && a.ScanType() == b.ScanType()
+}
+
+// Column describes the column of the result set known ahead of time, see Prepare
+type Column struct {
+	// Name is the name of the column/alias
+	Name string
+	// DatabaseTypeName is the database system name of the column type as reported by the driver, e.g. "VARCHAR"
+	DatabaseTypeName string
+	// ScanType is the type suitable for scanning the column into as reported by the driver,
+	// it is required only by StrictColumnTypeCheck, nil means interface{}
+	ScanType reflect.Type
+}
+
+func (c Column) known() knownColumn {
+	return knownColumn{name: c.Name, databaseTypeName: c.DatabaseTypeName, scanType: c.ScanType}
+}
+
+// knownColumn is Column in the form mappers are compiled from
+type knownColumn struct {
+	name             string
+	databaseTypeName string
+	scanType         reflect.Type
+}
+
+func (kc knownColumn) Name() string { return kc.name }
+
+func (kc knownColumn) DatabaseTypeName() string { return kc.databaseTypeName }
+
+func (kc knownColumn) ScanType() reflect.Type {
+	if kc.scanType == nil {
+		return reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+	return kc.scanType
+}
+
+// Prepared is the mapper compiled ahead of time with Prepare
+type Prepared struct {
+	elementType reflect.Type
+	columns     []Column
+	scanDef     scanDefinition
+	opts        *options
+}
+
+// Prepare compiles the mapper of the rows with the columns into elements of elementType ahead of time,
+// so the broken mapping is reported at the start of the service rather than on the first propagation.
+// forType is the type of the elements of dst accepted by Propagate, i.e. struct, reference to the struct or basic type.
+func Prepare(forType reflect.Type, columns []Column, opts ...Option) (*Prepared, error) {
+	return Default().Prepare(forType, columns, opts...)
+}
+
+// Prepare is the same as Prepare of the package with the options of the mapper
+func (m *Mapper) Prepare(forType reflect.Type, columns []Column, opts ...Option) (*Prepared, error) {
+	holderElementType, err := elementType(forType)
+	if err != nil {
+		return nil, err
+	}
+
+	o := m.newOptions(opts)
+	columnTypes := make([]columnType, len(columns))
+	for i, column := range columns {
+		columnTypes[i] = column.known()
+	}
+	scanDef, err := createScanDefinition(holderElementType, columnTypes, o.compile)
+	if err != nil {
+		return nil, err
+	}
+	return &Prepared{elementType: holderElementType, columns: append([]Column(nil), columns...), scanDef: scanDef, opts: o}, nil
+}
+
+// Propagate converts rows into dst the same way Propagate does with the options of Prepare.
+// Elements of dst must be of the prepared type and rows must have the prepared columns in the same order.
+func (p *Prepared) Propagate(dst interface{}, rows *sql.Rows) error {
+	return p.opts.closingRows(rows, func() (err error) {
+		defer p.opts.compile.state.recoverPropagation(reflect.TypeOf(dst), rows, &err)
+
+		sink, holderElementType, err := p.opts.compile.state.newSink(dst)
+		if err != nil {
+			return err
+		}
+		if holderElementType != p.elementType {
+			return fmt.Errorf("mapper is prepared for elements of the type: %v, not %v", p.elementType, holderElementType)
+		}
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		if err := p.checkColumns(columns); err != nil {
+			return err
+		}
+
+		if sink, err = p.opts.wrapSink(sink, holderElementType); err != nil {
+			return err
+		}
+		if err := propagateRows(p.scanDef.scanner(p.opts.unmappedColumn), sink, rows, p.opts); err != nil {
+			return err
+		}
+		return sink.Flush()
+	})
+}
+
+func (p *Prepared) checkColumns(columns []string) error {
+	matches := len(columns) == len(p.columns)
+	for i := 0; matches && i < len(columns); i++ {
+		matches = strings.EqualFold(columns[i], p.columns[i].Name)
+	}
+	if !matches {
+		names := make([]string, len(p.columns))
+		for i, column := range p.columns {
+			names[i] = column.Name
+		}
+		return fmt.Errorf("mapper is prepared for columns: %v, received: %v", names, columns)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrepared(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+		Col2 *string
+	}
+	prepared, err := Prepare(reflect.TypeOf(valStruct{}), []Column{
+		{Name: "id", DatabaseTypeName: "DECIMAL"},
+		{Name: "col1", DatabaseTypeName: "VARCHAR"},
+		{Name: "col2", DatabaseTypeName: "VARCHAR"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := prepared.Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	b := "b"
+	exp := []valStruct{{Id: 1, Col1: "a", Col2: &b}, {Id: 2, Col1: "c"}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPreparedMismatch(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	prepared, err := Prepare(reflect.TypeOf(valStruct{}), []Column{{Name: "id"}, {Name: "col1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT col1, id FROM propagation",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := prepared.Propagate(&valStructs, rows); err == nil {
+		t.Error("error expected for columns in different order")
+	}
+	var ids []int
+	if err := prepared.Propagate(&ids, rows); err == nil {
+		t.Error("error expected for elements of different type")
+	}
+}
+
+func TestPrepareBrokenMapping(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Name string `db_column:"id"`
+	}
+	if _, err := Prepare(reflect.TypeOf(valStruct{}), []Column{{Name: "id"}}); err == nil {
+		t.Error("error expected for the column mapped to more than one field")
+	}
+
+	type strictStruct struct {
+		Id int
+	}
+	if _, err := Prepare(reflect.TypeOf(strictStruct{}), []Column{{Name: "id"}, {Name: "unknown"}}, WithStrictColumnAmountCheck(true)); err == nil {
+		t.Error("error expected for the column without mapping")
+	}
+}
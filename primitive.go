@@ -0,0 +1,74 @@
+package rowconv
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// primitive is the type of the elements of the slices propagated without reflection, see propagatePrimitives
+type primitive interface {
+	int64 | string | float64 | time.Time
+}
+
+// propagatePrimitives propagates the single column into []int64, []string, []float64 or []time.Time with the loop
+// specialized for the type of the slice, so rows don't pay for reflect.New and reflect.Append of the generic path.
+// It reports false if dst is not such slice or the propagation needs the conversion or the features of the generic path.
+func propagatePrimitives(dst interface{}, rows *sql.Rows, opts *options) (bool, error) {
+	var elementType reflect.Type
+	switch dst.(type) {
+	case *[]int64:
+		elementType = reflect.TypeOf(int64(0))
+	case *[]string:
+		elementType = reflect.TypeOf("")
+	case *[]float64:
+		elementType = reflect.TypeOf(float64(0))
+	case *[]time.Time:
+		elementType = timeType
+	default:
+		return false, nil
+	}
+	if reflect.ValueOf(dst).IsNil() || !opts.plainSink() {
+		return false, nil
+	}
+	if _, found := opts.compile.state.constructorOf(elementType); found {
+		return false, nil
+	}
+
+	columnTypes, err := rowsColumnTypes(rows)
+	if err != nil {
+		return true, err
+	}
+	if len(columnTypes) != 1 || opts.compile.columnConverter(columnTypes[0], elementType, nil) != nil {
+		return false, nil
+	}
+
+	switch dst := dst.(type) {
+	case *[]int64:
+		return true, scanPrimitives(dst, rows)
+	case *[]string:
+		return true, scanPrimitives(dst, rows)
+	case *[]float64:
+		return true, scanPrimitives(dst, rows)
+	default:
+		return true, scanPrimitives(dst.(*[]time.Time), rows)
+	}
+}
+
+func scanPrimitives[T primitive](dst *[]T, rows *sql.Rows) error {
+	for rows.Next() {
+		var v T
+		if err := rows.Scan(&v); err != nil {
+			return err
+		}
+		*dst = append(*dst, v)
+	}
+	return rows.Err()
+}
+
+// plainSink reports if the elements are appended to the slice as is, without the decorators of wrapSink,
+// slice growth and slow row reports
+func (o *options) plainSink() bool {
+	return !o.distinct && len(o.middlewares) == 0 && o.progress == nil &&
+		o.growth.Initial == nil && o.growth.Chunk <= 0 && o.slowRowThreshold <= 0
+}
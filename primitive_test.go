@@ -0,0 +1,70 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropagatePrimitives(t *testing.T) {
+	insert := "INSERT INTO propagation(id, col1, col3) VALUES (1, 'a', '2021-03-04 05:06:07'), (2, 'b', '2022-03-04 05:06:07')"
+	identity := WithMiddleware(func(v reflect.Value) (reflect.Value, error) { return v, nil })
+
+	for _, tc := range []struct {
+		name    string
+		query   string
+		fast    interface{}
+		generic interface{}
+	}{
+		{name: "int64", query: "SELECT id FROM propagation ORDER BY id", fast: &[]int64{0}, generic: &[]int64{0}},
+		{name: "string", query: "SELECT col1 FROM propagation ORDER BY id", fast: &[]string{""}, generic: &[]string{""}},
+		{name: "float64", query: "SELECT id FROM propagation ORDER BY id", fast: &[]float64{0}, generic: &[]float64{0}},
+		{name: "time.Time", query: "SELECT col3 FROM propagation ORDER BY id", fast: &[]time.Time{{}}, generic: &[]time.Time{{}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, release := queryPropagation(t, insert, tc.query)
+			handled, err := propagatePrimitives(tc.fast, rows, newOptions(nil))
+			release()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !handled {
+				t.Error("propagation expected to be handled by the fast path")
+			}
+
+			// the middleware makes the propagation go through the generic path
+			rows, release = queryPropagation(t, insert, tc.query)
+			err = Propagate(tc.generic, rows, identity)
+			release()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.fast, tc.generic) {
+				t.Errorf("results of the fast path differ from the generic one: %v, %v", tc.fast, tc.generic)
+			}
+		})
+	}
+}
+
+func TestPropagatePrimitivesFallback(t *testing.T) {
+	insert := "INSERT INTO propagation(id, col1) VALUES (1, ' a '), (2, 'b')"
+	for _, tc := range []struct {
+		name  string
+		query string
+		opts  []Option
+	}{
+		{name: "multiple columns", query: "SELECT col1, col2 FROM propagation ORDER BY id"},
+		{name: "conversion", query: "SELECT col1 FROM propagation ORDER BY id", opts: []Option{WithTrimmedStrings()}},
+		{name: "distinct", query: "SELECT col1 FROM propagation ORDER BY id", opts: []Option{WithDistinctOn("")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, release := queryPropagation(t, insert, tc.query)
+			defer release()
+
+			var values []string
+			if handled, _ := propagatePrimitives(&values, rows, newOptions(tc.opts)); handled {
+				t.Error("propagation expected to be left for the generic path")
+			}
+		})
+	}
+}
@@ -2,183 +2,470 @@ package rowconv
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
 const (
-	dbColumn = "db_column"
+	dbColumn  = "db_column"
+	dbRownum  = "db_rownum"
+	dbTable   = "db_table"
+	dbLayout  = "db_layout"
+	dbScanner = "db_scanner"
 )
 
 var (
-	columnTypeCheck   atomic.Value
-	columnAmountCheck atomic.Value
-
-	scanDefinitionsMgr = &scanDefinitionsManager{byType: map[reflect.Type][]scanDefinition{}}
-	structProviderMgr  = &structProvideManager{byType: map[reflect.Type]structProvider{}}
-
-	smallestStructDecompositions = struct {
-		set map[reflect.Type]struct{}
-		sync.RWMutex
-	}{
-		set: map[reflect.Type]struct{}{
-			reflect.TypeOf(time.Time{}):     {},
-			reflect.TypeOf(time.Location{}): {},
-		},
-	}
-
 	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 )
 
-func init() {
-	columnTypeCheck.Store(false)
-	columnAmountCheck.Store(false)
-}
-
 // StrictColumnTypeCheck configures mapper to check types of struct fields with types returned by database driver
 // if types are different and 'strict' set to 'true' the error will be produced
 func StrictColumnTypeCheck(strict bool) {
-	columnTypeCheck.Store(strict)
+	Default().StrictColumnTypeCheck(strict)
+}
+
+// StrictColumnTypeCheck is the same as StrictColumnTypeCheck of the package for the mapper
+func (m *Mapper) StrictColumnTypeCheck(strict bool) {
+	m.state.columnTypeCheck.Store(strict)
 }
 
-func strictColumnTypeCheck() bool {
-	return columnTypeCheck.Load().(bool)
+func (st *state) strictColumnTypeCheck() bool {
+	return st.columnTypeCheck.Load().(bool)
 }
 
 // StrictColumnAmountCheck configures mapper to check amount of struct fields to be exact to amount of columns returned
 // if amount is different and 'strict' set to 'true' the error will be produced
 func StrictColumnAmountCheck(strict bool) {
-	columnAmountCheck.Store(strict)
+	Default().StrictColumnAmountCheck(strict)
 }
 
-func strictColumnAmountCheck() bool {
-	return columnAmountCheck.Load().(bool)
+// StrictColumnAmountCheck is the same as StrictColumnAmountCheck of the package for the mapper
+func (m *Mapper) StrictColumnAmountCheck(strict bool) {
+	m.state.columnAmountCheck.Store(strict)
+}
+
+func (st *state) strictColumnAmountCheck() bool {
+	return st.columnAmountCheck.Load().(bool)
 }
 
 // SmallestStructDecomposition adds struct to set of structs that not need to be field-initialized,
 // such as time.Time and time.Location
-// `time.Time` and `time.Location` are added by default
+// `time.Time`, `time.Location`, `netip.Addr`, `netip.Prefix` and `BlobSink` are added by default
 func SmallestStructDecomposition(t reflect.Type) {
-	smallestStructDecompositions.Lock()
-	smallestStructDecompositions.set[t] = struct{}{}
-	smallestStructDecompositions.Unlock()
+	Default().SmallestStructDecomposition(t)
+}
+
+// SmallestStructDecomposition is the same as SmallestStructDecomposition of the package for the mapper
+func (m *Mapper) SmallestStructDecomposition(t reflect.Type) {
+	m.state.smallestStructDecompositions.Lock()
+	m.state.smallestStructDecompositions.set[t] = struct{}{}
+	m.state.smallestStructDecompositions.Unlock()
+}
+
+// Propagate converts rows into structs/basic values according to settings and put them into dst.
+// dst is a pointer to the slice that will be extended with a new element for each row,
+// a pointer to the map which values are put by the key assembled from the fields tagged with `key` option,
+// a channel each element is sent to, TypedAppender each element is appended to,
+// or a pointer to the struct with fields of slice type, each of which is extended with the value of
+// the corresponding column for each row (column-wise/columnar form).
+// The rows are left open for the caller, unless WithCloseRows(true) is provided.
+func Propagate(dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return Default().Propagate(dst, rows, opts...)
+}
+
+// PropagateAndClose is Propagate that always closes the rows, even if the propagation fails
+func PropagateAndClose(dst interface{}, rows *sql.Rows, opts ...Option) error {
+	return Default().PropagateAndClose(dst, rows, opts...)
 }
 
-// Propagate converts rows into structs/basic values according to settings and put them into dst
-func Propagate(dst interface{}, rows *sql.Rows) error {
-	columnTypes, err := rows.ColumnTypes()
+// PropagateSets converts each result set of rows into the corresponding destination, in order.
+// The first destination receives the current result set, every next one is switched to with rows.NextResultSet.
+// Each destination has the same requirements as dst of Propagate and is mapped with its own compiled mapper.
+// It is an error if rows has fewer result sets than destinations provided. The rows are left open for the caller.
+func PropagateSets(rows *sql.Rows, dsts ...interface{}) error {
+	return Default().PropagateSets(rows, dsts...)
+}
+
+// PropagateSets is the same as PropagateSets of the package with the options of the mapper
+func (m *Mapper) PropagateSets(rows *sql.Rows, dsts ...interface{}) error {
+	opts := m.newOptions(nil)
+	for i, dst := range dsts {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("no result set for destination #%d, only %d available", i, i)
+		}
+
+		if err := propagate(dst, rows, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PropagateSink converts rows into values of elementType and adds them to the sink one by one.
+// The sink is flushed once all rows are consumed. The rows are left open for the caller, unless WithCloseRows(true) is provided.
+func PropagateSink(sink Sink, elementType reflect.Type, rows *sql.Rows, opts ...Option) error {
+	return Default().PropagateSink(sink, elementType, rows, opts...)
+}
+
+// propagate maps rows into dst, a panic of the mapping is returned as PanicError
+func propagate(dst interface{}, rows *sql.Rows, opts *options) error {
+	return opts.closingRows(rows, func() (err error) {
+		defer opts.compile.state.recoverPropagation(reflect.TypeOf(dst), rows, &err)
+		return propagateInto(dst, rows, opts)
+	})
+}
+
+func propagateInto(dst interface{}, rows *sql.Rows, opts *options) error {
+	if handled, err := propagatePrimitives(dst, rows, opts); handled {
+		return err
+	}
+
+	_, appender := dst.(TypedAppender)
+	if holderType := reflect.TypeOf(dst); !appender && holderType != nil && holderType.Kind() == reflect.Ptr && opts.compile.state.isColumnarType(holderType.Elem()) {
+		columnTypes, err := rowsColumnTypes(rows)
+		if err != nil {
+			return err
+		}
+
+		scanDef, err := opts.compile.state.columnarDefinitions.getOrCreateSync(holderType.Elem(), columnTypes, opts.compile)
+		if err != nil {
+			return err
+		}
+		return scanDef.mapper(dst, rows)
+	}
+
+	sink, holderElementType, err := opts.compile.state.newSink(dst)
 	if err != nil {
 		return err
 	}
+	return propagateSink(sink, holderElementType, rows, opts)
+}
+
+// propagateRows scans all rows with scan and adds them to the sink
+func propagateRows(scan rowScanner, sink Sink, rows *sql.Rows, opts *options) error {
+	slowRowThreshold := opts.slowRowThreshold
+	for rowNumber := 1; rows.Next(); rowNumber++ {
+		var start time.Time
+		if slowRowThreshold > 0 {
+			start = time.Now()
+		}
 
-	holderType := reflect.TypeOf(dst)
-	if holderType.Kind() != reflect.Ptr {
-		return errors.New("pointer to the slice is expected, received: " + holderType.String())
+		holderElement, err := scan(rows)
+		if err != nil {
+			// FieldError holds the column, so the wrapping of database/sql is dropped
+			var fieldErr *FieldError
+			if errors.As(err, &fieldErr) {
+				fieldErr.Row = rowNumber
+				return fieldErr
+			}
+			return err
+		}
+		if err := sink.Add(holderElement); err != nil {
+			return err
+		}
+
+		if slowRowThreshold > 0 {
+			if elapsed := time.Since(start); elapsed > slowRowThreshold {
+				opts.reportSlowRow(SlowRow{Number: rowNumber, Type: holderElement.Type(), Elapsed: elapsed}, rows)
+			}
+		}
+	}
+	return rows.Err()
+}
+
+func propagateSink(sink Sink, holderElementType reflect.Type, rows *sql.Rows, opts *options) error {
+	columnTypes, err := rowsColumnTypes(rows)
+	if err != nil {
+		return err
 	}
 
-	holderElemType := holderType.Elem()
-	if holderElemType.Kind() != reflect.Slice {
-		return errors.New("pointer to the slice is expected, received: " + holderType.String())
+	holderElementType, err = elementType(holderElementType)
+	if err != nil {
+		return err
 	}
 
-	holderElementType, err := elementType(holderElemType)
+	scanDef, err := scanDefinitionOf(holderElementType, columnTypes, opts.compile)
 	if err != nil {
 		return err
 	}
 
-	scanDef, err := scanDefinitionsMgr.getOrCreateSync(holderElementType, columnTypes)
+	sink, err = opts.wrapSink(sink, holderElementType)
 	if err != nil {
 		return err
 	}
 
-	return scanDef.mapper(dst, rows)
+	if err := propagateRows(scanDef.scanner(opts.unmappedColumn), sink, rows, opts); err != nil {
+		return err
+	}
+	return sink.Flush()
 }
 
-func isSmallestStructDecomposition(t reflect.Type) bool {
-	if t.Implements(scannerType) {
+// scanDefinitionOf returns the definition of scanning the columns into the element type,
+// the mapping is recorded by the coverage recorder of the mapper, if there is one
+func scanDefinitionOf(elementType reflect.Type, columnTypes []columnType, copts compileOptions) (scanDefinition, error) {
+	scanDef, err := copts.state.scanDefinitions.getOrCreateSync(elementType, columnTypes, copts)
+	if err != nil {
+		return scanDefinition{}, err
+	}
+	if r := copts.state.coverageRecorder(); r != nil {
+		if err := r.record(elementType, columnTypes, copts); err != nil {
+			return scanDefinition{}, err
+		}
+	}
+	return scanDef, nil
+}
+
+func (st *state) isSmallestStructDecomposition(t reflect.Type) bool {
+	// wrappers like null.String of guregu/null and volatiletech/null implement sql.Scanner with pointer receiver
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
 		return true
 	}
 
-	smallestStructDecompositions.RLock()
-	_, smallest := smallestStructDecompositions.set[t]
-	smallestStructDecompositions.RUnlock()
+	st.smallestStructDecompositions.RLock()
+	_, smallest := st.smallestStructDecompositions.set[t]
+	st.smallestStructDecompositions.RUnlock()
 	return smallest
 }
 
 func elementType(dstType reflect.Type) (reflect.Type, error) {
-	inspection := dstType
-	for {
-		switch inspection.Kind() {
-		case reflect.Slice:
-			if inspection.Elem().Kind() == reflect.Uint8 {
-				return inspection, nil
-			}
-			inspection = inspection.Elem()
-		case reflect.Map, reflect.Chan, reflect.Func, reflect.Invalid, reflect.Interface, reflect.UnsafePointer, reflect.Array:
-			return nil, errors.New("unsupported type: " + dstType.String())
-		default:
-			return inspection, nil
-		}
+	switch dstType.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Invalid, reflect.Interface, reflect.UnsafePointer, reflect.Array:
+		return nil, errors.New("unsupported type: " + dstType.String())
+	default:
+		return dstType, nil
 	}
 }
 
 type fieldAccessor struct {
-	fieldType  reflect.Type
-	fieldIndex []int
+	columnAlias string
+	fieldType   reflect.Type
+	fieldIndex  []int
+	fieldPath   []string
+	options     []string
+	// nested is set for the struct fields which own fields are mapped as well
+	nested bool
+	// convert is set by Mapping, it takes precedence over the converter of the field type
+	convert converter
 }
 
-func createFieldsAccessorsRecursively(columnAliasToAccessor map[string]fieldAccessor, folding []int, inspectionType reflect.Type) error {
+// createFieldsAccessorsRecursively collects accessors of the fields of inspectionType and its nested structs,
+// ancestors are struct types on the way from the root to it, a struct that contains its ancestor is reported as recursive
+func (st *state) createFieldsAccessorsRecursively(columnAliasToAccessor map[string]fieldAccessor, folding []int, path []string, ancestors map[reflect.Type]bool, inspectionType reflect.Type, tagKeys string) error {
 	for {
 		switch inspectionType.Kind() {
 		case reflect.Ptr:
 			inspectionType = inspectionType.Elem()
 
 		case reflect.Struct:
+			if ancestors[inspectionType] {
+				return fmt.Errorf("recursive type: field %s refers to its ancestor %v", strings.Join(path, "."), inspectionType)
+			}
+			ancestors[inspectionType] = true
+			defer delete(ancestors, inspectionType)
+
 			fields := inspectionType.NumField()
 			for i := 0; i < fields; i++ {
 				field := inspectionType.Field(i)
+				columnAlias, options := st.fieldColumnTagByKeys(inspectionType, field, tagKeys)
+				// table, row number, combined and split fields are not mapped to a single column
+				if isTableField(field) || isRowNumberField(field) || st.isCombinedType(field.Type) || isSplitField(options) {
+					continue
+				}
 				fieldKind := field.Type.Kind()
-				if fieldKind == reflect.Struct || // is struct or pointer to struct
-					fieldKind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
-					if err := createFieldsAccessorsRecursively(columnAliasToAccessor, append(folding, i), field.Type); err != nil {
+				nested := !isWholeValueField(options) &&
+					(fieldKind == reflect.Struct && !st.isSmallestStructDecomposition(field.Type) || // is struct or pointer to struct
+						fieldKind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct && !st.isSmallestStructDecomposition(field.Type.Elem()))
+				if nested {
+					if err := st.createFieldsAccessorsRecursively(columnAliasToAccessor, append(folding, i), append(path, field.Name), ancestors, field.Type, tagKeys); err != nil {
 						return err
 					}
 				}
 
-				columnAlias, found := field.Tag.Lookup(dbColumn)
-				if !found {
-					columnAlias = strings.ToLower(field.Name)
+				// copies are required as backing arrays of the folding and path are shared with sibling fields
+				accessor := fieldAccessor{
+					columnAlias: columnAlias,
+					fieldType:   field.Type,
+					fieldIndex:  append(append([]int(nil), folding...), i),
+					fieldPath:   append(append([]string(nil), path...), field.Name),
+					options:     options,
+					nested:      nested,
 				}
-				columnAliasToAccessor[columnAlias] = fieldAccessor{
-					fieldType:  field.Type,
-					fieldIndex: append(folding, i),
+				if existing, found := columnAliasToAccessor[columnAlias]; found && !existing.nested {
+					if !nested {
+						return fmt.Errorf("column/alias %s is mapped to more than one field: %s and %s",
+							columnAlias, strings.Join(existing.fieldPath, "."), strings.Join(accessor.fieldPath, "."))
+					}
+					// field of the nested struct takes precedence over the struct itself
+					continue
 				}
+				columnAliasToAccessor[columnAlias] = accessor
 			}
 			return nil
 		}
 	}
 }
 
-func createFieldsAccessors(dstType reflect.Type) (map[string]fieldAccessor, error) {
-	columnAliasToAccessor := map[string]fieldAccessor{}
-	if err := createFieldsAccessorsRecursively(columnAliasToAccessor, nil, dstType); err != nil {
-		return nil, err
+// visitLeafFields calls visit for the fields of structType and of its nested structs whose own fields are not mapped,
+// structType must not be recursive
+func (st *state) visitLeafFields(structType reflect.Type, folding []int, path []string, visit func(owner reflect.Type, field reflect.StructField, fieldIndex []int, fieldPath []string) error) error {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldIndex := append(append([]int(nil), folding...), i)
+		fieldPath := append(append([]string(nil), path...), field.Name)
+
+		_, options := st.fieldColumnTag(structType, field)
+		nestedType := derefType(field.Type)
+		nested := nestedType.Kind() == reflect.Struct && !st.isSmallestStructDecomposition(nestedType) && !isWholeValueField(options) &&
+			!isRowNumberField(field) && !st.isCombinedType(field.Type) && !isSplitField(options)
+		var err error
+		if nested {
+			err = st.visitLeafFields(nestedType, fieldIndex, fieldPath, visit)
+		} else {
+			err = visit(structType, field, fieldIndex, fieldPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldColumnAlias returns name of the column/alias the field of the owner struct is mapped to
+func (st *state) fieldColumnAlias(owner reflect.Type, field reflect.StructField) string {
+	columnAlias, _ := st.fieldColumnTag(owner, field)
+	return columnAlias
+}
+
+// fieldColumnTag returns name of the column/alias the field of the owner struct is mapped to and the options of the mapping.
+// The tag is a column/alias name optionally followed by comma-separated options: `db_column:"id,key"`.
+// If the name is omitted, lower-cased name of the field is used. The name may refer to the value
+// inside of the JSON column by the path of keys: `db_column:"payload->user->name"`.
+// The mapping registered for the owner with RegisterMapping takes precedence over the tag.
+func (st *state) fieldColumnTag(owner reflect.Type, field reflect.StructField) (string, []string) {
+	return st.fieldColumnTagByKeys(owner, field, "")
+}
+
+// fieldColumnTagByKeys is fieldColumnTag that consults the tags of tagKeys instead of `db_column`, see WithTagKeys
+func (st *state) fieldColumnTagByKeys(owner reflect.Type, field reflect.StructField, tagKeys string) (string, []string) {
+	tag, registered := st.registeredTag(owner, field.Name)
+	if !registered {
+		tag = st.lookupTag(field, tagKeys)
+	}
+	parts := strings.Split(tag, ",")
+	columnAlias, options := parts[0], withTagOptions(field, parts[1:])
+	if columnAlias == "" {
+		columnAlias = strings.ToLower(field.Name)
+	}
+	return columnAlias, options
+}
+
+// withTagOptions appends the values of `db_layout` and `db_scanner` tags of the field to its options,
+// the layout is kept as a whole as it may contain commas, e.g. "Jan 2, 2006"
+func withTagOptions(field reflect.StructField, options []string) []string {
+	if layout, found := field.Tag.Lookup(dbLayout); found {
+		options = append(options, layoutOptionPrefix+layout)
+	}
+	if scanner, found := field.Tag.Lookup(dbScanner); found {
+		options = append(options, scannerOptionPrefix+scanner)
+	}
+	return options
+}
+
+// isWholeValueField returns true if the field receives the whole column value according to its options,
+// so its own fields are not mapped to columns: composite values are stored into the fields of the struct by position
+// and XML values are decoded with encoding/xml
+func isWholeValueField(options []string) bool {
+	return hasOption(options, "composite") || hasOption(options, "xml")
+}
+
+func hasOption(options []string, option string) bool {
+	for _, opt := range options {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *state) createFieldsAccessors(dstType reflect.Type) (map[string]fieldAccessor, error) {
+	return st.createFieldsAccessorsByKeys(dstType, "")
+}
+
+// fieldsAccessorsKey identifies the accessors of the struct mapped by the tags of tagKeys
+type fieldsAccessorsKey struct {
+	dstType reflect.Type
+	tagKeys string
+}
+
+// createFieldsAccessorsByKeys is createFieldsAccessors that maps the fields by the tags of tagKeys, see WithTagKeys.
+// Accessors are cached per type, the caller receives its own copy of the map.
+func (st *state) createFieldsAccessorsByKeys(dstType reflect.Type, tagKeys string) (map[string]fieldAccessor, error) {
+	key := fieldsAccessorsKey{dstType: dstType, tagKeys: tagKeys}
+	st.fieldsAccessors.RLock()
+	cached, found := st.fieldsAccessors.byKey[key]
+	st.fieldsAccessors.RUnlock()
+
+	if !found {
+		cached = map[string]fieldAccessor{}
+		if err := st.createFieldsAccessorsRecursively(cached, nil, nil, map[reflect.Type]bool{}, dstType, tagKeys); err != nil {
+			return nil, err
+		}
+		st.fieldsAccessors.Lock()
+		st.fieldsAccessors.byKey[key] = cached
+		st.fieldsAccessors.Unlock()
+	}
+
+	columnAliasToAccessor := make(map[string]fieldAccessor, len(cached))
+	for columnAlias, accessor := range cached {
+		columnAliasToAccessor[columnAlias] = accessor
 	}
 	return columnAliasToAccessor, nil
 }
 
 type structProvider func() (reflect.Value, error)
 
+// structInitializer initializes nested struct references of the zero struct value
+type structInitializer func(reflect.Value) error
+
 type structProvideManager struct {
-	byType map[reflect.Type]structProvider
+	byType       map[reflect.Type]structProvider
+	initializers map[reflect.Type]structInitializer
+	// st is the state of the mapper the structs are inspected with
+	st *state
 	sync.RWMutex
 }
 
+func newStructProvideManager(st *state) *structProvideManager {
+	return &structProvideManager{
+		byType:       map[reflect.Type]structProvider{},
+		initializers: map[reflect.Type]structInitializer{},
+		st:           st,
+	}
+}
+
+// structProvider returns provider of forType, it isn't kept by the mapper if caching is disabled
+func (copts compileOptions) structProvider(forType reflect.Type) (structProvider, error) {
+	return copts.structProviders().getOrCreateSync(forType)
+}
+
+// structProviders returns the manager of struct providers, a new one is returned if caching is disabled
+func (copts compileOptions) structProviders() *structProvideManager {
+	if copts.noCache {
+		return newStructProvideManager(copts.state)
+	}
+	return copts.state.structProviders
+}
+
 func (tsp *structProvideManager) getOrCreateSync(forType reflect.Type) (provider structProvider, err error) {
 	tsp.RLock()
 	provider, found := tsp.byType[forType]
@@ -188,12 +475,14 @@ func (tsp *structProvideManager) getOrCreateSync(forType reflect.Type) (provider
 	}
 	tsp.RUnlock()
 	tsp.Lock()
-	provider, err = tsp.getOrCreate(forType)
-	tsp.Unlock()
-	return
+	defer tsp.Unlock()
+	return tsp.getOrCreate(forType, map[reflect.Type]bool{})
 }
 
-func (tsp *structProvideManager) getOrCreate(forType reflect.Type) (structProvider, error) {
+// getOrCreate returns provider of forType, ancestors are struct types on the way from the root to it,
+// a struct that contains its ancestor is reported as recursive
+func (tsp *structProvideManager) getOrCreate(forType reflect.Type, ancestors map[reflect.Type]bool) (structProvider, error) {
+	st := tsp.st
 	provider, found := tsp.byType[forType]
 	if found {
 		return provider, nil
@@ -203,21 +492,30 @@ func (tsp *structProvideManager) getOrCreate(forType reflect.Type) (structProvid
 	if err != nil {
 		return nil, err
 	}
+	if ancestors[actualType] {
+		return nil, fmt.Errorf("recursive type: %v contains its ancestor", actualType)
+	}
+	ancestors[actualType] = true
+	defer delete(ancestors, actualType)
 
 	var initActions []func(reflect.Value) error
 	actualValue := reflect.New(actualType).Elem()
 	for i := 0; i < actualValue.NumField(); i++ {
+		// fields that receive the whole column value are initialized by their converters or combiners, table fields are markers
+		if _, options := st.fieldColumnTag(actualType, actualType.Field(i)); isWholeValueField(options) || st.isCombinedType(actualType.Field(i).Type) || isTableField(actualType.Field(i)) {
+			continue
+		}
 		actualValueField := actualValue.Field(i)
 	LoopDetermineField:
 		for ptrNesting := 0; true; ptrNesting++ {
 			actualValueFieldType := actualValueField.Type()
 			switch actualValueField.Kind() {
 			case reflect.Struct:
-				if isSmallestStructDecomposition(actualValueFieldType) {
+				if st.isSmallestStructDecomposition(actualValueFieldType) {
 					break LoopDetermineField
 				}
 
-				provider, err := tsp.getOrCreate(actualValueFieldType)
+				provider, err := tsp.getOrCreate(actualValueFieldType, ancestors)
 				if err != nil {
 					return nil, err
 				}
@@ -247,22 +545,37 @@ func (tsp *structProvideManager) getOrCreate(forType reflect.Type) (structProvid
 		}
 	}
 
-	provider = func() (reflect.Value, error) {
-		holderValue := reflect.New(actualType).Elem()
+	initializer := func(holderValue reflect.Value) error {
 		for _, initAction := range initActions {
 			if err := initAction(holderValue); err != nil {
-				return reflect.Value{}, err
+				return err
 			}
 		}
+		return nil
+	}
+
+	provider = func() (reflect.Value, error) {
+		holderValue := reflect.New(actualType).Elem()
+		if err := initializer(holderValue); err != nil {
+			return reflect.Value{}, err
+		}
 		for ptrNesting := ptrDepth; ptrNesting > 0; ptrNesting-- {
 			holderValue = holderValue.Addr()
 		}
 		return holderValue, nil
 	}
 	tsp.byType[forType] = provider
+	tsp.initializers[actualType] = initializer
 	return provider, nil
 }
 
+func (tsp *structProvideManager) initializer(actualType reflect.Type) structInitializer {
+	tsp.RLock()
+	initializer := tsp.initializers[actualType]
+	tsp.RUnlock()
+	return initializer
+}
+
 func unwrapPtrStructType(wrapped reflect.Type) (reflect.Type, int, error) {
 	actualType := wrapped
 	levels := 0
@@ -295,7 +608,7 @@ func unwrapPtrStructValue(wrapped reflect.Value) (reflect.Value, int, error) {
 	}
 }
 
-func isSingleBasicType(dstType reflect.Type) bool {
+func (st *state) isSingleBasicType(dstType reflect.Type) bool {
 	for {
 		switch dstType.Kind() {
 		case reflect.Ptr:
@@ -310,87 +623,242 @@ func isSingleBasicType(dstType reflect.Type) bool {
 		case reflect.Slice:
 			return dstType.Elem().Kind() == reflect.Uint8
 		case reflect.Struct:
-			return isSmallestStructDecomposition(dstType)
+			return st.isSmallestStructDecomposition(dstType)
 		default:
 			return false
 		}
 	}
 }
 
-func singleColumnMapper(forType reflect.Type) func(dst interface{}, rows *sql.Rows) error {
-	return func(holder interface{}, rows *sql.Rows) error {
-		inject, err := prepareInjector(holder)
-		if err != nil {
-			rows.Close()
-			return err
-		}
-		for rows.Next() {
+func singleColumnScanner(forType reflect.Type, columnTypes []columnType, copts compileOptions) func(unmappedColumnFunc) rowScanner {
+	var columnName string
+	convert := copts.converter(forType, nil)
+	if len(columnTypes) > 0 {
+		columnName = columnTypes[0].Name()
+		convert = copts.columnConverter(columnTypes[0], forType, nil)
+	}
+
+	return func(unmappedColumnFunc) rowScanner {
+		return func(rows *sql.Rows) (reflect.Value, error) {
 			holderElement := reflect.New(forType)
-			err := rows.Scan(holderElement.Interface())
-			if err != nil {
-				return err
+			var holder interface{} = holderElement.Interface()
+			if convert != nil {
+				holder = &fieldScanner{column: columnName, field: holderElement.Elem(), convert: convert}
 			}
-			inject(holderElement.Elem())
-		}
-		if err := rows.Err(); err != nil {
-			return err
+			if err := rows.Scan(holder); err != nil {
+				return reflect.Value{}, err
+			}
+			return holderElement.Elem(), nil
 		}
-		return rows.Close()
 	}
 }
 
-func createHolderSuppliers(dstType reflect.Type, columnTypes []*sql.ColumnType) (holderSuppliers []holderSupplier, err error) {
-	columnAliasToAccessor, err := createFieldsAccessors(dstType)
+// columnAccessors returns accessors of the fields of dstType each column is mapped to, Mapping is applied if it is for dstType.
+// The column is mapped to several fields if their tags refer to the values inside of the JSON column, see splitJSONPath
+func (copts compileOptions) columnAccessors(dstType reflect.Type, columnTypes []columnType) ([][]fieldAccessor, error) {
+	if copts.mapping == nil || copts.mapping.structType != derefType(dstType) {
+		return copts.state.columnAccessors(dstType, columnTypes, copts.tagKeys)
+	}
+
+	columnAliasToAccessor, err := copts.mapping.columnAliasToAccessor(copts.state, copts.tagKeys)
 	if err != nil {
 		return nil, err
 	}
+	return matchColumnAccessors(columnAliasToAccessor, columnTypes), nil
+}
+
+func (st *state) columnAccessors(dstType reflect.Type, columnTypes []columnType, tagKeys string) ([][]fieldAccessor, error) {
+	// plans are kept only for the mapping by the default tag
+	if tagKeys != "" {
+		columnAliasToAccessor, err := st.createFieldsAccessorsByKeys(dstType, tagKeys)
+		if err != nil {
+			return nil, err
+		}
+		return matchColumnAccessors(columnAliasToAccessor, columnTypes), nil
+	}
 
-	camtChk := strictColumnAmountCheck()
-	ctChk := strictColumnTypeCheck()
+	if accessors, found := st.plans.accessors(dstType, columnTypes); found {
+		return accessors, nil
+	}
 
-	for _, columnType := range columnTypes {
-		accessor, found := columnAliasToAccessor[strings.ToLower(columnType.Name())]
-		if found {
-			if ctChk && columnType.ScanType() != accessor.fieldType {
-				return nil, fmt.Errorf("value for column/alias: %v can't be stored into the type: %v; required type: %v", columnType.Name(), accessor.fieldType, columnType.ScanType())
+	columnAliasToAccessor, err := st.createFieldsAccessors(dstType)
+	if err != nil {
+		return nil, err
+	}
+
+	accessors := matchColumnAccessors(columnAliasToAccessor, columnTypes)
+	st.plans.record(dstType, columnTypes, accessors)
+	return accessors, nil
+}
+
+// matchColumnAccessors groups accessors by the columns, accessors of the column are ordered by declaration of the fields
+func matchColumnAccessors(columnAliasToAccessor map[string]fieldAccessor, columnTypes []columnType) [][]fieldAccessor {
+	columnToAccessors := map[string][]fieldAccessor{}
+	for columnAlias, accessor := range columnAliasToAccessor {
+		column, _ := splitJSONPath(columnAlias)
+		columnToAccessors[column] = append(columnToAccessors[column], accessor)
+	}
+
+	accessors := make([][]fieldAccessor, len(columnTypes))
+	for i, columnType := range columnTypes {
+		accessors[i] = columnToAccessors[strings.ToLower(columnType.Name())]
+		sort.Slice(accessors[i], func(j, k int) bool {
+			return lessIndex(accessors[i][j].fieldIndex, accessors[i][k].fieldIndex)
+		})
+	}
+	return accessors
+}
+
+// createHolderSuppliers returns suppliers of the holders of the columns, the combiners of the fields,
+// the positions of the columns without mapping and the details of the fields stored by database/sql,
+// the details are returned only if the errors are reported with FieldError
+func createHolderSuppliers(dstType reflect.Type, columnTypes []columnType, copts compileOptions) (holderSuppliers []holderSupplier, fieldCombiners []fieldCombiner, unmapped []int, sqlFields []*fieldDetails, err error) {
+	accessors, err := copts.columnAccessors(dstType, columnTypes)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	fieldCombiners, err = copts.state.createFieldCombiners(dstType, columnTypes)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	combined := map[int]bool{}
+	for _, fc := range fieldCombiners {
+		for _, position := range fc.positions {
+			combined[position] = true
+		}
+	}
+	columnToSplit, err := copts.fieldSplits(dstType)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	for i, columnType := range columnTypes {
+		// values of the combined columns are kept for the combiners, see fieldCombiner.combine
+		if combined[i] {
+			holderSuppliers = append(holderSuppliers, holderSkipColumn)
+			continue
+		}
+		if fs, found := columnToSplit[strings.ToLower(columnType.Name())]; found {
+			holderSuppliers = append(holderSuppliers, holderBySplit(columnType.Name(), fs, copts))
+			continue
+		}
+
+		// setters are used for columns of unexported or absent fields
+		if !isSettableAccessor(dstType, accessors[i]) {
+			if setter, found := setterOf(dstType, columnType.Name()); found {
+				convert := copts.columnConverter(columnType, setter.Type.In(1), nil)
+				if convert == nil {
+					convert = convertReference(convertDefault)
+				}
+				holderSuppliers = append(holderSuppliers, holderBySetter(columnType.Name(), setter, convert))
+				continue
 			}
-			holderSuppliers = append(holderSuppliers, holderByFieldIndexPath(accessor.fieldIndex))
-		} else {
-			if camtChk {
-				return nil, errors.New("no mapping exists for column/alias: " + columnType.Name())
+		}
+
+		switch {
+		case len(accessors[i]) == 0:
+			if copts.columnAmountCheck {
+				return nil, nil, nil, nil, errors.New("no mapping exists for column/alias: " + columnType.Name())
 			}
-			holderSuppliers = append(holderSuppliers, holderSkipColumn)
+			holderSuppliers = append(holderSuppliers, copts.holderSkip())
+			unmapped = append(unmapped, i)
+
+		case len(accessors[i]) == 1 && accessors[i][0].fieldType == blobSinkType:
+			if !reflect.PtrTo(derefType(dstType)).Implements(blobOpenerType) {
+				return nil, nil, nil, nil, fmt.Errorf("%v has field of BlobSink type for column/alias: %v, but doesn't implement BlobOpener", derefType(dstType), columnType.Name())
+			}
+			holderSuppliers = append(holderSuppliers, holderBlobSink(columnType.Name(), accessors[i][0].fieldIndex))
+
+		case len(accessors[i]) == 1 && !strings.Contains(accessors[i][0].columnAlias, jsonPathSeparator):
+			accessor := accessors[i][0]
+			if copts.columnTypeCheck && columnType.ScanType() != accessor.fieldType {
+				return nil, nil, nil, nil, fmt.Errorf("value for column/alias: %v can't be stored into the type: %v; required type: %v", columnType.Name(), accessor.fieldType, columnType.ScanType())
+			}
+			// converter of Mapping takes precedence over the converter of the field type
+			convert := copts.columnConverter(columnType, accessor.fieldType, accessor.options)
+			if accessor.convert != nil {
+				convert = copts.intercepted(columnType.Name(), accessor.convert)
+			}
+			if copts.fieldErrors != fieldErrorsOff {
+				details := &fieldDetails{
+					fieldPath: accessor.fieldPath,
+					converter: copts.converterName(columnType, accessor.fieldType, accessor.options, accessor.convert),
+					redacted:  copts.fieldErrors == fieldErrorsRedacted,
+				}
+				if convert == nil {
+					// the value is still stored by database/sql, its error is completed with the details by the row scanner
+					if sqlFields == nil {
+						sqlFields = make([]*fieldDetails, len(columnTypes))
+					}
+					sqlFields[i] = details
+					holderSuppliers = append(holderSuppliers, holderByFieldIndexPath(accessor.fieldIndex))
+					continue
+				}
+				holderSuppliers = append(holderSuppliers, holderDetailedByFieldIndexPath(columnType.Name(), accessor.fieldIndex, convert, details))
+				continue
+			}
+			if convert != nil {
+				holderSuppliers = append(holderSuppliers, holderConvertedByFieldIndexPath(columnType.Name(), accessor.fieldIndex, convert))
+			} else {
+				holderSuppliers = append(holderSuppliers, holderByFieldIndexPath(accessor.fieldIndex))
+			}
+
+		default:
+			holderSuppliers = append(holderSuppliers, holderByJSONPaths(columnType.Name(), accessors[i], copts))
 		}
 	}
 	return
 }
 
-func multiColumnMapper(holderElementType reflect.Type, columnTypes []*sql.ColumnType) (rowsMapper, error) {
-	holderSuppliers, err := createHolderSuppliers(holderElementType, columnTypes)
+func multiColumnScanner(holderElementType reflect.Type, columnTypes []columnType, copts compileOptions) (func(unmappedColumnFunc) rowScanner, error) {
+	holderSuppliers, fieldCombiners, unmapped, sqlFields, err := createHolderSuppliers(holderElementType, columnTypes, copts)
 	if err != nil {
 		return nil, err
 	}
 
-	provider, err := structProviderMgr.getOrCreateSync(holderElementType)
+	providers := copts.structProviders()
+	provider, err := providers.getOrCreateSync(holderElementType)
 	if err != nil {
 		return nil, err
 	}
 
-	return func(holder interface{}, rows *sql.Rows) error {
-		inject, err := prepareInjector(holder)
-		if err != nil {
-			return err
+	rowNumbers, err := rowNumberFields(holderElementType)
+	if err != nil {
+		return nil, err
+	}
+
+	var pooledProvider structProvider
+	var poolable bool
+	if !copts.noCache {
+		pooledProvider, poolable = copts.state.structPools.provider(holderElementType, providers)
+	}
+	var initializer structInitializer
+	if copts.allocationBlock > 0 {
+		actualType, _, _ := unwrapPtrStructType(holderElementType)
+		initializer = providers.initializer(actualType)
+	}
+
+	return func(onUnmapped unmappedColumnFunc) rowScanner {
+		holderSuppliers := withUnmappedColumns(holderSuppliers, unmapped, columnTypes, onUnmapped)
+		provider := provider
+		switch {
+		case poolable && copts.state.structPoolingEnabled():
+			provider = pooledProvider
+		case initializer != nil:
+			provider = blockProvider(holderElementType, copts.allocationBlock, initializer)
 		}
+		var rowNumber int64
 
-		for rows.Next() {
+		return func(rows *sql.Rows) (reflect.Value, error) {
 			holderElement, err := provider()
 			if err != nil {
-				return err
+				return reflect.Value{}, err
 			}
 
 			underlyingValue, _, err := unwrapPtrStructValue(holderElement)
 			if err != nil {
-				return err
+				return reflect.Value{}, err
 			}
 
 			holderElementFields := make([]interface{}, len(holderSuppliers))
@@ -399,20 +867,37 @@ func multiColumnMapper(holderElementType reflect.Type, columnTypes []*sql.Column
 			}
 
 			if err := rows.Scan(holderElementFields...); err != nil {
-				return err
+				if sqlFields != nil {
+					err = sqlFieldError(rows, holderElementFields, columnTypes, sqlFields, err)
+				}
+				return reflect.Value{}, err
 			}
-
-			inject(holderElement)
+			for _, fc := range fieldCombiners {
+				if err := fc.combine(underlyingValue, holderElementFields); err != nil {
+					return reflect.Value{}, err
+				}
+			}
+			rowNumber++
+			setRowNumber(underlyingValue, rowNumbers, rowNumber)
+			return holderElement, nil
 		}
-		return rows.Err()
 	}, nil
 }
 
-func createRowsMapper(holderElementType reflect.Type, columnTypes []*sql.ColumnType) (rowsMapper, error) {
-	if isSingleBasicType(holderElementType) {
-		return singleColumnMapper(holderElementType), nil
+func createRowScanner(holderElementType reflect.Type, columnTypes []columnType, copts compileOptions) (func(unmappedColumnFunc) rowScanner, error) {
+	if isRowScannerType(derefType(holderElementType)) {
+		return delegatingScanner(holderElementType, columnTypes), nil
+	}
+	if c, found := copts.state.constructorOf(derefType(holderElementType)); found {
+		return constructorScanner(holderElementType, c, columnTypes, copts)
+	}
+	if isTupleType(derefType(holderElementType)) {
+		return tupleScanner(holderElementType, columnTypes, copts)
+	}
+	if copts.state.isSingleBasicType(holderElementType) {
+		return singleColumnScanner(holderElementType, columnTypes, copts), nil
 	}
-	return multiColumnMapper(holderElementType, columnTypes)
+	return multiColumnScanner(holderElementType, columnTypes, copts)
 }
 
 type holderSupplier func(underlyingValue reflect.Value) interface{}
@@ -423,96 +908,128 @@ func holderByFieldIndexPath(holderIndexPath []int) holderSupplier {
 	}
 }
 
+func holderConvertedByFieldIndexPath(column string, holderIndexPath []int, convert converter) holderSupplier {
+	return func(underlyingValue reflect.Value) interface{} {
+		return &fieldScanner{column: column, field: underlyingValue.FieldByIndex(holderIndexPath), convert: convert}
+	}
+}
+
 func holderSkipColumn(underlyingValue reflect.Value) (skip interface{}) { return &skip }
 
-func prepareInjector(holder interface{}) (func(value reflect.Value), error) {
-	dstHolderType := reflect.TypeOf(holder)
-	dstHolderValue := reflect.ValueOf(holder)
-	for {
-		switch dstHolderType.Kind() {
-		case reflect.Ptr:
-			dstHolderType = dstHolderType.Elem()
-			dstHolderValue = dstHolderValue.Elem()
-		case reflect.Slice:
-			return func(value reflect.Value) {
-				newSlice := reflect.Append(dstHolderValue, value)
-				dstHolderValue.Set(newSlice)
-			}, nil
+// holderSkipRawColumn skips the column without copying its value, see WithRawBytes
+func holderSkipRawColumn(underlyingValue reflect.Value) interface{} { return new(sql.RawBytes) }
 
-			//case reflect.Map:
-			//	return errors.New("not implemented: holder for map")
-		default:
-			return nil, errors.New("not implemented: holder for type: " + dstHolderType.Name())
-		}
+func (copts compileOptions) holderSkip() holderSupplier {
+	if copts.rawBytes {
+		return holderSkipRawColumn
 	}
+	return holderSkipColumn
 }
 
+// rowsMapper maps all rows into dst
 type rowsMapper func(dst interface{}, rows *sql.Rows) error
 
+// rowScanner scans the current row into a new element
+type rowScanner func(rows *sql.Rows) (reflect.Value, error)
+
 type scanDefinition struct {
-	columnTypes []*sql.ColumnType
-	mapper      rowsMapper
+	columnTypes []columnType
+	// scanner creates row scanner for a single propagation, it is set for definitions of elements
+	scanner func(onUnmapped unmappedColumnFunc) rowScanner
+	// mapper maps all rows at once, it is set for definitions of columnar destinations
+	mapper rowsMapper
+}
+
+// definitionKey identifies scan definitions compiled for the same element type with the same options
+type definitionKey struct {
+	elementType reflect.Type
+	options     compileOptions
 }
 
 type scanDefinitionsManager struct {
-	byType map[reflect.Type][]scanDefinition
+	// byKey holds definitions compiled for the same key by the signature of the columns
+	byKey   map[definitionKey]*definitionsLRU
+	compile func(elementType reflect.Type, columnTypes []columnType, copts compileOptions) (scanDefinition, error)
 	sync.RWMutex
 }
 
-func (sdm *scanDefinitionsManager) getOrCreateSync(elementType reflect.Type, columnTypes []*sql.ColumnType) (scanDefinition, error) {
+// sharable returns false if the definitions compiled with copts must not be kept in the cache shared by propagations:
+// each created option with the function, such as WithColumnInterceptor, would be a new key of the cache
+func (copts compileOptions) sharable() bool {
+	return !copts.noCache && copts.interceptor == nil && copts.missingField == nil
+}
+
+func (sdm *scanDefinitionsManager) getOrCreateSync(elementType reflect.Type, columnTypes []columnType, copts compileOptions) (scanDefinition, error) {
+	if !copts.sharable() {
+		scanDef, err := sdm.compile(elementType, columnTypes, copts)
+		scanDef.columnTypes = columnTypes
+		return scanDef, err
+	}
+
 	var scanDef scanDefinition
 	var found bool
 
+	key := definitionKey{elementType: elementType, options: copts}
+	signature := columnsSignature(columnTypes)
 	sdm.RLock()
-	scanDef, found = sdm.find(elementType, columnTypes)
+	scanDef, found = sdm.find(key, signature, columnTypes)
 	sdm.RUnlock()
 
 	if found {
 		return scanDef, nil
 	}
 
+	// unlock is deferred, so a panic of the compilation doesn't leave the manager locked
 	sdm.Lock()
-	if scanDef, found = sdm.find(elementType, columnTypes); found {
-		sdm.Unlock()
+	defer sdm.Unlock()
+	if scanDef, found = sdm.find(key, signature, columnTypes); found {
 		return scanDef, nil
 	}
-
-	scanDef, err := sdm.create(elementType, columnTypes)
-	sdm.Unlock()
-	return scanDef, err
+	return sdm.create(key, signature, columnTypes)
 }
 
-func (sdm *scanDefinitionsManager) find(elementType reflect.Type, columnTypes []*sql.ColumnType) (scanDefinition, bool) {
-	scanDefs, found := sdm.byType[elementType]
-	if !found {
+func (sdm *scanDefinitionsManager) find(key definitionKey, signature string, columnTypes []columnType) (scanDefinition, bool) {
+	lru := sdm.byKey[key]
+	entry, found := lru.get(signature)
+	if !found || len(entry.scanDef.columnTypes) != len(columnTypes) {
 		return scanDefinition{}, false
 	}
 
-LoopScanDef:
-	for _, scanDef := range scanDefs {
-		if len(scanDef.columnTypes) != len(columnTypes) {
-			continue
-		}
-
-		for i := 0; i < len(scanDef.columnTypes); i++ {
-			if *scanDef.columnTypes[i] != *columnTypes[i] {
-				continue LoopScanDef
-			}
+	// the signature doesn't cover everything sql.ColumnType holds, so the columns are compared as well,
+	// the definition of the columns differing only in that is replaced by the new one on create
+	for i := 0; i < len(columnTypes); i++ {
+		if !sameColumnType(entry.scanDef.columnTypes[i], columnTypes[i]) {
+			return scanDefinition{}, false
 		}
-
-		return scanDef, true
 	}
 
-	return scanDefinition{}, false
+	lru.touch(signature)
+	return entry.scanDef, true
 }
 
-func (sdm *scanDefinitionsManager) create(elementType reflect.Type, columnTypes []*sql.ColumnType) (scanDefinition, error) {
-	mapper, err := createRowsMapper(elementType, columnTypes)
+func (sdm *scanDefinitionsManager) create(key definitionKey, signature string, columnTypes []columnType) (scanDefinition, error) {
+	scanDef, err := sdm.compile(key.elementType, columnTypes, key.options)
 	if err != nil {
 		return scanDefinition{}, err
 	}
 
-	scanDef := scanDefinition{mapper: mapper, columnTypes: columnTypes}
-	sdm.byType[elementType] = append(sdm.byType[elementType], scanDef)
+	scanDef.columnTypes = columnTypes
+	lru, found := sdm.byKey[key]
+	if !found {
+		lru = newDefinitionsLRU()
+		sdm.byKey[key] = lru
+	}
+	lru.add(&definitionEntry{signature: signature, scanDef: scanDef}, key.options.state.scanDefinitionsLimit())
 	return scanDef, nil
 }
+
+func createScanDefinition(elementType reflect.Type, columnTypes []columnType, copts compileOptions) (scanDefinition, error) {
+	scanner, err := createRowScanner(elementType, columnTypes, copts)
+	if err != nil {
+		return scanDefinition{}, err
+	}
+	if err := copts.checkMissingFields(elementType, columnTypes); err != nil {
+		return scanDefinition{}, err
+	}
+	return scanDefinition{scanner: scanner}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -186,8 +187,8 @@ func TestPropagate(t *testing.T) {
 			retrieval: "SELECT id, col1 FROM propagation ORDER BY id",
 			action: func(rows *sql.Rows) func(t *testing.T) {
 				return func(t *testing.T) {
-					if driverName() == postgres {
-						t.Skip("postgres driver doesn't support `type Col1 []byte` types as embedded fields: " +
+					if driverName() == "postgres" || driverName() == "sqlite3" {
+						t.Skip(driverName() + " driver doesn't support `type Col1 []byte` types as embedded fields: " +
 							"sql: Scan error on column index 1: unsupported Scan, storing driver.Value type string into type *main.Col1")
 					}
 					type Col1 []byte
@@ -213,8 +214,8 @@ func TestPropagate(t *testing.T) {
 			retrieval: "SELECT id, col1 FROM propagation ORDER BY id",
 			action: func(rows *sql.Rows) func(t *testing.T) {
 				return func(t *testing.T) {
-					if driverName() == postgres {
-						t.Skip("postgres driver doesn't support `type Col1 []byte` types as embedded fields: " +
+					if driverName() == "postgres" || driverName() == "sqlite3" {
+						t.Skip(driverName() + " driver doesn't support `type Col1 []byte` types as embedded fields: " +
 							"sql: Scan error on column index 1: unsupported Scan, storing driver.Value type string into type *main.Col1")
 					}
 					type Col1 []byte
@@ -426,6 +427,83 @@ func TestPropagate(t *testing.T) {
 	}
 }
 
+// queryPropagation creates and fills the temporary propagation table inside of a new transaction
+// and returns rows of the retrieval query; returned function releases all acquired resources
+func queryPropagation(t *testing.T, insert, retrieval string) (*sql.Rows, func()) {
+	txCtx, txTimeout := context.WithTimeout(context.Background(), 5*time.Second)
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		txTimeout()
+		t.Fatal(err)
+	}
+	release := func() {
+		tx.Rollback()
+		txTimeout()
+	}
+
+	if _, err := tx.ExecContext(txCtx, ddlCreateTestTempTable()); err != nil {
+		release()
+		t.Fatal(err)
+	}
+
+	if _, err := tx.ExecContext(txCtx, insert); err != nil {
+		release()
+		t.Fatal(err)
+	}
+
+	rows, err := tx.QueryContext(txCtx, retrieval)
+	if err != nil {
+		release()
+		t.Fatal(err)
+	}
+	return rows, func() {
+		rows.Close()
+		release()
+	}
+}
+
+func TestPropagateSets(t *testing.T) {
+	if driverName() == "sqlite3" {
+		t.Skip("sqlite3 driver returns only the result set of the first statement")
+	}
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id FROM propagation ORDER BY id; SELECT id, col1, col2 FROM propagation ORDER BY id DESC",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+		COL2 *string
+	}
+	var ids []int
+	var valStructs []valStruct
+	if err := PropagateSets(rows, &ids, &valStructs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("unexpeted results of propagation: %v", ids)
+	}
+	exp := []valStruct{{Id: 2, Col1: "b", COL2: StringRef("c")}, {Id: 1, Col1: "a"}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestPropagateSetsNotEnoughResultSets(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	var first, second []int
+	if err := PropagateSets(rows, &first, &second); err == nil {
+		t.Error("error expected for missing result set")
+	}
+}
+
 // StrictColumnTypeCheck
 
 func StringRef(val string) *string {
@@ -449,3 +527,163 @@ type mlevel3 struct {
 type mrefStruct struct {
 	With *mlevel3
 }
+
+// nullString mimics wrappers of guregu/null and volatiletech/null: sql.Scanner is implemented with pointer receiver
+type nullString struct {
+	sql.NullString
+}
+
+func TestPropagateNullWrappers(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id, col2, col1 AS valid FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col2 nullString
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{
+		{Id: 1},
+		{Id: 2, Col2: nullString{sql.NullString{String: "c", Valid: true}}},
+	}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+
+	var col2s []nullString
+	if !Default().state.isSingleBasicType(reflect.TypeOf(col2s).Elem()) {
+		t.Error("wrapper with pointer receiver scanner must be scanned as a single column")
+	}
+}
+
+func TestStrictColumnAmountCheckAfterCompilation(t *testing.T) {
+	type valStruct struct {
+		Id int
+	}
+	propagate := func() error {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+			"SELECT id, col1 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		return Propagate(&valStructs, rows)
+	}
+
+	if err := propagate(); err != nil {
+		t.Fatal(err)
+	}
+
+	StrictColumnAmountCheck(true)
+	defer StrictColumnAmountCheck(false)
+	if err := propagate(); err == nil {
+		t.Error("error expected for column without mapping once strict check is enabled")
+	}
+}
+
+func TestDuplicateColumnAlias(t *testing.T) {
+	type audit struct {
+		Id      int `db_column:"created_by"`
+		Updated string
+	}
+	type valStruct struct {
+		CreatedBy int `db_column:"created_by"`
+		Audit     *audit
+	}
+
+	_, err := Default().state.createFieldsAccessors(reflect.TypeOf(valStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "Audit.Id") || !strings.Contains(err.Error(), "CreatedBy") {
+		t.Errorf("error with both field paths expected, actual: %v", err)
+	}
+
+	if _, err := Default().state.createFieldsAccessors(reflect.TypeOf(mrefStruct{})); err != nil {
+		t.Errorf("aliases of nested structs are not expected to conflict: %v", err)
+	}
+}
+
+func TestPropagateAndClose(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var ids []int
+	if err := PropagateAndClose(&ids, rows); err == nil {
+		t.Fatal("error expected for non-numeric values")
+	}
+	if rows.Next() {
+		t.Error("rows are expected to be closed")
+	}
+}
+
+func TestPropagateWithCloseRows(t *testing.T) {
+	type valStruct struct {
+		Col1 int
+	}
+	type columnar struct {
+		Col1 []int
+	}
+
+	for name, dst := range map[string]interface{}{"struct": &[]valStruct{}, "columnar": &columnar{}} {
+		t.Run(name, func(t *testing.T) {
+			rows, release := queryPropagation(t,
+				"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+				"SELECT col1 FROM propagation ORDER BY id",
+			)
+			defer release()
+
+			if err := Propagate(dst, rows, WithCloseRows(true)); err == nil {
+				t.Fatal("error expected for non-numeric values")
+			}
+			if rows.Next() {
+				t.Error("rows are expected to be closed")
+			}
+		})
+	}
+}
+
+func TestPropagateLeavesRowsOpen(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var ids []int
+	if err := Propagate(&ids, rows); err == nil {
+		t.Fatal("error expected for non-numeric values")
+	}
+	if !rows.Next() {
+		t.Error("rows are expected to be left open")
+	}
+}
+
+type category struct {
+	Id     int
+	Col1   string
+	Parent *category
+}
+
+func TestPropagateRecursiveType(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var categories []category
+	if err := Propagate(&categories, rows); err == nil || !strings.Contains(err.Error(), "recursive type") {
+		t.Errorf("error of the recursive type expected, actual: %v", err)
+	}
+	if _, err := Default().state.structProviders.getOrCreateSync(reflect.TypeOf(category{})); err == nil || !strings.Contains(err.Error(), "recursive type") {
+		t.Errorf("error of the recursive type expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Queryer executes queries that return rows; it is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Select executes query with args using q and propagates all returned rows into dst, the same as Select of sqlx.
+// dst has the same requirements as for Propagate and options attached to ctx with WithContextOptions are applied.
+// dst is left untouched if there are no rows. Rows are always closed before return.
+func Select(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	return Default().Select(ctx, q, dst, query, args...)
+}
+
+// Select is the same as Select of the package with the options of the mapper
+func (m *Mapper) Select(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err := m.PropagateContext(ctx, dst, rows, withQuery(query)); err != nil {
+		rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// errFirstRowPropagated stops the propagation once the first row is propagated by Get
+var errFirstRowPropagated = errors.New("first row is propagated")
+
+// Get executes query with args using q and propagates the first returned row into dst, the same as Get of sqlx:
+// dst is a pointer to the struct or to the basic value, sql.ErrNoRows is returned if there are no rows
+// and the rest of the rows are discarded. Options attached to ctx with WithContextOptions are applied.
+// Rows are always closed before return.
+func Get(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	return Default().Get(ctx, q, dst, query, args...)
+}
+
+// Get is the same as Get of the package with the options of the mapper
+func (m *Mapper) Get(ctx context.Context, q Queryer, dst interface{}, query string, args ...interface{}) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("non-nil pointer is expected, received: %T", dst)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	sink := &firstRowSink{dst: dstValue.Elem()}
+	opts := append(contextOptions(ctx), withQuery(query))
+	if err := m.PropagateSink(sink, dstValue.Type().Elem(), rows, opts...); err != nil && err != errFirstRowPropagated {
+		rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if !sink.propagated {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// firstRowSink stores the first value into dst and stops the propagation
+type firstRowSink struct {
+	dst        reflect.Value
+	propagated bool
+}
+
+func (frs *firstRowSink) Add(v reflect.Value) error {
+	frs.dst.Set(v)
+	frs.propagated = true
+	return errFirstRowPropagated
+}
+
+func (frs *firstRowSink) Flush() error { return nil }
@@ -0,0 +1,89 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	_ Queryer = (*sql.DB)(nil)
+	_ Queryer = (*sql.Tx)(nil)
+	_ Queryer = (*sql.Conn)(nil)
+)
+
+func TestSelect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c')"); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	var valStructs []valStruct
+	if err := Select(ctx, tx, &valStructs, "SELECT id, col1 FROM propagation WHERE id > 1 ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 2, Col1: "b"}, {Id: 3, Col1: "c"}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+
+	var ids []int
+	if err := Select(ctx, tx, ids, "SELECT id FROM propagation"); err == nil {
+		t.Error("error expected for non-pointer destination")
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')"); err != nil {
+		t.Fatal(err)
+	}
+
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	var v valStruct
+	if err := Get(ctx, tx, &v, "SELECT id, col1 FROM propagation ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := (valStruct{Id: 1, Col1: "a"}); v != exp {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, v)
+	}
+
+	var id int
+	if err := Get(ctx, tx, &id, "SELECT id FROM propagation WHERE id > 1"); err != nil || id != 2 {
+		t.Errorf("unexpeted results of propagation: %d, error: %v", id, err)
+	}
+	if err := Get(ctx, tx, &id, "SELECT id FROM propagation WHERE id > 2"); err != sql.ErrNoRows {
+		t.Errorf("sql.ErrNoRows expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package rowconv
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RangeBounds describes which bounds of the Range are inclusive or unbounded, and if the range is empty
+type RangeBounds uint8
+
+const (
+	// RangeLowerInclusive is set if the lower bound is included into the range: [lower,...
+	RangeLowerInclusive RangeBounds = 1 << iota
+	// RangeUpperInclusive is set if the upper bound is included into the range: ...,upper]
+	RangeUpperInclusive
+	// RangeLowerUnbounded is set if the range has no lower bound or it is -infinity: (,...
+	RangeLowerUnbounded
+	// RangeUpperUnbounded is set if the range has no upper bound or it is infinity: ...,)
+	RangeUpperUnbounded
+	// RangeEmpty is set for the range that contains no values
+	RangeEmpty
+)
+
+// Range holds a value of PostgreSQL range column, such as int4range, numrange or tstzrange: `Range[time.Time]`.
+// Lower and Upper are zero values if the corresponding bound is unbounded or the range is empty.
+type Range[T any] struct {
+	Lower  T
+	Upper  T
+	Bounds RangeBounds
+}
+
+// IsEmpty returns true if the range contains no values
+func (r Range[T]) IsEmpty() bool {
+	return r.Bounds&RangeEmpty != 0
+}
+
+// Scan implements sql.Scanner for textual representation of the range: `[1,10)`, `(,5]` or `empty`
+func (r *Range[T]) Scan(src interface{}) error {
+	*r = Range[T]{}
+	if src == nil {
+		return fmt.Errorf("converting NULL to %T is unsupported", r)
+	}
+
+	text := strings.TrimSpace(asString(src))
+	if strings.EqualFold(text, "empty") {
+		r.Bounds = RangeEmpty
+		return nil
+	}
+	if len(text) < 2 || !strings.ContainsRune("[(", rune(text[0])) || !strings.ContainsRune("])", rune(text[len(text)-1])) {
+		return &ParseError{Value: text, Type: reflect.TypeOf(r).Elem(), Err: errors.New("range must be enclosed in brackets or parentheses")}
+	}
+	if text[0] == '[' {
+		r.Bounds |= RangeLowerInclusive
+	}
+	if text[len(text)-1] == ']' {
+		r.Bounds |= RangeUpperInclusive
+	}
+
+	// bounds are quoted and escaped the same way as attributes of the composite value
+	bounds, err := parseComposite("(" + text[1:len(text)-1] + ")")
+	if err == nil && len(bounds) != 2 {
+		err = fmt.Errorf("2 bounds are expected, found: %d", len(bounds))
+	}
+	if err != nil {
+		return &ParseError{Value: text, Type: reflect.TypeOf(r).Elem(), Err: err}
+	}
+
+	if bounds[0] == nil || *bounds[0] == "-infinity" {
+		r.Bounds = r.Bounds&^RangeLowerInclusive | RangeLowerUnbounded
+	} else if err := parseRangeBound(*bounds[0], reflect.ValueOf(&r.Lower).Elem()); err != nil {
+		return err
+	}
+	if bounds[1] == nil || *bounds[1] == "infinity" {
+		r.Bounds = r.Bounds&^RangeUpperInclusive | RangeUpperUnbounded
+	} else if err := parseRangeBound(*bounds[1], reflect.ValueOf(&r.Upper).Elem()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, the range is stored in the textual representation
+func (r Range[T]) Value() (driver.Value, error) {
+	if r.IsEmpty() {
+		return "empty", nil
+	}
+
+	var text strings.Builder
+	if r.Bounds&RangeLowerInclusive != 0 {
+		text.WriteByte('[')
+	} else {
+		text.WriteByte('(')
+	}
+	if r.Bounds&RangeLowerUnbounded == 0 {
+		text.WriteString(formatRangeBound(r.Lower))
+	}
+	text.WriteByte(',')
+	if r.Bounds&RangeUpperUnbounded == 0 {
+		text.WriteString(formatRangeBound(r.Upper))
+	}
+	if r.Bounds&RangeUpperInclusive != 0 {
+		text.WriteByte(']')
+	} else {
+		text.WriteByte(')')
+	}
+	return text.String(), nil
+}
+
+// rangeTimeLayouts are layouts of timestamps and dates used by PostgreSQL in the textual representation of ranges
+var rangeTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+func parseRangeBound(text string, dst reflect.Value) error {
+	if dst.Type() != reflect.TypeOf(time.Time{}) {
+		return convertDefault(text, dst)
+	}
+
+	var err error
+	for _, layout := range rangeTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, text); err == nil {
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return &ParseError{Value: text, Type: dst.Type(), Err: err}
+}
+
+func formatRangeBound(bound interface{}) string {
+	if t, ok := bound.(time.Time); ok {
+		return `"` + t.Format(time.RFC3339Nano) + `"`
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(bound)) + `"`
+}
@@ -0,0 +1,71 @@
+package rowconv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	_ sql.Scanner   = (*Range[int])(nil)
+	_ driver.Valuer = Range[int]{}
+)
+
+func TestRangeScan(t *testing.T) {
+	for text, exp := range map[string]Range[int]{
+		"[1,10)":        {Lower: 1, Upper: 10, Bounds: RangeLowerInclusive},
+		"(,5]":          {Upper: 5, Bounds: RangeLowerUnbounded | RangeUpperInclusive},
+		"[-3,)":         {Lower: -3, Bounds: RangeLowerInclusive | RangeUpperUnbounded},
+		"empty":         {Bounds: RangeEmpty},
+		`("1","2")`:     {Lower: 1, Upper: 2},
+		"(-infinity,2]": {Upper: 2, Bounds: RangeLowerUnbounded | RangeUpperInclusive},
+	} {
+		var act Range[int]
+		if err := act.Scan([]byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Errorf("unexpected range of %s: expected %+v, actual %+v", text, exp, act)
+		}
+	}
+
+	for _, text := range []string{"1,10", "[1,2,3)", "[a,2)"} {
+		var r Range[int]
+		if err := r.Scan(text); err == nil {
+			t.Errorf("error expected for malformed range: %s", text)
+		}
+	}
+}
+
+func TestRangeScanTime(t *testing.T) {
+	var act Range[time.Time]
+	if err := act.Scan(`["2020-01-02 03:04:05+00","2020-01-03 00:00:00.5+02")`); err != nil {
+		t.Fatal(err)
+	}
+	exp := Range[time.Time]{
+		Lower:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Upper:  time.Date(2020, 1, 3, 0, 0, 0, 5e8, time.FixedZone("", 2*60*60)),
+		Bounds: RangeLowerInclusive,
+	}
+	if !act.Lower.Equal(exp.Lower) || !act.Upper.Equal(exp.Upper) || act.Bounds != exp.Bounds {
+		t.Errorf("unexpected range: expected %+v, actual %+v", exp, act)
+	}
+}
+
+func TestRangeValue(t *testing.T) {
+	for exp, r := range map[string]Range[int]{
+		`["1","10")`: {Lower: 1, Upper: 10, Bounds: RangeLowerInclusive},
+		`(,"5"]`:     {Upper: 5, Bounds: RangeLowerUnbounded | RangeUpperInclusive},
+		"empty":      {Bounds: RangeEmpty},
+	} {
+		act, err := r.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(act, exp) {
+			t.Errorf("unexpected value of %+v: expected %s, actual %v", r, exp, act)
+		}
+	}
+}
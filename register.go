@@ -0,0 +1,52 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterMapping registers mapping of the fields of struct T to the columns/aliases for structs that can't be tagged,
+// e.g. third-party or generated ones: `RegisterMapping[User](map[string]string{"ID": "usr_id,key"})`.
+// Keys are names of the fields of T, values have the syntax of `db_column` tag. Fields of nested structs are registered
+// with their own struct types. The registered mapping takes precedence over the tag and the name of the field,
+// unregistered fields are mapped as usual. Mappings should be registered before the first propagation into T,
+// as compiled mappers are cached. Registration of the empty mapping removes it.
+func RegisterMapping[T any](fieldToColumn map[string]string) error {
+	return RegisterMappingOn[T](Default(), fieldToColumn)
+}
+
+// RegisterMappingOn is the same as RegisterMapping for the mapper m
+func RegisterMappingOn[T any](m *Mapper, fieldToColumn map[string]string) error {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("mapping can be registered only for struct types, received: %v", structType)
+	}
+	for fieldName := range fieldToColumn {
+		// promoted fields are registered with the embedded struct type
+		if field, found := structType.FieldByName(fieldName); !found || len(field.Index) != 1 {
+			return fmt.Errorf("%v has no field %s", structType, fieldName)
+		}
+	}
+
+	m.state.mappings.Lock()
+	if len(fieldToColumn) == 0 {
+		delete(m.state.mappings.byType, structType)
+	} else {
+		mapping := make(map[string]string, len(fieldToColumn))
+		for fieldName, column := range fieldToColumn {
+			mapping[fieldName] = column
+		}
+		m.state.mappings.byType[structType] = mapping
+	}
+	m.state.mappings.Unlock()
+	m.state.resetFieldsAccessors()
+	return nil
+}
+
+// registeredTag returns `db_column` tag registered for the field of the owner struct with RegisterMapping
+func (st *state) registeredTag(owner reflect.Type, fieldName string) (string, bool) {
+	st.mappings.RLock()
+	tag, found := st.mappings.byType[owner][fieldName]
+	st.mappings.RUnlock()
+	return tag, found
+}
@@ -0,0 +1,54 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+type untaggedUser struct {
+	ID       int `db_column:"uid"`
+	Name     string
+	Nickname string
+}
+
+func TestRegisterMapping(t *testing.T) {
+	if err := RegisterMapping[untaggedUser](map[string]string{"ID": "id,key", "Name": "col1"}); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterMapping[untaggedUser](nil)
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b')",
+		"SELECT id, col1, col2 AS nickname FROM propagation",
+	)
+	defer release()
+
+	var users map[int]untaggedUser
+	if err := Propagate(&users, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[int]untaggedUser{1: {ID: 1, Name: "a", Nickname: "b"}}
+	if !reflect.DeepEqual(users, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, users)
+	}
+}
+
+func TestRegisterMappingErrors(t *testing.T) {
+	type embedded struct {
+		Col1 string
+	}
+	type valStruct struct {
+		embedded
+		Id int
+	}
+
+	if err := RegisterMapping[int](map[string]string{"Id": "id"}); err == nil {
+		t.Error("error expected for non-struct type")
+	}
+	if err := RegisterMapping[valStruct](map[string]string{"Unknown": "id"}); err == nil {
+		t.Error("error expected for unknown field")
+	}
+	if err := RegisterMapping[valStruct](map[string]string{"Col1": "col2"}); err == nil {
+		t.Error("error expected for promoted field")
+	}
+}
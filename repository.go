@@ -0,0 +1,147 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Database executes queries with and without rows; it is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type Database interface {
+	Queryer
+	Execer
+}
+
+// Repository provides CRUD of the rows of the table of struct T without a full ORM. Statements are generated
+// from the same metadata as used by Propagate: the table is declared with `db_table` tag or RegisterTable,
+// columns/aliases are the ones the fields are mapped to and the rows are identified by the fields tagged
+// with `key` option, e.g. `db_column:"id,key"`. Complex queries are still executed with Select or Propagate.
+type Repository[T any] struct {
+	mapper    *Mapper
+	db        Database
+	dialect   Dialect
+	table     string
+	accessors []fieldAccessor
+	keys      []fieldAccessor
+	updated   []fieldAccessor
+}
+
+// NewRepository creates repository of struct T that executes statements of the dialect with db
+func NewRepository[T any](db Database, dialect Dialect) (*Repository[T], error) {
+	return NewRepositoryOn[T](Default(), db, dialect)
+}
+
+// NewRepositoryOn is the same as NewRepository, the statements are generated and the rows are mapped with the mapper m
+func NewRepositoryOn[T any](m *Mapper, db Database, dialect Dialect) (*Repository[T], error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("repository can be created only for struct types, received: %v", structType)
+	}
+	st := m.state
+	table, err := st.tableOf(structType)
+	if err != nil {
+		return nil, err
+	}
+	accessors, err := st.insertAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &Repository[T]{mapper: m, db: db, dialect: dialect, table: table, accessors: accessors}
+	for _, accessor := range accessors {
+		if hasOption(accessor.options, "key") {
+			repo.keys = append(repo.keys, accessor)
+		} else {
+			repo.updated = append(repo.updated, accessor)
+		}
+	}
+	return repo, nil
+}
+
+// With returns copy of the repository that executes statements with db, e.g. inside of the transaction
+func (r *Repository[T]) With(db Database) *Repository[T] {
+	repo := *r
+	repo.db = db
+	return &repo
+}
+
+// Find returns the rows that satisfy the condition with args, e.g. `Find(ctx, "status = ?", status)`.
+// The condition is added to the query as is, so its placeholders must be in the style of the dialect,
+// the empty condition returns all rows.
+func (r *Repository[T]) Find(ctx context.Context, condition string, args ...interface{}) ([]T, error) {
+	var found []T
+	if err := r.mapper.Select(ctx, r.db, &found, selectQuery(r.table, r.accessors, condition), args...); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// FindOne returns the first row that satisfies the condition with args the same as Find,
+// sql.ErrNoRows is returned if there is no such row
+func (r *Repository[T]) FindOne(ctx context.Context, condition string, args ...interface{}) (T, error) {
+	var found T
+	err := r.mapper.Get(ctx, r.db, &found, selectQuery(r.table, r.accessors, condition), args...)
+	return found, err
+}
+
+// Insert inserts the rows with a single statement generated by BuildInsert
+func (r *Repository[T]) Insert(ctx context.Context, rows ...T) (sql.Result, error) {
+	query, args, err := r.mapper.BuildInsert(r.dialect, r.table, rows)
+	if err != nil {
+		return nil, err
+	}
+	return r.db.ExecContext(ctx, query, args...)
+}
+
+// Update updates all columns of the row identified by its keys except the keys themselves
+func (r *Repository[T]) Update(ctx context.Context, row T) (sql.Result, error) {
+	if len(r.updated) == 0 {
+		return nil, fmt.Errorf("%v has no fields to update", reflect.TypeOf(row))
+	}
+
+	rowValue := reflect.ValueOf(row)
+	assignments := make([]string, len(r.updated))
+	args := make([]interface{}, 0, len(r.accessors))
+	for i, accessor := range r.updated {
+		value, err := insertValue(rowValue, accessor)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+		assignments[i] = accessor.columnAlias + " = " + r.dialect.BindStyle().placeholder(len(args))
+	}
+	condition, args, err := r.keyCondition(rowValue, args)
+	if err != nil {
+		return nil, err
+	}
+	return r.db.ExecContext(ctx, "UPDATE "+r.table+" SET "+strings.Join(assignments, ", ")+" WHERE "+condition, args...)
+}
+
+// Delete deletes the row identified by its keys
+func (r *Repository[T]) Delete(ctx context.Context, row T) (sql.Result, error) {
+	condition, args, err := r.keyCondition(reflect.ValueOf(row), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.db.ExecContext(ctx, "DELETE FROM "+r.table+" WHERE "+condition, args...)
+}
+
+// keyCondition generates condition matching the keys of the row, their values are appended to args
+func (r *Repository[T]) keyCondition(rowValue reflect.Value, args []interface{}) (string, []interface{}, error) {
+	if len(r.keys) == 0 {
+		return "", nil, fmt.Errorf("%v has no fields tagged with key option", rowValue.Type())
+	}
+
+	conditions := make([]string, len(r.keys))
+	for i, accessor := range r.keys {
+		value, err := insertValue(rowValue, accessor)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, value)
+		conditions[i] = accessor.columnAlias + " = " + r.dialect.BindStyle().placeholder(len(args))
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
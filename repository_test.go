@@ -0,0 +1,91 @@
+package rowconv
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var _ Database = (*sql.Tx)(nil)
+
+type repoRow struct {
+	_    struct{} `db_table:"propagation"`
+	Id   int      `db_column:"id,key"`
+	Col1 string
+	Col2 *string
+}
+
+func TestRepository(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+
+	dialect, condition := DialectMySQL, "id = ?"
+	if driverName() == "postgres" {
+		dialect, condition = DialectPostgres, "id = $1"
+	}
+	repo, err := NewRepository[repoRow](db, dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo = repo.With(tx)
+
+	if _, err := repo.Insert(ctx, repoRow{Id: 1, Col1: "a"}, repoRow{Id: 2, Col1: "b", Col2: StringRef("x")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Update(ctx, repoRow{Id: 1, Col1: "c", Col2: StringRef("y")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Delete(ctx, repoRow{Id: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repo.Find(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []repoRow{{Id: 1, Col1: "c", Col2: StringRef("y")}}; !reflect.DeepEqual(found, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, found)
+	}
+
+	one, err := repo.FindOne(ctx, condition, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if one.Col1 != "c" {
+		t.Errorf("unexpeted results of propagation: %+v", one)
+	}
+	if _, err := repo.FindOne(ctx, condition, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("sql.ErrNoRows expected, actual: %v", err)
+	}
+}
+
+func TestRepositoryWithoutKeys(t *testing.T) {
+	repo, err := NewRepository[tableUser](db, DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Update(context.Background(), tableUser{Id: 1}); err == nil {
+		t.Error("error expected for update without keys")
+	}
+	if _, err := repo.Delete(context.Background(), tableUser{Id: 1}); err == nil {
+		t.Error("error expected for delete without keys")
+	}
+
+	type untagged struct{ Id int }
+	if _, err := NewRepository[untagged](db, DialectMySQL); err == nil {
+		t.Error("error expected for struct without table")
+	}
+}
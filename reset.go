@@ -0,0 +1,26 @@
+package rowconv
+
+// ResetConfig restores the default configuration of the package and removes all registrations made with its functions,
+// e.g. RegisterScanner, RegisterEnum or SmallestStructDecomposition, so test suites and plugins reloaded at runtime
+// don't leak them into each other. Compiled mappers are dropped as well, as they embed the registrations.
+// It should not be called concurrently with propagations.
+func ResetConfig() {
+	Default().ResetConfig()
+}
+
+// ResetConfig is the same as ResetConfig of the package for the mapper
+func (m *Mapper) ResetConfig() {
+	m.state.resetConfig()
+	m.state.resetCaches()
+}
+
+// ResetCaches drops compiled mappers, inspected structs, pooled structs and plans recorded or loaded with LoadPlans,
+// keeping the configuration and registrations, so they are created again on the next use
+func ResetCaches() {
+	Default().ResetCaches()
+}
+
+// ResetCaches is the same as ResetCaches of the package for the mapper
+func (m *Mapper) ResetCaches() {
+	m.state.resetCaches()
+}
@@ -0,0 +1,56 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResetConfig(t *testing.T) {
+	type coords struct {
+		X, Y float64
+	}
+	mapper := NewMapper()
+	mapper.RegisterScanner("cents", scanCents)
+	mapper.StrictColumnAmountCheck(true)
+	mapper.SmallestStructDecomposition(reflect.TypeOf(coords{}))
+
+	mapper.ResetConfig()
+	if _, found := mapper.state.scannerOf("cents"); found {
+		t.Error("registered scanner expected to be removed")
+	}
+	if mapper.state.strictColumnAmountCheck() {
+		t.Error("strict column amount check expected to be disabled")
+	}
+	if mapper.state.isSmallestStructDecomposition(reflect.TypeOf(coords{})) {
+		t.Error("registered decomposition expected to be removed")
+	}
+	if !mapper.state.isSmallestStructDecomposition(timeType) {
+		t.Error("default decomposition expected to be restored")
+	}
+}
+
+func TestResetCaches(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	mapper := NewMapper()
+	mapper.RegisterScanner("cents", scanCents)
+	if err := mapper.Warm(valStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mapper.state.structProviders.byType) == 0 {
+		t.Fatal("struct provider expected to be cached")
+	}
+
+	mapper.ResetCaches()
+	if n := len(mapper.state.structProviders.byType); n != 0 {
+		t.Errorf("struct providers expected to be dropped, actual: %d", n)
+	}
+	if n := len(mapper.state.scanDefinitions.byKey); n != 0 {
+		t.Errorf("scan definitions expected to be dropped, actual: %d", n)
+	}
+	if _, found := mapper.state.scannerOf("cents"); !found {
+		t.Error("registered scanner expected to be kept")
+	}
+}
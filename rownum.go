@@ -0,0 +1,55 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isRowNumberField returns true if the field is tagged with `db_rownum:"true"`, such field is not mapped to a column,
+// but filled with the 1-based index of the row in the propagation, e.g. for ranking displays or error reporting.
+// Only exported integer fields of the element struct itself can be row number fields.
+func isRowNumberField(field reflect.StructField) bool {
+	return field.Tag.Get(dbRownum) == "true"
+}
+
+func isRowNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// rowNumberFields returns indexes of the row number fields of the struct contained in holderElementType
+func rowNumberFields(holderElementType reflect.Type) ([]int, error) {
+	structType := derefType(holderElementType)
+	if structType.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var indexes []int
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !isRowNumberField(field) {
+			continue
+		}
+		if field.PkgPath != "" || !isRowNumberKind(field.Type.Kind()) {
+			return nil, fmt.Errorf("row number field %s of %v must be an exported integer field", field.Name, structType)
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
+
+// setRowNumber stores rowNumber into the row number fields of the struct value
+func setRowNumber(structValue reflect.Value, indexes []int, rowNumber int64) {
+	for _, i := range indexes {
+		field := structValue.Field(i)
+		if field.CanInt() {
+			field.SetInt(rowNumber)
+		} else {
+			field.SetUint(uint64(rowNumber))
+		}
+	}
+}
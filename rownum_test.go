@@ -0,0 +1,50 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateRowNumber(t *testing.T) {
+	type ranked struct {
+		Rank int `db_rownum:"true"`
+		Id   int
+		Col1 string
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (10, 'a'), (20, 'b'), (30, 'c')",
+		"SELECT id, col1 FROM propagation ORDER BY id DESC",
+	)
+	defer release()
+
+	var results []*ranked
+	if err := Propagate(&results, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*ranked{{Rank: 1, Id: 30, Col1: "c"}, {Rank: 2, Id: 20, Col1: "b"}, {Rank: 3, Id: 10, Col1: "a"}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
+
+func TestRowNumberFieldValidation(t *testing.T) {
+	type position struct {
+		Row string `db_rownum:"true"`
+	}
+	type nested struct {
+		Position struct {
+			Row int `db_rownum:"true"`
+		}
+	}
+
+	if _, err := rowNumberFields(reflect.TypeOf(&position{})); err == nil {
+		t.Error("error expected for the row number field of non-integer type")
+	}
+	if problems := Validate(reflect.TypeOf(position{})); len(problems) != 1 {
+		t.Errorf("problem expected for the row number field of non-integer type, actual: %v", problems)
+	}
+	if problems := Validate(reflect.TypeOf(nested{})); len(problems) != 1 {
+		t.Errorf("problem expected for the row number field of the nested struct, actual: %v", problems)
+	}
+}
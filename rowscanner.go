@@ -0,0 +1,49 @@
+package rowconv
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// RowScanner is implemented by the element types that scan the row by themselves, e.g. hand-optimized or generated ones.
+// Propagation into such types (or references to them) is delegated entirely to ScanRow called on a new value
+// for each row, so they coexist with the reflective mapping in the same call sites.
+type RowScanner interface {
+	// ScanRow scans the current row, columns are the names of the columns of the rows
+	// and scan is sql.Rows.Scan of them
+	ScanRow(columns []string, scan func(dest ...interface{}) error) error
+}
+
+var rowScannerType = reflect.TypeOf((*RowScanner)(nil)).Elem()
+
+// isRowScannerType returns true if the reference to t implements RowScanner, t is not a reference itself
+func isRowScannerType(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(rowScannerType)
+}
+
+// delegatingScanner creates row scanner that delegates scanning to RowScanner,
+// forType is the type implementing it or reference to it
+func delegatingScanner(forType reflect.Type, columnTypes []columnType) func(unmappedColumnFunc) rowScanner {
+	columns := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		columns[i] = columnType.Name()
+	}
+	scannerType := derefType(forType)
+
+	return func(unmappedColumnFunc) rowScanner {
+		return func(rows *sql.Rows) (reflect.Value, error) {
+			v := reflect.New(scannerType)
+			if err := v.Interface().(RowScanner).ScanRow(columns, rows.Scan); err != nil {
+				return reflect.Value{}, err
+			}
+
+			v = v.Elem()
+			for v.Type() != forType {
+				ref := reflect.New(v.Type())
+				ref.Elem().Set(v)
+				v = ref
+			}
+			return v, nil
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type handScanned struct {
+	id      int
+	name    string
+	columns []string
+}
+
+func (hs *handScanned) ScanRow(columns []string, scan func(dest ...interface{}) error) error {
+	hs.columns = columns
+	var name *string
+	if err := scan(&hs.id, &name); err != nil {
+		return err
+	}
+	if name == nil {
+		return errors.New("name is required")
+	}
+	hs.name = *name
+	return nil
+}
+
+func TestPropagateRowScanner(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT id, col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var results []*handScanned
+	if err := Propagate(&results, rows); err != nil {
+		t.Fatal(err)
+	}
+	columns := []string{"id", "col1"}
+	exp := []*handScanned{{id: 1, name: "a", columns: columns}, {id: 2, name: "b", columns: columns}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
+
+func TestPropagateRowScannerError(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col2 FROM propagation",
+	)
+	defer release()
+
+	var results []handScanned
+	if err := Propagate(&results, rows); err == nil || err.Error() != "name is required" {
+		t.Errorf("error of ScanRow expected, actual: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// scannerOptionPrefix prefixes the option carrying the name of the scanner of `db_scanner` tag of the field
+const scannerOptionPrefix = "scanner="
+
+// RegisterScanner registers scan under the name referred by `db_scanner` tag of the fields that need special handling
+// without a wrapper type, e.g. integer cents stored into the field of decimal type:
+//
+//	type Order struct {
+//		Total float64 `db_scanner:"money_cents"`
+//	}
+//
+// scan receives the value of the column as returned by database driver, its result is stored into the field the same way
+// database/sql stores values of the basic types. NULL values are not passed to scan. The scanner of the field takes
+// precedence over the converters of the database type of the column. Scanners should be registered before the first
+// propagation, as compiled mappers are cached. Registration of nil scan removes it.
+func RegisterScanner(name string, scan func(src interface{}) (interface{}, error)) {
+	Default().RegisterScanner(name, scan)
+}
+
+// RegisterScanner is the same as RegisterScanner of the package for the mapper
+func (m *Mapper) RegisterScanner(name string, scan func(src interface{}) (interface{}, error)) {
+	m.state.scanners.Lock()
+	if scan == nil {
+		delete(m.state.scanners.byName, name)
+	} else {
+		m.state.scanners.byName[name] = scan
+	}
+	m.state.scanners.Unlock()
+}
+
+func (st *state) scannerOf(name string) (func(src interface{}) (interface{}, error), bool) {
+	st.scanners.RLock()
+	scan, found := st.scanners.byName[name]
+	st.scanners.RUnlock()
+	return scan, found
+}
+
+// scannerOption returns the name of the scanner of the field declared with `db_scanner` tag
+func scannerOption(options []string) (string, bool) {
+	for _, opt := range options {
+		if strings.HasPrefix(opt, scannerOptionPrefix) {
+			return strings.TrimPrefix(opt, scannerOptionPrefix), true
+		}
+	}
+	return "", false
+}
+
+// namedScannerConverter creates converter that stores the result of the scanner registered by the name into the field,
+// the conversion fails if there is no such scanner
+func (st *state) namedScannerConverter(name string) converter {
+	scan, registered := st.scannerOf(name)
+	if !registered {
+		return func(src interface{}, dst reflect.Value) error {
+			return fmt.Errorf("scanner %s is not registered", name)
+		}
+	}
+	return convertWith(scan)
+}
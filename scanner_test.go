@@ -0,0 +1,71 @@
+package rowconv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func scanCents(src interface{}) (interface{}, error) {
+	cents, err := strconv.ParseInt(asString(src), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return float64(cents) / 100, nil
+}
+
+func TestRegisterScanner(t *testing.T) {
+	RegisterScanner("money_cents", scanCents)
+	defer RegisterScanner("money_cents", nil)
+	RegisterScanner("upper", func(src interface{}) (interface{}, error) {
+		return strings.ToUpper(asString(src)), nil
+	})
+	defer RegisterScanner("upper", nil)
+
+	type valStruct struct {
+		Id    int
+		Total float64 `db_column:"col1" db_scanner:"money_cents"`
+		Code  *string `db_column:"col2" db_scanner:"upper"`
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '1250', 'ab'), (2, '7', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	code := "AB"
+	exp := []valStruct{{Id: 1, Total: 12.5, Code: &code}, {Id: 2, Total: 0.07}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+	if problems := Validate(reflect.TypeOf(valStruct{})); len(problems) != 0 {
+		t.Errorf("no problems expected, actual: %v", problems)
+	}
+}
+
+func TestUnregisteredScanner(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string `db_scanner:"missing"`
+	}
+	if problems := Validate(reflect.TypeOf(valStruct{})); len(problems) != 1 {
+		t.Errorf("unregistered scanner expected to be reported, actual: %v", problems)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 FROM propagation",
+	)
+	defer release()
+
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("error of unregistered scanner expected, actual: %v", err)
+	}
+}
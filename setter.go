@@ -0,0 +1,71 @@
+package rowconv
+
+import (
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// setterOf returns method `SetXxx(v)` of the struct dstType (or reference to it) the column/alias `xxx` is mapped to,
+// underscores of the column are ignored, so `SetUserName` is the setter of `user_name`.
+// The method has a pointer or value receiver, a single argument and returns nothing or an error.
+func setterOf(dstType reflect.Type, column string) (reflect.Method, bool) {
+	column = strings.ReplaceAll(column, "_", "")
+	ptrType := reflect.PtrTo(derefType(dstType))
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		name := strings.TrimPrefix(method.Name, "Set")
+		if name == method.Name || name == "" || !strings.EqualFold(name, column) {
+			continue
+		}
+
+		methodType := method.Type
+		if methodType.NumIn() != 2 ||
+			methodType.NumOut() > 1 || methodType.NumOut() == 1 && methodType.Out(0) != errorType {
+			continue
+		}
+		return method, true
+	}
+	return reflect.Method{}, false
+}
+
+// isSettableAccessor returns true if the fields of dstType the accessors refer to can be set directly,
+// otherwise the setter of the column is used if there is one
+func isSettableAccessor(dstType reflect.Type, accessors []fieldAccessor) bool {
+	for _, accessor := range accessors {
+		if _, field, found := structFieldByIndex(dstType, accessor.fieldIndex); !found || !token.IsExported(field.Name) {
+			return false
+		}
+	}
+	return len(accessors) > 0
+}
+
+func holderBySetter(column string, setter reflect.Method, convert converter) holderSupplier {
+	return func(underlyingValue reflect.Value) interface{} {
+		return &setterScanner{column: column, receiver: underlyingValue.Addr(), setter: setter, convert: convert}
+	}
+}
+
+// setterScanner is a scan destination that converts the value into the argument of the setter and calls it
+type setterScanner struct {
+	column   string
+	receiver reflect.Value
+	setter   reflect.Method
+	convert  converter
+}
+
+func (ss *setterScanner) Scan(src interface{}) (err error) {
+	defer recoverColumnPanic(ss.column, &err)
+
+	arg := reflect.New(ss.setter.Type.In(1)).Elem()
+	argScanner := &fieldScanner{column: ss.column, field: arg, convert: ss.convert}
+	if err := argScanner.Scan(src); err != nil {
+		return err
+	}
+
+	out := ss.setter.Func.Call([]reflect.Value{ss.receiver, arg})
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
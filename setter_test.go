@@ -0,0 +1,100 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type encapsulatedAccount struct {
+	Id    int
+	name  string
+	label *string
+}
+
+func (ea *encapsulatedAccount) SetName(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	ea.name = strings.ToUpper(name)
+	return nil
+}
+
+func (ea *encapsulatedAccount) SetLabel(label *string) {
+	ea.label = label
+}
+
+func TestPropagateSetters(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1 AS name, col2 AS label FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var accounts []encapsulatedAccount
+	if err := Propagate(&accounts, rows); err != nil {
+		t.Fatal(err)
+	}
+	b := "b"
+	exp := []encapsulatedAccount{{Id: 1, name: "A", label: &b}, {Id: 2, name: "C"}}
+	if !reflect.DeepEqual(accounts, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, accounts)
+	}
+	if problems := Validate(reflect.TypeOf(encapsulatedAccount{})); len(problems) != 0 {
+		t.Errorf("no problems expected for fields with setters: %v", problems)
+	}
+}
+
+func TestPropagateSetterError(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, '' AS name FROM propagation",
+	)
+	defer release()
+
+	var accounts []encapsulatedAccount
+	if err := Propagate(&accounts, rows); err == nil || !strings.Contains(err.Error(), "empty name") {
+		t.Errorf("error of the setter expected, actual: %v", err)
+	}
+}
+
+type encapsulatedUser struct {
+	Id       int
+	userName string
+}
+
+func (eu *encapsulatedUser) SetUserName(userName string) {
+	eu.userName = userName
+}
+
+func TestPropagateSetterOfMultiWordColumn(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1 AS user_name FROM propagation",
+	)
+	defer release()
+
+	var users []encapsulatedUser
+	if err := Propagate(&users, rows); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []encapsulatedUser{{Id: 1, userName: "a"}}; !reflect.DeepEqual(users, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, users)
+	}
+}
+
+func TestSetterOf(t *testing.T) {
+	accountType := reflect.TypeOf(encapsulatedAccount{})
+	if setter, found := setterOf(accountType, "NAME"); !found || setter.Name != "SetName" {
+		t.Errorf("setter expected, actual: %+v", setter)
+	}
+	if setter, found := setterOf(reflect.TypeOf(encapsulatedUser{}), "user_name"); !found || setter.Name != "SetUserName" {
+		t.Errorf("setter of multi-word column expected, actual: %+v", setter)
+	}
+	for _, column := range []string{"id", "col2", ""} {
+		if setter, found := setterOf(accountType, column); found {
+			t.Errorf("no setter expected for %q, actual: %+v", column, setter)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package rowconv
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Sink receives values mapped from rows one by one
+type Sink interface {
+	// Add accepts the value mapped from the next row
+	Add(v reflect.Value) error
+	// Flush is called once all rows are mapped
+	Flush() error
+}
+
+// newSink creates built-in sink for dst accepted by Propagate and returns it with the type of the elements it accepts,
+// the keys of the maps are taken with the mapping of the mapper of st
+func (st *state) newSink(dst interface{}) (Sink, reflect.Type, error) {
+	if appender, ok := dst.(TypedAppender); ok {
+		return NewAppenderSink(appender), appender.ElementType(), nil
+	}
+
+	dstValue := reflect.ValueOf(dst)
+	switch {
+	case dstValue.Kind() == reflect.Chan:
+		sink, err := NewChanSink(dst)
+		return sink, dstValue.Type().Elem(), err
+	case dstValue.Kind() == reflect.Ptr && dstValue.Type().Elem().Kind() == reflect.Slice:
+		sink, err := NewSliceSink(dst)
+		return sink, dstValue.Type().Elem().Elem(), err
+	case dstValue.Kind() == reflect.Ptr && dstValue.Type().Elem().Kind() == reflect.Map:
+		sink, err := st.newMapSink(dst)
+		return sink, dstValue.Type().Elem().Elem(), err
+	default:
+		return nil, nil, fmt.Errorf("pointer to the slice is expected, received: %T", dst)
+	}
+}
+
+type sliceSink struct {
+	slice reflect.Value
+	// chunk is the amount of elements the capacity is grown by, see SliceGrowth
+	chunk int
+}
+
+// NewSliceSink creates sink that appends values to the slice dst points to
+func NewSliceSink(dst interface{}) (Sink, error) {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.Type().Elem().Kind() != reflect.Slice || dstValue.IsNil() {
+		return nil, fmt.Errorf("pointer to the slice is expected, received: %T", dst)
+	}
+	return &sliceSink{slice: dstValue.Elem()}, nil
+}
+
+func (ss *sliceSink) Add(v reflect.Value) error {
+	if ss.chunk > 0 && ss.slice.Len() == ss.slice.Cap() {
+		ss.reserve(ss.chunk)
+	}
+	ss.slice.Set(reflect.Append(ss.slice, v))
+	return nil
+}
+
+func (ss *sliceSink) Flush() error { return nil }
+
+type mapSink struct {
+	m   reflect.Value
+	key func(v reflect.Value) (reflect.Value, error)
+}
+
+// NewMapSink creates sink that puts values into the map dst points to; nil map is initialized.
+// Values must be structs or references to structs. The key is taken from the field tagged with `key` option,
+// e.g. `db_column:"id,key"`. If the key is a struct, its fields are populated from the fields with `key` option
+// mapped to the same columns, so the map can be keyed by several columns (e.g. tenant_id + user_id).
+// The last value wins if keys of multiple values are the same.
+func NewMapSink(dst interface{}) (Sink, error) {
+	return Default().NewMapSink(dst)
+}
+
+// NewMapSink is the same as NewMapSink of the package, the keys are taken with the mapping of the mapper
+func (m *Mapper) NewMapSink(dst interface{}) (Sink, error) {
+	return m.state.newMapSink(dst)
+}
+
+func (st *state) newMapSink(dst interface{}) (Sink, error) {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.Type().Elem().Kind() != reflect.Map || dstValue.IsNil() {
+		return nil, fmt.Errorf("pointer to the map is expected, received: %T", dst)
+	}
+
+	m := dstValue.Elem()
+	key, err := st.mapKeyExtractor(m.Type().Key(), m.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	return &mapSink{m: m, key: key}, nil
+}
+
+func (ms *mapSink) Add(v reflect.Value) error {
+	key, err := ms.key(v)
+	if err != nil {
+		return err
+	}
+	ms.m.SetMapIndex(key, v)
+	return nil
+}
+
+func (ms *mapSink) Flush() error { return nil }
+
+// keyAccessors returns accessors of the fields tagged with `key` option in the order of their declaration
+func (st *state) keyAccessors(valueType reflect.Type) ([]fieldAccessor, error) {
+	structType, _, err := unwrapPtrStructType(valueType)
+	if err != nil {
+		return nil, err
+	}
+
+	columnAliasToAccessor, err := st.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessors []fieldAccessor
+	for _, accessor := range columnAliasToAccessor {
+		if hasOption(accessor.options, "key") {
+			accessors = append(accessors, accessor)
+		}
+	}
+	sort.Slice(accessors, func(i, j int) bool {
+		return lessIndex(accessors[i].fieldIndex, accessors[j].fieldIndex)
+	})
+	return accessors, nil
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func (st *state) mapKeyExtractor(keyType, valueType reflect.Type) (func(v reflect.Value) (reflect.Value, error), error) {
+	accessors, err := st.keyAccessors(valueType)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType.Kind() == reflect.Struct && !st.isSmallestStructDecomposition(keyType) {
+		return st.compositeKeyExtractor(keyType, valueType, accessors)
+	}
+
+	if len(accessors) != 1 {
+		return nil, fmt.Errorf("exactly one field with `key` option is expected for map values of type %v, found: %d", valueType, len(accessors))
+	}
+
+	extract, err := keyFieldExtractor(accessors[0], keyType)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) (reflect.Value, error) {
+		underlyingValue, _, err := unwrapPtrStructValue(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return extract(underlyingValue)
+	}, nil
+}
+
+// compositeKeyExtractor creates extractor of the struct key which fields are populated from the key fields of the value.
+// Fields of the key are matched with key fields of the value by the column/alias they are mapped to.
+func (st *state) compositeKeyExtractor(keyType, valueType reflect.Type, accessors []fieldAccessor) (func(v reflect.Value) (reflect.Value, error), error) {
+	columnAliasToAccessor := map[string]fieldAccessor{}
+	for _, accessor := range accessors {
+		columnAliasToAccessor[accessor.columnAlias] = accessor
+	}
+
+	extracts := make([]func(underlyingValue reflect.Value) (reflect.Value, error), keyType.NumField())
+	for i := 0; i < keyType.NumField(); i++ {
+		keyField := keyType.Field(i)
+		accessor, found := columnAliasToAccessor[st.fieldColumnAlias(keyType, keyField)]
+		if !found {
+			return nil, fmt.Errorf("no field with `key` option in %v for the field %s of the key type %v", valueType, keyField.Name, keyType)
+		}
+
+		extract, err := keyFieldExtractor(accessor, keyField.Type)
+		if err != nil {
+			return nil, err
+		}
+		extracts[i] = extract
+	}
+
+	return func(v reflect.Value) (reflect.Value, error) {
+		underlyingValue, _, err := unwrapPtrStructValue(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		key := reflect.New(keyType).Elem()
+		for i, extract := range extracts {
+			keyFieldValue, err := extract(underlyingValue)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			key.Field(i).Set(keyFieldValue)
+		}
+		return key, nil
+	}, nil
+}
+
+// keyFieldExtractor creates extractor of the key field value converted to the keyType
+func keyFieldExtractor(accessor fieldAccessor, keyType reflect.Type) (func(underlyingValue reflect.Value) (reflect.Value, error), error) {
+	fieldType := accessor.fieldType
+	if fieldType.Kind() == reflect.Ptr && keyType.Kind() != reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if !fieldType.ConvertibleTo(keyType) {
+		return nil, fmt.Errorf("key field of type %v can't be used as key of type %v", accessor.fieldType, keyType)
+	}
+
+	return func(underlyingValue reflect.Value) (reflect.Value, error) {
+		key := underlyingValue.FieldByIndex(accessor.fieldIndex)
+		if key.Kind() == reflect.Ptr && keyType.Kind() != reflect.Ptr {
+			if key.IsNil() {
+				return reflect.Value{}, errors.New("key field can't be NULL")
+			}
+			key = key.Elem()
+		}
+		return key.Convert(keyType), nil
+	}, nil
+}
+
+type chanSink struct {
+	ch reflect.Value
+}
+
+// NewChanSink creates sink that sends values into the channel ch. Send blocks until the value is received,
+// so the channel must be drained concurrently. The channel is not closed by the sink.
+func NewChanSink(ch interface{}) (Sink, error) {
+	chValue := reflect.ValueOf(ch)
+	if chValue.Kind() != reflect.Chan || chValue.Type().ChanDir()&reflect.SendDir == 0 || chValue.IsNil() {
+		return nil, fmt.Errorf("channel available for send is expected, received: %T", ch)
+	}
+	return &chanSink{ch: chValue}, nil
+}
+
+func (cs *chanSink) Add(v reflect.Value) error {
+	cs.ch.Send(v)
+	return nil
+}
+
+func (cs *chanSink) Flush() error { return nil }
+
+type writerSink struct {
+	out *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewWriterSink creates sink that writes values into w as JSON, one value per line.
+// Writes are buffered and flushed once all rows are mapped.
+func NewWriterSink(w io.Writer) Sink {
+	out := bufio.NewWriter(w)
+	return &writerSink{out: out, enc: json.NewEncoder(out)}
+}
+
+func (ws *writerSink) Add(v reflect.Value) error {
+	return ws.enc.Encode(v.Interface())
+}
+
+func (ws *writerSink) Flush() error {
+	return ws.out.Flush()
+}
+
+// Encoder encodes values into a stream one by one, e.g. *gob.Encoder or msgpack.Encoder of github.com/vmihailenco/msgpack
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+type encoderSink struct {
+	enc   Encoder
+	flush func() error
+	st    *state
+	// elementType is the type of the elements the fields are resolved for
+	elementType reflect.Type
+	fields      []encodedField
+}
+
+// encodedField is the field of the struct element encoded by the column/alias it is mapped to
+type encodedField struct {
+	columnAlias string
+	fieldIndex  []int
+}
+
+// NewEncoderSink creates sink that passes values to enc as they are mapped, so bulk transfers between services
+// are streamed over io.Writer of enc without collecting all rows first. Struct elements are encoded as maps
+// of the columns/aliases their fields are mapped to, taken from the mapping of the fields without the second
+// reflection pass of enc over the struct; NULL values of the fields of reference types are encoded as nil.
+// Elements of other types are encoded as is. Types of the values, such as time.Time, may have to be registered
+// with enc, e.g. with gob.Register. If enc implements `Flush() error`, it is flushed once all rows are mapped.
+func NewEncoderSink(enc Encoder) Sink {
+	return Default().NewEncoderSink(enc)
+}
+
+// NewEncoderSink is the same as NewEncoderSink of the package, the fields are mapped with the mapping of the mapper
+func (m *Mapper) NewEncoderSink(enc Encoder) Sink {
+	es := &encoderSink{enc: enc, flush: func() error { return nil }, st: m.state}
+	if flusher, ok := enc.(interface{ Flush() error }); ok {
+		es.flush = flusher.Flush
+	}
+	return es
+}
+
+func (es *encoderSink) Add(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || es.st.isSmallestStructDecomposition(v.Type()) {
+		return es.enc.Encode(v.Interface())
+	}
+
+	if v.Type() != es.elementType {
+		fields, err := es.st.encodedFields(v.Type())
+		if err != nil {
+			return err
+		}
+		es.elementType, es.fields = v.Type(), fields
+	}
+
+	encoded := make(map[string]interface{}, len(es.fields))
+	for _, field := range es.fields {
+		value, err := encodedValue(v, field.fieldIndex)
+		if err != nil {
+			return err
+		}
+		encoded[field.columnAlias] = value
+	}
+	return es.enc.Encode(encoded)
+}
+
+// encodedFields returns the exported fields of structType mapped to the columns, nested structs are represented
+// by their own fields
+func (st *state) encodedFields(structType reflect.Type) ([]encodedField, error) {
+	columnAliasToAccessor, err := st.createFieldsAccessors(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]encodedField, 0, len(columnAliasToAccessor))
+	for columnAlias, accessor := range columnAliasToAccessor {
+		if accessor.nested || !exportedPath(structType, accessor.fieldIndex) {
+			continue
+		}
+		fields = append(fields, encodedField{columnAlias: columnAlias, fieldIndex: accessor.fieldIndex})
+	}
+	return fields, nil
+}
+
+// exportedPath returns true if all the fields on the way to the field of structType are exported
+func exportedPath(structType reflect.Type, fieldIndex []int) bool {
+	for i := range fieldIndex {
+		if _, field, found := structFieldByIndex(structType, fieldIndex[:i+1]); !found || field.PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// encodedValue returns the value of the field of v, nil is returned if there is nil reference on the way to it.
+// Values of driver.Valuer, e.g. sql.NullString, are encoded as the values they hold.
+func encodedValue(v reflect.Value, fieldIndex []int) (interface{}, error) {
+	for _, i := range fieldIndex {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	value := v.Interface()
+	if v.CanAddr() {
+		value = v.Addr().Interface()
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return v.Interface(), nil
+}
+
+func (es *encoderSink) Flush() error {
+	return es.flush()
+}
@@ -0,0 +1,205 @@
+package rowconv
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPropagateIntoMap(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type refStruct struct {
+		Id   int `db_column:",key"`
+		Col1 string
+		Col2 *string
+	}
+	var byID map[int]*refStruct
+	if err := Propagate(&byID, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[int]*refStruct{
+		1: {Id: 1, Col1: "a"},
+		2: {Id: 2, Col1: "b", Col2: StringRef("c")},
+	}
+	if !reflect.DeepEqual(byID, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, byID)
+	}
+}
+
+func TestPropagateIntoChan(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b')",
+		"SELECT col1 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	col1s := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Propagate(col1s, rows)
+		close(col1s)
+	}()
+
+	var received []string
+	for col1 := range col1s {
+		received = append(received, col1)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, []string{"a", "b"}) {
+		t.Errorf("unexpeted results of propagation: %v", received)
+	}
+}
+
+func TestPropagateIntoWriterSink(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type valStruct struct {
+		Col1 string
+		Col2 *string
+	}
+	var out bytes.Buffer
+	if err := PropagateSink(NewWriterSink(&out), reflect.TypeOf(valStruct{}), rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := "{\"Col1\":\"a\",\"Col2\":null}\n{\"Col1\":\"b\",\"Col2\":\"c\"}\n"
+	if out.String() != exp {
+		t.Errorf("unexpeted results of propagation: expected %q, actual %q", exp, out.String())
+	}
+}
+
+func TestPropagateIntoEncoderSink(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', NULL), (2, 'b', 'c')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type details struct {
+		Name  string         `db_column:"col1"`
+		Extra sql.NullString `db_column:"col2"`
+	}
+	type valStruct struct {
+		Id      int64
+		Details details
+		hidden  string
+	}
+	var out bytes.Buffer
+	if err := PropagateSink(NewEncoderSink(gob.NewEncoder(&out)), reflect.TypeOf(valStruct{}), rows); err != nil {
+		t.Fatal(err)
+	}
+
+	// struct elements are encoded by the columns/aliases their fields are mapped to
+	dec := gob.NewDecoder(&out)
+	var received []map[string]interface{}
+	for {
+		var v map[string]interface{}
+		if err := dec.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, v)
+	}
+	exp := []map[string]interface{}{
+		{"id": int64(1), "col1": "a", "col2": nil},
+		{"id": int64(2), "col1": "b", "col2": "c"},
+	}
+	if !reflect.DeepEqual(received, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, received)
+	}
+}
+
+type countingSink struct {
+	added   int
+	flushed bool
+}
+
+func (cs *countingSink) Add(v reflect.Value) error {
+	cs.added++
+	return nil
+}
+
+func (cs *countingSink) Flush() error {
+	cs.flushed = true
+	return nil
+}
+
+func TestPropagateIntoCustomSink(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	var sink countingSink
+	if err := PropagateSink(&sink, reflect.TypeOf(0), rows); err != nil {
+		t.Fatal(err)
+	}
+	if sink.added != 3 || !sink.flushed {
+		t.Errorf("unexpeted state of the sink: %+v", sink)
+	}
+}
+
+func TestNewMapSinkWithoutKey(t *testing.T) {
+	var m map[int]struct{ Id int }
+	if _, err := NewMapSink(&m); err == nil {
+		t.Error("error expected for map value without key field")
+	}
+}
+
+func TestPropagateIntoMapWithCompositeKey(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'x'), (2, 'a', 'y'), (3, 'b', 'x')",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	type key struct {
+		Tenant string `db_column:"col1"`
+		User   string `db_column:"col2"`
+	}
+	type refStruct struct {
+		Id     int
+		Tenant string `db_column:"col1,key"`
+		User   string `db_column:"col2,key"`
+	}
+	var byKey map[key]*refStruct
+	if err := Propagate(&byKey, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[key]*refStruct{
+		{Tenant: "a", User: "x"}: {Id: 1, Tenant: "a", User: "x"},
+		{Tenant: "a", User: "y"}: {Id: 2, Tenant: "a", User: "y"},
+		{Tenant: "b", User: "x"}: {Id: 3, Tenant: "b", User: "x"},
+	}
+	if !reflect.DeepEqual(byKey, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, byKey)
+	}
+}
+
+func TestNewMapSinkWithUnmatchedCompositeKey(t *testing.T) {
+	type key struct {
+		Tenant string
+		Other  int
+	}
+	var m map[key]struct {
+		Tenant string `db_column:",key"`
+	}
+	if _, err := NewMapSink(&m); err == nil {
+		t.Error("error expected for key field without matching value field")
+	}
+}
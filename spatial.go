@@ -0,0 +1,127 @@
+package rowconv
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// SpatialFormat is the format the values of spatial columns are returned in by database driver
+type SpatialFormat uint8
+
+const (
+	// SpatialEWKB is the format of PostGIS geometry and geography columns: extended WKB, hex-encoded in the textual
+	// representation. The SRID embedded into EWKB is removed from WKB and reported as SRID. Plain WKB, e.g. of
+	// ST_AsBinary, is accepted as well.
+	SpatialEWKB SpatialFormat = iota
+	// SpatialMySQL is the internal format of MySQL spatial columns: 4-byte little-endian SRID followed by WKB
+	SpatialMySQL
+	// SpatialWKT is the well-known text, e.g. of ST_AsText, optionally prefixed with SRID of EWKT: "SRID=4326;POINT(1 2)"
+	SpatialWKT
+)
+
+// ewkbSRIDFlag is set in the geometry type of EWKB that embeds SRID after the type
+const ewkbSRIDFlag = 0x20000000
+
+// SpatialValue is the value of spatial column passed to the decoder registered with RegisterSpatialDecoder
+type SpatialValue struct {
+	// WKB is the geometry in well-known binary, it is empty for SpatialWKT.
+	// It may refer to the buffer of database driver, so it must be copied to be retained after decode.
+	WKB []byte
+	// WKT is the geometry in well-known text for SpatialWKT, it is empty for other formats
+	WKT string
+	// SRID is the spatial reference system identifier, 0 if it is unknown
+	SRID uint32
+}
+
+// RegisterSpatialDecoder registers decode of the values of spatial columns of the database type, as reported by
+// sql.ColumnType.DatabaseTypeName (e.g. "GEOMETRY" or "POINT"), returned by database driver in the format.
+// It is the hook for geometry libraries such as orb or geom: the value is delivered as raw WKB (or WKT) regardless of
+// the wrapping of the database, the result of decode is stored into the field the same way as the value returned
+// by database driver, e.g. orb.Point into the field of orb.Point type:
+//
+//	rowconv.RegisterSpatialDecoder("GEOMETRY", rowconv.SpatialMySQL, func(v rowconv.SpatialValue) (interface{}, error) {
+//		return wkb.Unmarshal(v.WKB)
+//	})
+//
+// The decoder is registered with RegisterDatabaseTypeConverter, so the same rules apply. Registration of nil removes it.
+func RegisterSpatialDecoder(databaseTypeName string, format SpatialFormat, decode func(SpatialValue) (interface{}, error)) {
+	Default().RegisterSpatialDecoder(databaseTypeName, format, decode)
+}
+
+// RegisterSpatialDecoder is the same as RegisterSpatialDecoder of the package for the mapper
+func (m *Mapper) RegisterSpatialDecoder(databaseTypeName string, format SpatialFormat, decode func(SpatialValue) (interface{}, error)) {
+	if decode == nil {
+		m.RegisterDatabaseTypeConverter(databaseTypeName, nil)
+		return
+	}
+	m.RegisterDatabaseTypeConverter(databaseTypeName, func(src interface{}) (interface{}, error) {
+		value, err := parseSpatial(src, format)
+		if err != nil {
+			return nil, err
+		}
+		return decode(value)
+	})
+}
+
+// parseSpatial extracts WKB or WKT with SRID from the value of spatial column in the format
+func parseSpatial(src interface{}, format SpatialFormat) (SpatialValue, error) {
+	switch format {
+	case SpatialWKT:
+		text := strings.TrimSpace(asString(src))
+		if !strings.HasPrefix(strings.ToUpper(text), "SRID=") {
+			return SpatialValue{WKT: text}, nil
+		}
+		prefix, wkt, found := strings.Cut(text, ";")
+		srid, err := strconv.ParseUint(prefix[len("SRID="):], 10, 32)
+		if !found || err != nil {
+			return SpatialValue{}, errors.New("malformed SRID of EWKT")
+		}
+		return SpatialValue{WKT: wkt, SRID: uint32(srid)}, nil
+
+	case SpatialMySQL:
+		raw := asBytes(src)
+		if len(raw) < 9 {
+			return SpatialValue{}, errors.New("spatial value is too short")
+		}
+		return SpatialValue{WKB: raw[4:], SRID: binary.LittleEndian.Uint32(raw)}, nil
+
+	default:
+		raw := asBytes(src)
+		// the textual representation is hex-encoded, while binary WKB starts with the byte order 0 or 1
+		if len(raw) > 0 && raw[0] != 0 && raw[0] != 1 {
+			decoded, err := hex.DecodeString(string(raw))
+			if err != nil {
+				return SpatialValue{}, err
+			}
+			raw = decoded
+		}
+		return parseEWKB(raw)
+	}
+}
+
+// parseEWKB removes SRID from extended WKB of PostGIS, so the result is WKB
+func parseEWKB(raw []byte) (SpatialValue, error) {
+	if len(raw) < 5 || raw[0] > 1 {
+		return SpatialValue{}, errors.New("malformed WKB")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if raw[0] == 1 {
+		order = binary.LittleEndian
+	}
+	geometryType := order.Uint32(raw[1:5])
+	if geometryType&ewkbSRIDFlag == 0 {
+		return SpatialValue{WKB: raw}, nil
+	}
+	if len(raw) < 9 {
+		return SpatialValue{}, errors.New("malformed EWKB")
+	}
+
+	wkb := make([]byte, 0, len(raw)-4)
+	wkb = append(wkb, raw[0], 0, 0, 0, 0)
+	order.PutUint32(wkb[1:5], geometryType&^ewkbSRIDFlag)
+	wkb = append(wkb, raw[9:]...)
+	return SpatialValue{WKB: wkb, SRID: order.Uint32(raw[5:9])}, nil
+}
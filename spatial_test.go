@@ -0,0 +1,74 @@
+package rowconv
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestParseSpatial(t *testing.T) {
+	// POINT(1 2) in little-endian WKB
+	wkb, _ := hex.DecodeString("0101000000000000000000f03f0000000000000040")
+	// the same point with SRID 4326 in EWKB of PostGIS
+	ewkb, _ := hex.DecodeString("0101000020e6100000000000000000f03f0000000000000040")
+	mysql := append([]byte{0xe6, 0x10, 0, 0}, wkb...)
+
+	for _, tc := range []struct {
+		src    interface{}
+		format SpatialFormat
+		exp    SpatialValue
+	}{
+		{src: hex.EncodeToString(ewkb), format: SpatialEWKB, exp: SpatialValue{WKB: wkb, SRID: 4326}},
+		{src: []byte(hex.EncodeToString(ewkb)), format: SpatialEWKB, exp: SpatialValue{WKB: wkb, SRID: 4326}},
+		{src: ewkb, format: SpatialEWKB, exp: SpatialValue{WKB: wkb, SRID: 4326}},
+		{src: wkb, format: SpatialEWKB, exp: SpatialValue{WKB: wkb}},
+		{src: mysql, format: SpatialMySQL, exp: SpatialValue{WKB: wkb, SRID: 4326}},
+		{src: "SRID=4326;POINT(1 2)", format: SpatialWKT, exp: SpatialValue{WKT: "POINT(1 2)", SRID: 4326}},
+		{src: []byte("POINT(1 2)"), format: SpatialWKT, exp: SpatialValue{WKT: "POINT(1 2)"}},
+	} {
+		act, err := parseSpatial(tc.src, tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(act, tc.exp) {
+			t.Errorf("unexpected spatial value of %v: expected %+v, actual %+v", tc.src, tc.exp, act)
+		}
+	}
+
+	for _, tc := range []struct {
+		src    interface{}
+		format SpatialFormat
+	}{
+		{src: "zz", format: SpatialEWKB},
+		{src: []byte{1, 1}, format: SpatialEWKB},
+		{src: []byte{0xe6, 0x10, 0, 0}, format: SpatialMySQL},
+		{src: "SRID=x;POINT(1 2)", format: SpatialWKT},
+	} {
+		if _, err := parseSpatial(tc.src, tc.format); err == nil {
+			t.Errorf("error expected for malformed value %v", tc.src)
+		}
+	}
+}
+
+func TestRegisterSpatialDecoder(t *testing.T) {
+	type point struct {
+		wkb  []byte
+		srid uint32
+	}
+	RegisterSpatialDecoder("test_point", SpatialMySQL, func(v SpatialValue) (interface{}, error) {
+		return point{wkb: append([]byte(nil), v.WKB...), srid: v.SRID}, nil
+	})
+	defer RegisterSpatialDecoder("test_point", SpatialMySQL, nil)
+
+	convert, found := Default().state.databaseTypeConverterOf("TEST_POINT")
+	if !found {
+		t.Fatal("decoder expected to be registered as database type converter")
+	}
+	act, err := convert([]byte{0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := act.(point); !ok || len(p.wkb) != 21 || p.srid != 0 {
+		t.Errorf("unexpected decoded value: %+v", act)
+	}
+}
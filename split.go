@@ -0,0 +1,165 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const splitOptionPrefix = "split="
+
+// RegisterSplitter registers split under the name referred by `split` tag option of the fields populated from
+// a single column, e.g. a packed "WxH" dimension string split into Width and Height:
+//
+//	type Box struct {
+//		Width  int `db_column:"dims,split=wxh"`
+//		Height int `db_column:"dims,split=wxh"`
+//	}
+//
+// split receives the value of the column as returned by database driver and returns values of the fields tagged with
+// the same column, in order of their declaration. The values are stored into the fields the same way database/sql
+// stores values of the basic types. NULL values are not passed to split, the fields are set to zero values instead.
+// Splitters should be registered before the first propagation, as compiled mappers are cached.
+// Registration of nil split removes it.
+func RegisterSplitter(name string, split func(src interface{}) ([]interface{}, error)) {
+	Default().RegisterSplitter(name, split)
+}
+
+// RegisterSplitter is the same as RegisterSplitter of the package for the mapper
+func (m *Mapper) RegisterSplitter(name string, split func(src interface{}) ([]interface{}, error)) {
+	m.state.splitters.Lock()
+	if split == nil {
+		delete(m.state.splitters.byName, name)
+	} else {
+		m.state.splitters.byName[name] = split
+	}
+	m.state.splitters.Unlock()
+}
+
+func (st *state) splitterOf(name string) (func(src interface{}) ([]interface{}, error), bool) {
+	st.splitters.RLock()
+	split, found := st.splitters.byName[name]
+	st.splitters.RUnlock()
+	return split, found
+}
+
+// splitOption returns the name of the splitter of the field from its tag options
+func splitOption(options []string) (string, bool) {
+	for _, opt := range options {
+		if strings.HasPrefix(opt, splitOptionPrefix) {
+			return strings.TrimPrefix(opt, splitOptionPrefix), true
+		}
+	}
+	return "", false
+}
+
+func isSplitField(options []string) bool {
+	_, found := splitOption(options)
+	return found
+}
+
+// fieldSplit distributes values the column is split into to the fields
+type fieldSplit struct {
+	name      string
+	split     func(src interface{}) ([]interface{}, error)
+	accessors []fieldAccessor
+}
+
+// fieldSplits returns splits of the columns/aliases into the fields of dstType declared with tags,
+// splits declared with Mapping take precedence over them
+func (copts compileOptions) fieldSplits(dstType reflect.Type) (map[string]fieldSplit, error) {
+	st := copts.state
+	columnToSplit := map[string]fieldSplit{}
+	err := st.visitLeafFields(derefType(dstType), nil, nil, func(owner reflect.Type, field reflect.StructField, fieldIndex []int, fieldPath []string) error {
+		columnAlias, options := st.fieldColumnTagByKeys(owner, field, copts.tagKeys)
+		name, found := splitOption(options)
+		if !found {
+			return nil
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("unexported field %s can't be split into", strings.Join(fieldPath, "."))
+		}
+
+		fs, exists := columnToSplit[columnAlias]
+		if !exists {
+			split, registered := st.splitterOf(name)
+			if !registered {
+				return fmt.Errorf("splitter %s of field %s is not registered", name, strings.Join(fieldPath, "."))
+			}
+			fs = fieldSplit{name: name, split: split}
+		} else if fs.name != name {
+			return fmt.Errorf("column/alias %s is split by more than one splitter: %s and %s", columnAlias, fs.name, name)
+		}
+		fs.accessors = append(fs.accessors, fieldAccessor{
+			columnAlias: columnAlias,
+			fieldType:   field.Type,
+			fieldIndex:  fieldIndex,
+			fieldPath:   fieldPath,
+			options:     options,
+		})
+		columnToSplit[columnAlias] = fs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if copts.mapping != nil && copts.mapping.structType == derefType(dstType) {
+		for column, fs := range copts.mapping.columnToSplit(copts.state) {
+			columnToSplit[column] = fs
+		}
+	}
+	return columnToSplit, nil
+}
+
+// holderBySplit creates holder that splits the value of the column into the fields
+func holderBySplit(column string, fs fieldSplit, copts compileOptions) holderSupplier {
+	converts := make([]converter, len(fs.accessors))
+	for i, accessor := range fs.accessors {
+		converts[i] = accessor.convert
+		if converts[i] == nil {
+			converts[i] = copts.converter(accessor.fieldType, accessor.options)
+		}
+		if converts[i] == nil {
+			converts[i] = convertReference(convertDefault)
+		}
+	}
+
+	return func(underlyingValue reflect.Value) interface{} {
+		return &splitScanner{column: column, fs: fs, converts: converts, underlyingValue: underlyingValue}
+	}
+}
+
+type splitScanner struct {
+	column          string
+	fs              fieldSplit
+	converts        []converter
+	underlyingValue reflect.Value
+}
+
+func (ss *splitScanner) Scan(src interface{}) (err error) {
+	defer recoverColumnPanic(ss.column, &err)
+
+	if src == nil {
+		for _, accessor := range ss.fs.accessors {
+			field := ss.underlyingValue.FieldByIndex(accessor.fieldIndex)
+			field.Set(reflect.Zero(field.Type()))
+		}
+		return nil
+	}
+
+	values, err := ss.fs.split(src)
+	if err != nil {
+		return fmt.Errorf("can't split column/alias %s: %w", ss.column, err)
+	}
+	if len(values) != len(ss.fs.accessors) {
+		return fmt.Errorf("column/alias %s is split into %d values, but %d fields receive them", ss.column, len(values), len(ss.fs.accessors))
+	}
+	for i, accessor := range ss.fs.accessors {
+		scanner := &fieldScanner{column: ss.column, field: ss.underlyingValue.FieldByIndex(accessor.fieldIndex), convert: ss.converts[i]}
+		if err := scanner.Scan(values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
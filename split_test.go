@@ -0,0 +1,77 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func splitDimensions(src interface{}) ([]interface{}, error) {
+	parts := strings.Split(asString(src), "x")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("dimensions expected in WxH form, received: %s", asString(src))
+	}
+	return []interface{}{parts[0], parts[1]}, nil
+}
+
+func TestRegisterSplitter(t *testing.T) {
+	RegisterSplitter("wxh", splitDimensions)
+	defer RegisterSplitter("wxh", nil)
+
+	type box struct {
+		Id     int
+		Width  int  `db_column:"col2,split=wxh"`
+		Height *int `db_column:"col2,split=wxh"`
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', '20x30'), (2, 'b', NULL)",
+		"SELECT id, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var boxes []box
+	if err := Propagate(&boxes, rows); err != nil {
+		t.Fatal(err)
+	}
+	height := 30
+	exp := []box{{Id: 1, Width: 20, Height: &height}, {Id: 2}}
+	if !reflect.DeepEqual(boxes, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, boxes)
+	}
+	if problems := Validate(reflect.TypeOf(box{})); len(problems) != 0 {
+		t.Errorf("no problems expected, actual: %v", problems)
+	}
+}
+
+func TestMappingSplitter(t *testing.T) {
+	type box struct {
+		Id     int
+		Width  int
+		Height int
+	}
+	mapping, err := For[box]().Column("col2").Fields("Width", "Height").Splitter(splitDimensions).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', '20x30')",
+		"SELECT id, col2 FROM propagation",
+	)
+	defer release()
+
+	var boxes []box
+	if err := Propagate(&boxes, rows, WithMapping(mapping)); err != nil {
+		t.Fatal(err)
+	}
+	exp := []box{{Id: 1, Width: 20, Height: 30}}
+	if !reflect.DeepEqual(boxes, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, boxes)
+	}
+
+	if _, err := For[box]().Column("col2").Fields("Width", "Height").Build(); err == nil {
+		t.Error("error expected for several fields without splitter")
+	}
+}
@@ -0,0 +1,60 @@
+// +build sqlite
+
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSQLiteTypeAffinity(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, '42', '2020-01-02 03:04:05')",
+		`SELECT id, col1, col2, datetime(0, 'unixepoch') AS created, 86400 AS updated,
+			julianday('2020-01-02') AS checked, id * 1.5 AS ratio
+		FROM propagation`,
+	)
+	defer release()
+
+	type valStruct struct {
+		Id      int
+		Col1    int
+		Col2    time.Time
+		Created time.Time
+		Updated *time.Time
+		Checked time.Time
+		Ratio   float64
+	}
+	var results []valStruct
+	if err := Propagate(&results, rows, WithTypeAffinity()); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := time.Unix(86400, 0).UTC()
+	exp := []valStruct{{
+		Id:      1,
+		Col1:    42,
+		Col2:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Created: time.Unix(0, 0).UTC(),
+		Updated: &updated,
+		Checked: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Ratio:   1.5,
+	}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, results)
+	}
+}
+
+func TestSQLiteTypeAffinityMalformedTime(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'yesterday')",
+		"SELECT col1 FROM propagation",
+	)
+	defer release()
+
+	var times []time.Time
+	if err := Propagate(&times, rows, WithTypeAffinity()); err == nil {
+		t.Error("error expected for malformed time")
+	}
+}
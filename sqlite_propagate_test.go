@@ -0,0 +1,29 @@
+// +build sqlite
+
+package rowconv
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	sqlite = "sqlite3"
+)
+
+func driverName() string {
+	return sqlite
+}
+
+func dataSourceURL() string {
+	return "file:" + schema + "?mode=memory&cache=shared&_loc=UTC"
+}
+
+func ddlCreateTestTempTable() string {
+	return `
+CREATE TEMPORARY TABLE IF NOT EXISTS propagation(
+	id DECIMAL(6,0) PRIMARY KEY,
+	col1 VARCHAR(20) NOT NULL,
+	col2 VARCHAR(10),
+	col3 DATETIME
+)`
+}
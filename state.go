@@ -0,0 +1,184 @@
+package rowconv
+
+import (
+	"net/netip"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// state holds the configuration, registrations and compiled mappers of Mapper,
+// so mappers of different libraries embedded into the same binary don't affect each other, see Default
+type state struct {
+	columnTypeCheck   atomic.Value
+	columnAmountCheck atomic.Value
+	structPooling     atomic.Value
+	tagFallback       atomic.Value
+	definitionsLimit  atomic.Value
+	logger            atomic.Value
+	coverage          atomic.Value
+	moneyFormat       atomic.Value
+
+	scanDefinitions     *scanDefinitionsManager
+	columnarDefinitions *scanDefinitionsManager
+	structProviders     *structProvideManager
+	structPools         *structPoolManager
+	plans               *planManager
+
+	smallestStructDecompositions struct {
+		set map[reflect.Type]struct{}
+		sync.RWMutex
+	}
+	databaseTypeConverters struct {
+		byName map[string]func(src interface{}) (interface{}, error)
+		sync.RWMutex
+	}
+	scanners struct {
+		byName map[string]func(src interface{}) (interface{}, error)
+		sync.RWMutex
+	}
+	converters struct {
+		list []Converter
+		sync.RWMutex
+	}
+	splitters struct {
+		byName map[string]func(src interface{}) ([]interface{}, error)
+		sync.RWMutex
+	}
+	combiners struct {
+		byType map[reflect.Type]constructor
+		sync.RWMutex
+	}
+	constructors struct {
+		byType map[reflect.Type]constructor
+		sync.RWMutex
+	}
+	enumMembers struct {
+		byType map[reflect.Type][]string
+		sync.RWMutex
+	}
+	nullPolicies struct {
+		byType map[reflect.Type]NullPolicy
+		sync.RWMutex
+	}
+	mappings struct {
+		byType map[reflect.Type]map[string]string
+		sync.RWMutex
+	}
+	tables struct {
+		byType map[reflect.Type]string
+		sync.RWMutex
+	}
+	fieldsAccessors struct {
+		byKey map[fieldsAccessorsKey]map[string]fieldAccessor
+		sync.RWMutex
+	}
+}
+
+func newState() *state {
+	st := &state{
+		scanDefinitions:     &scanDefinitionsManager{compile: createScanDefinition},
+		columnarDefinitions: &scanDefinitionsManager{compile: createColumnarScanDefinition},
+		structPools:         &structPoolManager{},
+	}
+	st.structProviders = newStructProvideManager(st)
+	st.plans = &planManager{st: st}
+	st.resetConfig()
+	st.resetCaches()
+	return st
+}
+
+// resetConfig restores the default configuration and removes all registrations
+func (st *state) resetConfig() {
+	st.columnTypeCheck.Store(false)
+	st.columnAmountCheck.Store(false)
+	st.structPooling.Store(false)
+	st.tagFallback.Store(false)
+	st.definitionsLimit.Store(0)
+	st.logger.Store(loggerHolder{})
+	st.coverage.Store(coverageHolder{})
+	st.moneyFormat.Store(postgresMoneyFormat)
+
+	st.smallestStructDecompositions.Lock()
+	st.smallestStructDecompositions.set = map[reflect.Type]struct{}{
+		reflect.TypeOf(time.Time{}):     {},
+		reflect.TypeOf(time.Location{}): {},
+		reflect.TypeOf(netip.Addr{}):    {},
+		reflect.TypeOf(netip.Prefix{}):  {},
+		reflect.TypeOf(BlobSink{}):      {},
+	}
+	st.smallestStructDecompositions.Unlock()
+
+	st.databaseTypeConverters.Lock()
+	st.databaseTypeConverters.byName = map[string]func(src interface{}) (interface{}, error){}
+	st.databaseTypeConverters.Unlock()
+
+	st.scanners.Lock()
+	st.scanners.byName = map[string]func(src interface{}) (interface{}, error){}
+	st.scanners.Unlock()
+
+	st.converters.Lock()
+	st.converters.list = nil
+	st.converters.Unlock()
+
+	st.splitters.Lock()
+	st.splitters.byName = map[string]func(src interface{}) ([]interface{}, error){}
+	st.splitters.Unlock()
+
+	st.combiners.Lock()
+	st.combiners.byType = map[reflect.Type]constructor{}
+	st.combiners.Unlock()
+
+	st.constructors.Lock()
+	st.constructors.byType = map[reflect.Type]constructor{}
+	st.constructors.Unlock()
+
+	st.enumMembers.Lock()
+	st.enumMembers.byType = map[reflect.Type][]string{}
+	st.enumMembers.Unlock()
+
+	st.nullPolicies.Lock()
+	st.nullPolicies.byType = map[reflect.Type]NullPolicy{}
+	st.nullPolicies.Unlock()
+
+	st.mappings.Lock()
+	st.mappings.byType = map[reflect.Type]map[string]string{}
+	st.mappings.Unlock()
+
+	st.tables.Lock()
+	st.tables.byType = map[reflect.Type]string{}
+	st.tables.Unlock()
+}
+
+// resetCaches drops compiled mappers, inspected structs, pooled structs and recorded plans
+func (st *state) resetCaches() {
+	st.resetFieldsAccessors()
+
+	for _, sdm := range []*scanDefinitionsManager{st.scanDefinitions, st.columnarDefinitions} {
+		sdm.Lock()
+		sdm.byKey = map[definitionKey]*definitionsLRU{}
+		sdm.Unlock()
+	}
+
+	st.structProviders.Lock()
+	st.structProviders.byType = map[reflect.Type]structProvider{}
+	st.structProviders.initializers = map[reflect.Type]structInitializer{}
+	st.structProviders.Unlock()
+
+	st.structPools.Lock()
+	st.structPools.byType = map[reflect.Type]*sync.Pool{}
+	st.structPools.Unlock()
+
+	st.plans.Lock()
+	st.plans.byKey = map[string]plan{}
+	st.plans.Unlock()
+}
+
+// resetFieldsAccessors drops the accessors of the inspected structs, so they are created again with the changed
+// mapping of the fields to the columns, e.g. by RegisterMapping
+func (st *state) resetFieldsAccessors() {
+	st.fieldsAccessors.Lock()
+	st.fieldsAccessors.byKey = map[fieldsAccessorsKey]map[string]fieldAccessor{}
+	st.fieldsAccessors.Unlock()
+}
@@ -0,0 +1,136 @@
+package rowconv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RegisterTable registers the table of struct T for structs that can't be tagged, e.g. third-party or generated ones:
+// `RegisterTable[User]("users")`. The registered table takes precedence over the `db_table` tag of T.
+// Registration of the empty table removes it.
+func RegisterTable[T any](table string) error {
+	return RegisterTableOn[T](Default(), table)
+}
+
+// RegisterTableOn is the same as RegisterTable for the mapper m
+func RegisterTableOn[T any](m *Mapper, table string) error {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("table can be registered only for struct types, received: %v", structType)
+	}
+
+	m.state.tables.Lock()
+	if table == "" {
+		delete(m.state.tables.byType, structType)
+	} else {
+		m.state.tables.byType[structType] = table
+	}
+	m.state.tables.Unlock()
+	return nil
+}
+
+// isTableField returns true if the field declares the table of the struct with `db_table` tag,
+// e.g. `_ struct{} db_table:"users"`, such field is not mapped to a column
+func isTableField(field reflect.StructField) bool {
+	_, found := field.Tag.Lookup(dbTable)
+	return found
+}
+
+// tableOf returns the table of the struct registered with RegisterTable or declared with `db_table` tag of its field
+func (st *state) tableOf(structType reflect.Type) (string, error) {
+	st.tables.RLock()
+	table, found := st.tables.byType[structType]
+	st.tables.RUnlock()
+	if found {
+		return table, nil
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		if table := structType.Field(i).Tag.Get(dbTable); table != "" {
+			return table, nil
+		}
+	}
+	return "", fmt.Errorf("%v has no table: it is neither registered nor declared with %s tag", structType, dbTable)
+}
+
+// SelectAll selects all rows of the table of the struct contained in dst and propagates them into dst the same way as Select.
+// The table is registered with RegisterTable or declared with `db_table` tag of any field of the struct, usually blank one:
+//
+//	type User struct {
+//		_    struct{} `db_table:"users"`
+//		ID   int      `db_column:"id"`
+//		Name string
+//	}
+//
+// Selected columns are the columns/aliases of the fields of the struct, the same as written by BuildInsert.
+func SelectAll(ctx context.Context, q Queryer, dst interface{}) error {
+	return Default().SelectAll(ctx, q, dst)
+}
+
+// SelectAll is the same as SelectAll of the package, the table and the columns are taken with the mapper
+func (m *Mapper) SelectAll(ctx context.Context, q Queryer, dst interface{}) error {
+	return m.SelectWhere(ctx, q, dst, "")
+}
+
+// SelectWhere selects rows of the table of the struct contained in dst that satisfy the condition with args,
+// e.g. `SelectWhere(ctx, db, &users, "status = ?", status)`, and propagates them into dst the same way as SelectAll.
+// The condition is added to the query as is, so its placeholders must be in the style of the driver.
+// The empty condition selects all rows.
+func SelectWhere(ctx context.Context, q Queryer, dst interface{}, condition string, args ...interface{}) error {
+	return Default().SelectWhere(ctx, q, dst, condition, args...)
+}
+
+// SelectWhere is the same as SelectWhere of the package, the table and the columns are taken with the mapper
+func (m *Mapper) SelectWhere(ctx context.Context, q Queryer, dst interface{}, condition string, args ...interface{}) error {
+	query, err := m.state.buildSelect(dst, condition)
+	if err != nil {
+		return err
+	}
+	return m.Select(ctx, q, dst, query, args...)
+}
+
+// buildSelect generates SELECT of the columns of the struct contained in dst from its table
+func (st *state) buildSelect(dst interface{}, condition string) (string, error) {
+	structType, err := selectedStructType(dst)
+	if err != nil {
+		return "", err
+	}
+	table, err := st.tableOf(structType)
+	if err != nil {
+		return "", err
+	}
+	accessors, err := st.insertAccessors(structType)
+	if err != nil {
+		return "", err
+	}
+	return selectQuery(table, accessors, condition), nil
+}
+
+// selectQuery generates SELECT of the columns of the accessors from the table with optional condition
+func selectQuery(table string, accessors []fieldAccessor, condition string) string {
+	query := "SELECT " + accessorColumns(accessors) + " FROM " + table
+	if condition != "" {
+		query += " WHERE " + condition
+	}
+	return query
+}
+
+// selectedStructType returns the struct contained in dst, e.g. User of *[]*User or of TypedAppender of User values
+func selectedStructType(dst interface{}) (reflect.Type, error) {
+	dstType := reflect.TypeOf(dst)
+	if appender, ok := dst.(TypedAppender); ok {
+		dstType = appender.ElementType()
+	}
+	for dstType != nil {
+		switch dstType.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Chan, reflect.Map:
+			dstType = dstType.Elem()
+		case reflect.Struct:
+			return dstType, nil
+		default:
+			return nil, fmt.Errorf("struct is expected to be contained in %T", dst)
+		}
+	}
+	return nil, fmt.Errorf("struct is expected to be contained in %T", dst)
+}
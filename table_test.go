@@ -0,0 +1,87 @@
+package rowconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type tableUser struct {
+	_    struct{} `db_table:"propagation"`
+	Id   int
+	Name string  `db_column:"col1"`
+	Note *string `db_column:"col2"`
+}
+
+func TestSelectWhere(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, ddlCreateTestTempTable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'x'), (2, 'b', NULL), (3, 'c', NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	var all []tableUser
+	if err := SelectAll(ctx, tx, &all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("unexpeted results of propagation: %+v", all)
+	}
+
+	condition := "id > ? AND col2 IS NULL"
+	if driverName() == "postgres" {
+		condition = "id > $1 AND col2 IS NULL"
+	}
+	var users []*tableUser
+	if err := SelectWhere(ctx, tx, &users, condition+" ORDER BY id", 2); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []*tableUser{{Id: 3, Name: "c"}}; !reflect.DeepEqual(users, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, users)
+	}
+}
+
+func TestBuildSelect(t *testing.T) {
+	var users []tableUser
+	query, err := Default().state.buildSelect(&users, "id = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "SELECT id, col1, col2 FROM propagation WHERE id = ?"; query != exp {
+		t.Errorf("unexpected query: expected %s, actual %s", exp, query)
+	}
+
+	type untagged struct{ Id int }
+	if _, err := Default().state.buildSelect(&[]untagged{}, ""); err == nil {
+		t.Error("error expected for struct without table")
+	}
+	if err := RegisterTable[untagged]("untagged"); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterTable[untagged]("")
+	query, err = Default().state.buildSelect(make(chan untagged), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "SELECT id FROM untagged"; query != exp {
+		t.Errorf("unexpected query: expected %s, actual %s", exp, query)
+	}
+
+	if err := RegisterTable[int]("ints"); err == nil {
+		t.Error("error expected for registration of non-struct type")
+	}
+	if _, err := Default().state.buildSelect(&[]int{}, ""); err == nil {
+		t.Error("error expected for destination without struct")
+	}
+}
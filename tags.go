@@ -0,0 +1,82 @@
+package rowconv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithTagKeys maps the fields by the tags of keys instead of `db_column`: the first of the keys the field is tagged with
+// is used, fields tagged with none of them are mapped by their names. It lets one binary map the same structs against
+// different schemas, e.g. the reporting replica with legacy column names, with a Mapper for each of them:
+//
+//	type User struct {
+//		ID   int    `db_column:"id" legacy_column:"usr_id"`
+//		Name string `db_column:"name" legacy_column:"usr_name"`
+//	}
+//
+//	replicaMapper := rowconv.NewMapper(rowconv.WithTagKeys("legacy_column", "db_column"))
+//
+// Keys must not contain commas. Only the names are taken from `db` and `json` tags, see TagFallback.
+// The mapping registered with RegisterMapping takes precedence over the tags.
+func WithTagKeys(keys ...string) Option {
+	return func(o *options) {
+		o.compile.tagKeys = strings.Join(keys, ",")
+	}
+}
+
+// fallbackTagKeys are the tags of other libraries consulted for the fields without `db_column` tag, see TagFallback
+var fallbackTagKeys = []string{"db", "json"}
+
+// TagFallback configures mapper to map the fields without `db_column` tag by their `db` tag and then by their `json` tag
+// before their lower-cased names, so structs defined for serialization are mapped to sensibly named columns
+// without duplication of the tags. Only the names are taken from these tags, e.g. "user_name" of
+// `json:"user_name,omitempty"`, the tags without the name or with "-" name are skipped.
+// It should be configured before the first propagation, as compiled mappers are cached.
+func TagFallback(enabled bool) {
+	Default().TagFallback(enabled)
+}
+
+// TagFallback is the same as TagFallback of the package for the mapper
+func (m *Mapper) TagFallback(enabled bool) {
+	m.state.tagFallback.Store(enabled)
+	m.state.resetFieldsAccessors()
+}
+
+func (st *state) tagFallbackEnabled() bool {
+	return st.tagFallback.Load().(bool)
+}
+
+// lookupTag returns the value of the first of comma-separated tagKeys the field is tagged with,
+// `db_column` with fallback tags if enabled are consulted if tagKeys are empty
+func (st *state) lookupTag(field reflect.StructField, tagKeys string) string {
+	keys := []string{dbColumn}
+	if tagKeys != "" {
+		keys = strings.Split(tagKeys, ",")
+	} else if st.tagFallbackEnabled() {
+		keys = append(keys, fallbackTagKeys...)
+	}
+
+	for _, key := range keys {
+		tag, found := field.Tag.Lookup(key)
+		if !found {
+			continue
+		}
+		if !isFallbackTagKey(key) {
+			return tag
+		}
+		// options of the fallback tags belong to other libraries
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+func isFallbackTagKey(key string) bool {
+	for _, fallbackKey := range fallbackTagKeys {
+		if key == fallbackKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithTagKeys(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Name string `db_column:"col1" legacy_column:"col2"`
+	}
+	propagate := func(mapper *Mapper) []valStruct {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b')",
+			"SELECT id, col1, col2 FROM propagation",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		if err := mapper.Propagate(&valStructs, rows); err != nil {
+			t.Fatal(err)
+		}
+		return valStructs
+	}
+
+	if exp, act := []valStruct{{Id: 1, Name: "a"}}, propagate(NewMapper()); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpeted results of propagation by default tag: expected %+v, actual %+v", exp, act)
+	}
+	replica := NewMapper(WithTagKeys("legacy_column", "db_column"))
+	if exp, act := []valStruct{{Id: 1, Name: "b"}}, propagate(replica); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpeted results of propagation by legacy tag: expected %+v, actual %+v", exp, act)
+	}
+	fallback := NewMapper(WithTagKeys("missing_column", "db_column"))
+	if exp, act := []valStruct{{Id: 1, Name: "a"}}, propagate(fallback); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpeted results of propagation by fallback tag: expected %+v, actual %+v", exp, act)
+	}
+}
+
+func TestTagFallback(t *testing.T) {
+	type valStruct struct {
+		Id     int    `db_column:"pk" db:"id"`
+		Name   string `json:"user_name,omitempty"`
+		Email  string `db:"mail" json:"email"`
+		Secret string `json:"-"`
+		Note   string `json:",omitempty"`
+	}
+	columns := func() []string {
+		mappings, err := Mappings(reflect.TypeOf(valStruct{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var columns []string
+		for _, mapping := range mappings {
+			columns = append(columns, mapping.Column)
+		}
+		return columns
+	}
+
+	if exp, act := []string{"pk", "name", "email", "secret", "note"}, columns(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected columns without fallback: expected %v, actual %v", exp, act)
+	}
+	TagFallback(true)
+	defer TagFallback(false)
+	if exp, act := []string{"pk", "user_name", "mail", "secret", "note"}, columns(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected columns with fallback: expected %v, actual %v", exp, act)
+	}
+}
@@ -0,0 +1,94 @@
+package rowconv
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Pair is the destination of two-column queries that doesn't need a struct declared for it,
+// First and Second are populated by the first and the second columns of the rows regardless of their names:
+//
+//	var names []rowconv.Pair[int64, string]
+//	err := rowconv.Propagate(&names, rows) // SELECT id, name FROM users
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+func (Pair[A, B]) tupleArity() int { return 2 }
+
+// Triple is the destination of three-column queries, see Pair
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+func (Triple[A, B, C]) tupleArity() int { return 3 }
+
+// tuple is implemented by the types whose fields are mapped to the columns by position
+type tuple interface {
+	tupleArity() int
+}
+
+var tupleType = reflect.TypeOf((*tuple)(nil)).Elem()
+
+func isTupleType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(tupleType)
+}
+
+// tupleScanner creates row scanner that populates fields of the tuple with the columns at the same positions,
+// forType is the type of the tuple or reference to it. Columns beyond the fields of the tuple are skipped
+// unless StrictColumnAmountCheck is enabled.
+func tupleScanner(forType reflect.Type, columnTypes []columnType, copts compileOptions) (func(unmappedColumnFunc) rowScanner, error) {
+	structType := derefType(forType)
+	if len(columnTypes) < structType.NumField() {
+		return nil, fmt.Errorf("%v requires %d columns, received: %d", structType, structType.NumField(), len(columnTypes))
+	}
+
+	holderSuppliers := make([]holderSupplier, len(columnTypes))
+	var unmapped []int
+	for i, columnType := range columnTypes {
+		if i >= structType.NumField() {
+			if copts.columnAmountCheck {
+				return nil, errors.New("no mapping exists for column/alias: " + columnType.Name())
+			}
+			holderSuppliers[i] = copts.holderSkip()
+			unmapped = append(unmapped, i)
+			continue
+		}
+
+		field := structType.Field(i)
+		if copts.columnTypeCheck && columnType.ScanType() != field.Type {
+			return nil, fmt.Errorf("value for column/alias: %v can't be stored into the type: %v; required type: %v", columnType.Name(), field.Type, columnType.ScanType())
+		}
+		if convert := copts.columnConverter(columnType, field.Type, nil); convert != nil {
+			holderSuppliers[i] = holderConvertedByFieldIndexPath(columnType.Name(), field.Index, convert)
+		} else {
+			holderSuppliers[i] = holderByFieldIndexPath(field.Index)
+		}
+	}
+
+	return func(onUnmapped unmappedColumnFunc) rowScanner {
+		holderSuppliers := withUnmappedColumns(holderSuppliers, unmapped, columnTypes, onUnmapped)
+		return func(rows *sql.Rows) (reflect.Value, error) {
+			v := reflect.New(structType).Elem()
+			dest := make([]interface{}, len(holderSuppliers))
+			for i, holderSupplier := range holderSuppliers {
+				dest[i] = holderSupplier(v)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return reflect.Value{}, err
+			}
+
+			for v.Type() != forType {
+				ref := reflect.New(v.Type())
+				ref.Elem().Set(v)
+				v = ref
+			}
+			return v, nil
+		}
+	}, nil
+}
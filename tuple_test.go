@@ -0,0 +1,60 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropagateTuples(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var pairs []*Pair[int64, string]
+	if err := Propagate(&pairs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*Pair[int64, string]{{First: 1, Second: "a"}, {First: 2, Second: "c"}}
+	if !reflect.DeepEqual(pairs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, pairs)
+	}
+
+	rows, release = queryPropagation(t,
+		"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'b'), (2, 'c', NULL)",
+		"SELECT id, col1, col2 FROM propagation ORDER BY id",
+	)
+	defer release()
+
+	var triples []Triple[int64, string, *string]
+	if err := Propagate(&triples, rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(triples) != 2 || *triples[0].Third != "b" || triples[1].Third != nil {
+		t.Errorf("unexpeted results of propagation: %+v", triples)
+	}
+}
+
+func TestPropagateTuplesColumnsAmount(t *testing.T) {
+	rows, release := queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id FROM propagation",
+	)
+	defer release()
+
+	var pairs []Pair[int64, string]
+	if err := Propagate(&pairs, rows); err == nil {
+		t.Error("error expected for the missing column")
+	}
+
+	rows, release = queryPropagation(t,
+		"INSERT INTO propagation(id, col1) VALUES (1, 'a')",
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	if err := Propagate(&pairs, rows, WithStrictColumnAmountCheck(true)); err == nil {
+		t.Error("error expected for the unmapped column")
+	}
+}
@@ -0,0 +1,46 @@
+package rowconv
+
+import "reflect"
+
+// unmappedColumnFunc receives the values of the columns without mapping, see WithUnmappedColumn
+type unmappedColumnFunc func(column string, value interface{}) error
+
+// WithUnmappedColumn passes the value of each column without mapping to the field of the element, as returned by
+// database driver, to fn instead of skipping it, so unexpected columns can be logged, collected or rejected by the call.
+// The error of fn stops the propagation and can be matched with errors.Is. The value may refer to the buffer of
+// database driver, so it must be copied to be retained after fn returns. fn is not called for the columns rejected
+// by StrictColumnAmountCheck, nor for the destinations of columnar and basic types.
+func WithUnmappedColumn(fn func(column string, value interface{}) error) Option {
+	return func(o *options) {
+		o.unmappedColumn = fn
+	}
+}
+
+// unmappedColumnScanner is a scan destination that passes the value of the column to the function
+type unmappedColumnScanner struct {
+	column     string
+	onUnmapped unmappedColumnFunc
+}
+
+func (ucs *unmappedColumnScanner) Scan(src interface{}) error {
+	return ucs.onUnmapped(ucs.column, src)
+}
+
+// holderUnmappedColumn passes the value of the column to onUnmapped, the scanner is shared by all rows
+func holderUnmappedColumn(column string, onUnmapped unmappedColumnFunc) holderSupplier {
+	scanner := &unmappedColumnScanner{column: column, onUnmapped: onUnmapped}
+	return func(underlyingValue reflect.Value) interface{} { return scanner }
+}
+
+// withUnmappedColumns returns holderSuppliers with the suppliers of the unmapped columns at the positions replaced
+// by the ones passing the values to onUnmapped, holderSuppliers are returned as is if there is no onUnmapped
+func withUnmappedColumns(holderSuppliers []holderSupplier, unmapped []int, columnTypes []columnType, onUnmapped unmappedColumnFunc) []holderSupplier {
+	if onUnmapped == nil || len(unmapped) == 0 {
+		return holderSuppliers
+	}
+	holderSuppliers = append([]holderSupplier(nil), holderSuppliers...)
+	for _, position := range unmapped {
+		holderSuppliers[position] = holderUnmappedColumn(columnTypes[position].Name(), onUnmapped)
+	}
+	return holderSuppliers
+}
@@ -0,0 +1,54 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPropagateWithUnmappedColumn(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Col1 string
+	}
+	propagate := func(opts ...Option) ([]valStruct, error) {
+		rows, release := queryPropagation(t,
+			"INSERT INTO propagation(id, col1, col2) VALUES (1, 'a', 'x'), (2, 'b', NULL)",
+			"SELECT id, col1, col2 FROM propagation ORDER BY id",
+		)
+		defer release()
+
+		var valStructs []valStruct
+		err := Propagate(&valStructs, rows, opts...)
+		return valStructs, err
+	}
+
+	var unmapped []interface{}
+	valStructs, err := propagate(WithUnmappedColumn(func(column string, value interface{}) error {
+		if column != "col2" {
+			t.Errorf("unexpected unmapped column: %s", column)
+		}
+		if raw, ok := value.([]byte); ok {
+			value = string(raw)
+		}
+		unmapped = append(unmapped, value)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []valStruct{{Id: 1, Col1: "a"}, {Id: 2, Col1: "b"}}; !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+	if exp := []interface{}{"x", nil}; !reflect.DeepEqual(unmapped, exp) {
+		t.Errorf("unexpected values of unmapped column: expected %v, actual %v", exp, unmapped)
+	}
+
+	rejected := errors.New("rejected")
+	if _, err := propagate(WithUnmappedColumn(func(string, interface{}) error { return rejected })); !errors.Is(err, rejected) {
+		t.Errorf("error of the callback expected, actual: %v", err)
+	}
+	if _, err := propagate(); err != nil {
+		t.Errorf("unmapped column expected to be skipped without the callback, actual: %v", err)
+	}
+}
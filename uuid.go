@@ -0,0 +1,55 @@
+package rowconv
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var errUUIDFormat = errors.New("invalid UUID format")
+
+// convertUUID stores 16-byte binary UUID, e.g. of BINARY(16) column of MySQL, into the string field
+// in canonical form: xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx. Textual UUIDs are stored as is.
+// It is used for fields with `uuid` tag option: `db_column:"id,uuid"`.
+func convertUUID(src interface{}, dst reflect.Value) error {
+	raw, ok := src.([]byte)
+	if !ok || len(raw) != 16 {
+		if src != nil {
+			if _, err := UUIDBinary(asString(src)); err != nil {
+				return &ParseError{Value: asString(src), Type: dst.Type(), Err: err}
+			}
+		}
+		return convertDefault(src, dst)
+	}
+
+	return convertDefault(formatUUID(raw), dst)
+}
+
+// formatUUID formats 16-byte UUID in canonical form
+func formatUUID(raw []byte) string {
+	var canonical [36]byte
+	hex.Encode(canonical[0:8], raw[0:4])
+	canonical[8] = '-'
+	hex.Encode(canonical[9:13], raw[4:6])
+	canonical[13] = '-'
+	hex.Encode(canonical[14:18], raw[6:8])
+	canonical[18] = '-'
+	hex.Encode(canonical[19:23], raw[8:10])
+	canonical[23] = '-'
+	hex.Encode(canonical[24:], raw[10:])
+	return string(canonical[:])
+}
+
+// UUIDBinary converts UUID in canonical form into 16 bytes, so it can be passed as an argument
+// of the query to the column of BINARY(16) type.
+func UUIDBinary(uuid string) ([]byte, error) {
+	if len(uuid) != 36 || uuid[8] != '-' || uuid[13] != '-' || uuid[18] != '-' || uuid[23] != '-' {
+		return nil, errUUIDFormat
+	}
+	raw, err := hex.DecodeString(strings.ReplaceAll(uuid, "-", ""))
+	if err != nil {
+		return nil, errUUIDFormat
+	}
+	return raw, nil
+}
@@ -0,0 +1,40 @@
+package rowconv
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestConvertUUID(t *testing.T) {
+	const canonical = "00112233-4455-6677-8899-aabbccddeeff"
+	raw := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+
+	copts := compileOptions{state: Default().state}
+	convert := copts.converter(reflect.TypeOf(StringRef("")), []string{"uuid"})
+	for _, src := range []interface{}{raw, []byte(canonical), canonical} {
+		var act *string
+		if err := convert(src, reflect.ValueOf(&act).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if act == nil || *act != canonical {
+			t.Errorf("unexpected UUID of %v: expected %s, actual %v", src, canonical, act)
+		}
+	}
+
+	var act string
+	if err := convert([]byte("short"), reflect.ValueOf(&act).Elem()); err == nil {
+		t.Error("error expected for value that is not UUID")
+	}
+
+	back, err := UUIDBinary(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back, raw) {
+		t.Errorf("unexpected binary UUID: expected %x, actual %x", raw, back)
+	}
+	if _, err := UUIDBinary("00112233445566778899aabbccddeeff"); err == nil {
+		t.Error("error expected for UUID not in canonical form")
+	}
+}
@@ -0,0 +1,172 @@
+package rowconv
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Problem is an issue of the mapping of the struct to the columns found by Validate
+type Problem struct {
+	// FieldPath is a sequence of names of the fields from the root struct to the field with the problem,
+	// it is empty if the problem concerns the type itself
+	FieldPath []string
+	// Column is the name of the column/alias the field is mapped to
+	Column string
+	// Description explains the problem
+	Description string
+}
+
+func (p Problem) String() string {
+	if len(p.FieldPath) == 0 {
+		return p.Description
+	}
+	return strings.Join(p.FieldPath, ".") + ": " + p.Description
+}
+
+// Validate reports all issues of the mapping of the struct t (or reference to it) that can be found without a database:
+// fields of unsupported kinds, fields that resolve to the same column/alias, fields that can't be set and recursive types.
+// An empty result means the type is valid, so it can be asserted in unit tests.
+func Validate(t reflect.Type) []Problem {
+	return Default().Validate(t)
+}
+
+// Validate is the same as Validate of the package for the mapper
+func (m *Mapper) Validate(t reflect.Type) []Problem {
+	structType, _, err := unwrapPtrStructType(t)
+	if err != nil {
+		return []Problem{{Description: err.Error()}}
+	}
+
+	v := &validator{aliasToPaths: map[string][][]string{}, st: m.state}
+	v.validateStruct(structType, nil, map[reflect.Type]bool{})
+
+	aliases := make([]string, 0, len(v.aliasToPaths))
+	for alias, paths := range v.aliasToPaths {
+		if len(paths) > 1 {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		paths := v.aliasToPaths[alias]
+		names := make([]string, len(paths))
+		for i, path := range paths {
+			names[i] = strings.Join(path, ".")
+		}
+		for _, path := range paths {
+			v.report(path, alias, "column/alias is mapped to more than one field: "+strings.Join(names, ", "))
+		}
+	}
+	return v.problems
+}
+
+type validator struct {
+	aliasToPaths map[string][][]string
+	problems     []Problem
+	// st is the state of the mapper the struct is validated with
+	st *state
+}
+
+func (v *validator) report(path []string, column, description string) {
+	v.problems = append(v.problems, Problem{FieldPath: path, Column: column, Description: description})
+}
+
+// validateStruct inspects fields of structType, ancestors are struct types on the way from the root to it
+func (v *validator) validateStruct(structType reflect.Type, path []string, ancestors map[reflect.Type]bool) {
+	st := v.st
+	ancestors[structType] = true
+	defer delete(ancestors, structType)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldPath := append(append([]string(nil), path...), field.Name)
+		if isTableField(field) {
+			continue
+		}
+		if isRowNumberField(field) {
+			switch {
+			case len(path) != 0:
+				v.report(fieldPath, "", "row number field must be a field of the root struct")
+			case field.PkgPath != "" || !isRowNumberKind(field.Type.Kind()):
+				v.report(fieldPath, "", "row number field must be an exported integer field")
+			}
+			continue
+		}
+		columnAlias, options := st.fieldColumnTag(structType, field)
+
+		if st.isCombinedType(field.Type) {
+			if field.PkgPath != "" {
+				v.report(fieldPath, "", "unexported field can't be set")
+			}
+			continue
+		}
+
+		nestedType := field.Type
+		for nestedType.Kind() == reflect.Ptr {
+			nestedType = nestedType.Elem()
+		}
+		if nestedType.Kind() == reflect.Struct && !st.isSmallestStructDecomposition(nestedType) && !isWholeValueField(options) && !isSplitField(options) {
+			switch {
+			case ancestors[nestedType]:
+				v.report(fieldPath, columnAlias, "recursive type: "+nestedType.String())
+			case field.PkgPath != "" && (!field.Anonymous || field.Type.Kind() == reflect.Ptr):
+				v.report(fieldPath, columnAlias, "unexported field can't be set")
+			default:
+				v.validateStruct(nestedType, fieldPath, ancestors)
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			// unexported fields of the root struct are populated with setters
+			if _, found := setterOf(structType, columnAlias); len(path) == 0 && found {
+				continue
+			}
+			v.report(fieldPath, columnAlias, "unexported field can't be set")
+			continue
+		}
+		if !isSupportedFieldType(field.Type) {
+			v.report(fieldPath, columnAlias, fmt.Sprintf("unsupported type: %v", field.Type))
+			continue
+		}
+		if _, found := layoutOption(options); found && derefType(field.Type) != timeType {
+			v.report(fieldPath, columnAlias, "layout is supported only for fields of time.Time type")
+		}
+		if name, found := scannerOption(options); found {
+			if _, registered := st.scannerOf(name); !registered {
+				v.report(fieldPath, columnAlias, "scanner is not registered: "+name)
+			}
+		}
+		// fields split from the same column share it
+		if name, split := splitOption(options); split {
+			if _, registered := st.splitterOf(name); !registered {
+				v.report(fieldPath, columnAlias, "splitter is not registered: "+name)
+			}
+			continue
+		}
+		v.aliasToPaths[columnAlias] = append(v.aliasToPaths[columnAlias], fieldPath)
+	}
+}
+
+// isSupportedFieldType reports if a value returned by database driver can be stored into the field of type t
+func isSupportedFieldType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		if t.Implements(scannerType) {
+			return true
+		}
+		t = t.Elem()
+	}
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128, reflect.Map, reflect.Array:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package rowconv
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	type audit struct {
+		CreatedBy string `db_column:"owner"`
+		Created   time.Time
+	}
+	type node struct {
+		Id     int
+		Parent *node
+	}
+	type valStruct struct {
+		Id      int
+		Owner   string
+		Col2    sql.NullString
+		Col3    *[]byte
+		Audit   audit
+		Tree    node
+		Tags    []string
+		Handler func()
+		hidden  string
+	}
+
+	exp := []string{
+		"Tree.Parent: recursive type: rowconv.node",
+		"Tags: unsupported type: []string",
+		"Handler: unsupported type: func()",
+		"hidden: unexported field can't be set",
+		"Id: column/alias is mapped to more than one field: Id, Tree.Id",
+		"Tree.Id: column/alias is mapped to more than one field: Id, Tree.Id",
+		"Owner: column/alias is mapped to more than one field: Owner, Audit.CreatedBy",
+		"Audit.CreatedBy: column/alias is mapped to more than one field: Owner, Audit.CreatedBy",
+	}
+	problems := Validate(reflect.TypeOf(&valStruct{}))
+	act := make([]string, len(problems))
+	for i, problem := range problems {
+		act[i] = problem.String()
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected problems: expected %q, actual %q", exp, act)
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	if problems := Validate(reflect.TypeOf(mrefStruct{})); len(problems) != 0 {
+		t.Errorf("no problems expected, actual: %v", problems)
+	}
+	if problems := Validate(reflect.TypeOf(0)); len(problems) != 1 {
+		t.Errorf("problem expected for non-struct type, actual: %v", problems)
+	}
+}
@@ -0,0 +1,90 @@
+package rowconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ExpectedColumns is the element type warmed by Warm along with the columns of the query it is propagated from
+type ExpectedColumns struct {
+	// Type is the value of the element type or reflect.Type of it
+	Type interface{}
+	// Columns are the columns of the query in the form reported by the driver,
+	// the compiled mapper is reused only if names, database type names and scan types of the rows match them
+	Columns []Column
+}
+
+// Warm compiles mappers of the element types at the start of the service, so the reflection cost of the first
+// propagation doesn't fall on the latency-critical path. Each of types is the value of the element type,
+// e.g. `User{}` or `(*User)(nil)`, reflect.Type of it, or ExpectedColumns to compile the mapper of the whole query.
+// Mappers are compiled with the default options. The first failed compilation is returned as the error.
+func Warm(types ...interface{}) error {
+	return Default().Warm(types...)
+}
+
+// Warm is the same as Warm of the package for the mapper
+func (m *Mapper) Warm(types ...interface{}) error {
+	for _, t := range types {
+		expected, withColumns := t.(ExpectedColumns)
+		if withColumns {
+			t = expected.Type
+		}
+
+		forType, ok := t.(reflect.Type)
+		if !ok {
+			forType = reflect.TypeOf(t)
+		}
+		if forType == nil {
+			return errors.New("can't warm untyped nil")
+		}
+
+		var err error
+		if withColumns {
+			err = m.warmColumns(forType, expected.Columns)
+		} else {
+			err = m.warmType(forType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warmType caches struct provider and accessors of the fields of the struct element type,
+// other types require no compilation
+func (m *Mapper) warmType(forType reflect.Type) error {
+	holderElementType, err := elementType(forType)
+	if err != nil {
+		return err
+	}
+	if m.state.isSingleBasicType(holderElementType) {
+		return nil
+	}
+
+	structType, _, err := unwrapPtrStructType(holderElementType)
+	if err != nil {
+		return fmt.Errorf("can't warm %v: %w", forType, err)
+	}
+	if _, err := m.state.createFieldsAccessors(structType); err != nil {
+		return err
+	}
+	_, err = m.state.structProviders.getOrCreateSync(holderElementType)
+	return err
+}
+
+// warmColumns caches scan definition of the element type for the columns
+func (m *Mapper) warmColumns(forType reflect.Type, columns []Column) error {
+	holderElementType, err := elementType(forType)
+	if err != nil {
+		return err
+	}
+
+	columnTypes := make([]columnType, len(columns))
+	for i, column := range columns {
+		columnTypes[i] = column.known()
+	}
+	_, err = m.state.scanDefinitions.getOrCreateSync(holderElementType, columnTypes, m.newOptions(nil).compile)
+	return err
+}
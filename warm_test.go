@@ -0,0 +1,55 @@
+package rowconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWarm(t *testing.T) {
+	type nested struct {
+		Col1 string
+	}
+	type valStruct struct {
+		Id     int
+		Nested *nested
+	}
+	columns := []Column{{Name: "id", DatabaseTypeName: "INT", ScanType: reflect.TypeOf(int64(0))}, {Name: "col1", DatabaseTypeName: "VARCHAR"}}
+	if err := Warm(valStruct{}, (*valStruct)(nil), reflect.TypeOf(valStruct{}), 0, ExpectedColumns{Type: valStruct{}, Columns: columns}); err != nil {
+		t.Fatal(err)
+	}
+
+	Default().state.structProviders.RLock()
+	_, found := Default().state.structProviders.byType[reflect.TypeOf((*valStruct)(nil))]
+	Default().state.structProviders.RUnlock()
+	if !found {
+		t.Error("struct provider is expected to be cached")
+	}
+	Default().state.fieldsAccessors.RLock()
+	accessors := Default().state.fieldsAccessors.byKey[fieldsAccessorsKey{dstType: reflect.TypeOf(valStruct{})}]
+	Default().state.fieldsAccessors.RUnlock()
+	if _, found := accessors["col1"]; !found {
+		t.Errorf("accessors of the fields are expected to be cached: %+v", accessors)
+	}
+
+	key := definitionKey{elementType: reflect.TypeOf(valStruct{}), options: newOptions(nil).compile}
+	columnTypes := []columnType{columns[0].known(), columns[1].known()}
+	Default().state.scanDefinitions.RLock()
+	entries := Default().state.scanDefinitions.byKey[key]
+	entry, found := entries.get(columnsSignature(columnTypes))
+	Default().state.scanDefinitions.RUnlock()
+	if entries.len() != 1 || !found || !reflect.DeepEqual(entry.scanDef.columnTypes, columnTypes) {
+		t.Errorf("scan definition is expected to be cached: %+v", entries)
+	}
+}
+
+func TestWarmErrors(t *testing.T) {
+	type valStruct struct {
+		Id   int
+		Name string `db_column:"id"`
+	}
+	for _, warmed := range []interface{}{nil, valStruct{}, make(chan int), ExpectedColumns{Type: valStruct{}, Columns: []Column{{Name: "id"}}}} {
+		if err := Warm(warmed); err == nil {
+			t.Errorf("error expected for %#v", warmed)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package rowconv
+
+import (
+	"encoding/xml"
+	"reflect"
+)
+
+// convertXML decodes XML value into dst with encoding/xml, NULL is stored as zero value.
+// It is used for fields with `xml` tag option: `db_column:"payload,xml"`.
+func convertXML(src interface{}, dst reflect.Value) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	raw := asBytes(src)
+	if err := xml.Unmarshal(raw, dst.Addr().Interface()); err != nil {
+		return &ParseError{Value: string(raw), Type: dst.Type(), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package rowconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type xmlOrder struct {
+	Number string   `xml:"number,attr"`
+	Items  []string `xml:"item"`
+}
+
+func TestPropagateXML(t *testing.T) {
+	rows, release := queryPropagation(t,
+		`INSERT INTO propagation(id, col1, col2) VALUES (1, '<o number="7"><item>a</item></o>', NULL)`,
+		"SELECT id, col1, col2 FROM propagation",
+	)
+	defer release()
+
+	type valStruct struct {
+		Id   int
+		Col1 xmlOrder  `db_column:"col1,xml"`
+		Col2 *xmlOrder `db_column:"col2,xml"`
+	}
+	var valStructs []valStruct
+	if err := Propagate(&valStructs, rows); err != nil {
+		t.Fatal(err)
+	}
+	exp := []valStruct{{Id: 1, Col1: xmlOrder{Number: "7", Items: []string{"a"}}}}
+	if !reflect.DeepEqual(valStructs, exp) {
+		t.Errorf("unexpeted results of propagation: expected %+v, actual %+v", exp, valStructs)
+	}
+}
+
+func TestConvertXML(t *testing.T) {
+	var order xmlOrder
+	if err := convertXML([]byte(`<o number="1"><item>a</item><item>b</item></o>`), reflect.ValueOf(&order).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := (xmlOrder{Number: "1", Items: []string{"a", "b"}}); !reflect.DeepEqual(order, exp) {
+		t.Errorf("unexpected value: expected %+v, actual %+v", exp, order)
+	}
+
+	var parseErr *ParseError
+	if err := convertXML("<o", reflect.ValueOf(&order).Elem()); !errors.As(err, &parseErr) {
+		t.Errorf("ParseError expected, actual: %v", err)
+	}
+}